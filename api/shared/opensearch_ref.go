@@ -0,0 +1,10 @@
+package shared
+
+// OpensearchRef is the reference of Opensearch cluster managed by the operator
+type OpensearchRef struct {
+
+	// Name is the Opensearch resource name
+	// The cluster must be on the same namespace
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+}
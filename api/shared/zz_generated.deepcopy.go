@@ -44,3 +44,18 @@ func (in *ImageSpec) DeepCopy() *ImageSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpensearchRef) DeepCopyInto(out *OpensearchRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpensearchRef.
+func (in *OpensearchRef) DeepCopy() *OpensearchRef {
+	if in == nil {
+		return nil
+	}
+	out := new(OpensearchRef)
+	in.DeepCopyInto(out)
+	return out
+}
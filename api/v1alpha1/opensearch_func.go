@@ -2,17 +2,23 @@ package v1alpha1
 
 import (
 	"fmt"
+	"path"
+	"sort"
 	"strings"
 
 	"github.com/disaster37/k8sbuilder"
 	"github.com/pkg/errors"
 	"github.com/thoas/go-funk"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
 	"github.com/webcenter-fr/opensearch-operator/pkg/helper"
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
@@ -22,13 +28,194 @@ import (
 func (h *Opensearch) GetNodeNames() (nodeNames []string) {
 	nodeNames = make([]string, 0)
 
-	for _, nodeGroup := range h.Spec.NodeGroups {
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
 		nodeNames = append(nodeNames, h.GetNodeGroupNodeNames(&nodeGroup)...)
 	}
 
 	return nodeNames
 }
 
+// GetActiveNodeGroups permit to get the node groups that are not yet migrated on their successor
+func (h *Opensearch) GetActiveNodeGroups() (nodeGroups []NodeGroupSpec) {
+	nodeGroups = make([]NodeGroupSpec, 0, len(h.Spec.NodeGroups))
+
+	for _, nodeGroup := range h.Spec.NodeGroups {
+		if !h.IsNodeGroupMigrated(nodeGroup.Name) {
+			nodeGroups = append(nodeGroups, nodeGroup)
+		}
+	}
+
+	return nodeGroups
+}
+
+// GetNodeGroupMigration permit to get the migration status of old node group
+// It return nil if there are no migration
+func (h *Opensearch) GetNodeGroupMigration(nodeGroupName string) *NodeGroupMigrationStatus {
+	for i, migration := range h.Status.NodeGroupMigrations {
+		if migration.NodeGroup == nodeGroupName {
+			return &h.Status.NodeGroupMigrations[i]
+		}
+	}
+
+	return nil
+}
+
+// IsNodeGroupMigrated return true if the node group is completely migrated on its successor
+func (h *Opensearch) IsNodeGroupMigrated(nodeGroupName string) bool {
+	migration := h.GetNodeGroupMigration(nodeGroupName)
+	return migration != nil && migration.Phase == NodeGroupMigrationCompleted
+}
+
+// GetSuccessorNodeGroups permit to get the node groups that are successor of an other node group
+func (h *Opensearch) GetSuccessorNodeGroups() (nodeGroups []NodeGroupSpec) {
+	nodeGroups = make([]NodeGroupSpec, 0)
+
+	for _, nodeGroup := range h.Spec.NodeGroups {
+		if nodeGroup.SuccessorOf != "" {
+			nodeGroups = append(nodeGroups, nodeGroup)
+		}
+	}
+
+	return nodeGroups
+}
+
+// IsRestoreInProgress return true if the snapshot restore is not yet completed
+// External endpoints are not created while restore is in progress
+// The restore status is only initialized on new cluster, to not restore snapshot on live data
+func (h *Opensearch) IsRestoreInProgress() bool {
+	if h.Spec.Restore == nil || h.Status.Restore == nil {
+		return false
+	}
+
+	return h.Status.Restore.Phase == RestorePending || h.Status.Restore.Phase == RestoreRunning
+}
+
+// IsRestoreIgnored return true if the restore is added on existing cluster
+func (h *Opensearch) IsRestoreIgnored() bool {
+	return h.Spec.Restore != nil && h.Status.Restore != nil && h.Status.Restore.Phase == RestoreIgnored
+}
+
+// GenerateRestoreStatus permit to init the restore status when restore is requested
+// The restore is pending on new cluster, and ignored if the cluster already have statefullsets
+func (h *Opensearch) GenerateRestoreStatus(currentStatefullsets []appv1.StatefulSet) (status *RestoreStatus) {
+	if h.Spec.Restore == nil {
+		return nil
+	}
+
+	if len(currentStatefullsets) > 0 {
+		return &RestoreStatus{
+			Phase: RestoreIgnored,
+			Message: "Restore is only done when the cluster is created",
+		}
+	}
+
+	return &RestoreStatus{
+		Phase: RestorePending,
+	}
+}
+
+// ComputeClusterSettingsToApply permit to compute the cluster settings to apply from the current persistent settings
+// The settings removed from spec but previously managed are reset with nil value. The other settings are not changed
+func (h *Opensearch) ComputeClusterSettingsToApply(current map[string]any) (settings map[string]any) {
+	settings = map[string]any{}
+
+	for key, value := range h.Spec.ClusterSettings {
+		if clusterSettingToString(current[key]) != value {
+			settings[key] = value
+		}
+	}
+
+	if h.Status.ClusterSettings != nil {
+		for _, key := range h.Status.ClusterSettings.Keys {
+			if _, ok := h.Spec.ClusterSettings[key]; !ok {
+				if _, ok := current[key]; ok {
+					settings[key] = nil
+				}
+			}
+		}
+	}
+
+	return settings
+}
+
+// GetTransportSeeds permit to get the transport addresses used by remote clusters to connect on this cluster
+// It return the headless services of master node groups
+func (h *Opensearch) GetTransportSeeds() (seeds []string) {
+	seeds = make([]string, 0, 1)
+
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
+		if h.IsMasterRole(&nodeGroup) {
+			seeds = append(seeds, fmt.Sprintf("%s.%s.svc:9300", h.GetNodeGroupServiceNameHeadless(nodeGroup.Name), h.Namespace))
+		}
+	}
+
+	return seeds
+}
+
+// GetRemoteOpensearchNamespace permit to get the namespace of remote cluster managed by operator
+// It default to the namespace of this cluster
+func (h *Opensearch) GetRemoteOpensearchNamespace(remoteCluster *RemoteClusterSpec) string {
+	if remoteCluster.OpensearchRef == nil || remoteCluster.OpensearchRef.Namespace == "" {
+		return h.Namespace
+	}
+
+	return remoteCluster.OpensearchRef.Namespace
+}
+
+// IsRemoteClusterOf return true if this cluster is declared as remote cluster of the other cluster
+func (h *Opensearch) IsRemoteClusterOf(o *Opensearch) bool {
+	for _, remoteCluster := range o.Spec.RemoteClusters {
+		if remoteCluster.OpensearchRef != nil && remoteCluster.OpensearchRef.Name == h.Name && o.GetRemoteOpensearchNamespace(&remoteCluster) == h.Namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ComputeRemoteClusterSettingsToApply permit to compute the remote cluster settings to apply from the seeds of each remote clusters and the current persistent settings
+// The remote clusters without seeds are skipped. The remote clusters removed from spec but previously configured (listed on status) are reset with nil value
+func (h *Opensearch) ComputeRemoteClusterSettingsToApply(seeds map[string][]string, current map[string]any) (settings map[string]any) {
+	settings = map[string]any{}
+	names := make([]string, 0, len(h.Spec.RemoteClusters))
+
+	for _, remoteCluster := range h.Spec.RemoteClusters {
+		names = append(names, remoteCluster.Name)
+
+		// The remote cluster managed by operator is not yet created
+		if len(seeds[remoteCluster.Name]) == 0 {
+			continue
+		}
+
+		seedsKey := fmt.Sprintf("cluster.remote.%s.seeds", remoteCluster.Name)
+		if clusterSettingToString(current[seedsKey]) != strings.Join(seeds[remoteCluster.Name], ",") {
+			settings[seedsKey] = seeds[remoteCluster.Name]
+		}
+
+		skipUnavailableKey := fmt.Sprintf("cluster.remote.%s.skip_unavailable", remoteCluster.Name)
+		currentSkipUnavailable := clusterSettingToString(current[skipUnavailableKey])
+		if currentSkipUnavailable == "" {
+			currentSkipUnavailable = "false"
+		}
+		if currentSkipUnavailable != fmt.Sprint(remoteCluster.SkipUnavailable) {
+			settings[skipUnavailableKey] = remoteCluster.SkipUnavailable
+		}
+	}
+
+	for _, status := range h.Status.RemoteClusters {
+		if funk.ContainsString(names, status.Name) {
+			continue
+		}
+		for _, key := range []string{fmt.Sprintf("cluster.remote.%s.seeds", status.Name), fmt.Sprintf("cluster.remote.%s.skip_unavailable", status.Name)} {
+			if _, ok := current[key]; ok {
+				settings[key] = nil
+			}
+		}
+	}
+
+	return settings
+}
+
 // GetSecretNameForTlsTransport permit to get the secret name that store all certificates for transport layout
 // It return the secret name as string
 func (h *Opensearch) GetSecretNameForTlsTransport() (secretName string) {
@@ -100,6 +287,17 @@ func (h *Opensearch) GetGlobalServiceName() (serviceName string) {
 	return fmt.Sprintf("%s-os", h.Name)
 }
 
+// GetApiUrl permit to get the URL of Opensearch API used by operator
+// The API is served on HTTP without authentication when security plugin is disabled
+func (h *Opensearch) GetApiUrl() string {
+	scheme := "https"
+	if h.Spec.DisableSecurityPlugin {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s.%s.svc:9200", scheme, h.GetGlobalServiceName(), h.Namespace)
+}
+
 // GetNodeGroupServiceName permit to get the service name for specified node group name
 func (h *Opensearch) GetNodeGroupServiceName(nodeGroupName string) (serviceName string) {
 	return h.GetNodeGroupName(nodeGroupName)
@@ -110,10 +308,64 @@ func (h *Opensearch) GetNodeGroupServiceNameHeadless(nodeGroupName string) (serv
 	return fmt.Sprintf("%s-headless", h.GetNodeGroupName(nodeGroupName))
 }
 
+// GetLoadBalancerServiceName permit to get the service name used as load balancer
+func (h *Opensearch) GetLoadBalancerServiceName() (serviceName string) {
+	return fmt.Sprintf("%s-lb", h.GetGlobalServiceName())
+}
+
 func(h *Opensearch) GetNodeGroupPDBName(nodeGroupName string) (serviceName string) {
 	return h.GetNodeGroupName(nodeGroupName)
 }
 
+// GetNodeGroupNetworkPolicyName permit to get the network policy name for specified node group name
+func (h *Opensearch) GetNodeGroupNetworkPolicyName(nodeGroupName string) (networkPolicyName string) {
+	return h.GetNodeGroupName(nodeGroupName)
+}
+
+// IsNetworkPolicyEnabled return true if network policies are enabled
+func (h *Opensearch) IsNetworkPolicyEnabled() bool {
+	if h.Spec.NetworkPolicy != nil && h.Spec.NetworkPolicy.Enabled {
+		return true
+	}
+
+	return false
+}
+
+// IsAwarenessEnabled return true if zone awareness is enabled
+func (h *Opensearch) IsAwarenessEnabled() bool {
+	if h.Spec.Awareness != nil && h.Spec.Awareness.Enabled {
+		return true
+	}
+
+	return false
+}
+
+// GetAwarenessTopologyKey permit to get the node label used to get the zone
+func (h *Opensearch) GetAwarenessTopologyKey() (topologyKey string) {
+	if h.Spec.Awareness != nil && h.Spec.Awareness.TopologyKey != "" {
+		return h.Spec.Awareness.TopologyKey
+	}
+
+	return defaultAwarenessTopologyKey
+}
+
+// GetServiceAccountNameForAwareness permit to get the service account name used by pods to read node labels
+func (h *Opensearch) GetServiceAccountNameForAwareness() (serviceAccountName string) {
+	return fmt.Sprintf("%s-os-awareness", h.Name)
+}
+
+// GetClusterRoleNameForAwareness permit to get the cluster role name that allow to read node labels
+// The cluster role is not namespaced, so the namespace is part of the name
+func (h *Opensearch) GetClusterRoleNameForAwareness() (clusterRoleName string) {
+	return fmt.Sprintf("%s-%s-os-awareness", h.Namespace, h.Name)
+}
+
+// GetClusterRoleBindingNameForAwareness permit to get the cluster role binding name that allow pods to read node labels
+// The cluster role binding is not namespaced, so the namespace is part of the name
+func (h *Opensearch) GetClusterRoleBindingNameForAwareness() (clusterRoleBindingName string) {
+	return fmt.Sprintf("%s-%s-os-awareness", h.Namespace, h.Name)
+}
+
 // IsIngressEnabled return true if ingress is enabled
 func (h *Opensearch) IsIngressEnabled() bool {
 	if h.Spec.Endpoint != nil && h.Spec.Endpoint.Ingress != nil && h.Spec.Endpoint.Ingress.Enabled {
@@ -132,6 +384,57 @@ func (h *Opensearch) IsLoadBalancerEnabled() bool {
 	return false
 }
 
+// GetApiCertificateAltNames permit to compute the subject alt names expected on Api certificate
+// It use all generated services (short, namespaced and FQDN forms), the ingress host, the observed load balancer addresses
+// and the alt names / IPs provided on self signed certificate spec
+// loadBalancer is the current load balancer service read on Kubernetes. It can be nil
+func (h *Opensearch) GetApiCertificateAltNames(loadBalancer *corev1.Service) (altNames []string, altIPs []string, err error) {
+	altNames = make([]string, 0)
+	altIPs = make([]string, 0)
+
+	// Compute alt names from services
+	services, err := h.GenerateServices()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error when generate services")
+	}
+	for _, service := range services {
+		altNames = append(altNames, computeServiceDNSNames(service.Name, h.Namespace, service.Spec.ClusterIP == "None")...)
+	}
+
+	// Compute alt names from load balancer
+	if h.IsLoadBalancerEnabled() {
+		altNames = append(altNames, computeServiceDNSNames(h.GetLoadBalancerServiceName(), h.Namespace, false)...)
+		if loadBalancer != nil {
+			for _, lbIngress := range loadBalancer.Status.LoadBalancer.Ingress {
+				if lbIngress.IP != "" {
+					altIPs = append(altIPs, lbIngress.IP)
+				}
+				if lbIngress.Hostname != "" {
+					altNames = append(altNames, lbIngress.Hostname)
+				}
+			}
+		}
+	}
+
+	// Compute alt names from ingress
+	if h.IsIngressEnabled() && h.Spec.Endpoint.Ingress.Host != "" {
+		altNames = append(altNames, h.Spec.Endpoint.Ingress.Host)
+	}
+
+	// Add alt names provided by user
+	if h.Spec.Endpoint != nil && h.Spec.Endpoint.LoadBalancer != nil && h.Spec.Endpoint.LoadBalancer.Tls != nil && h.Spec.Endpoint.LoadBalancer.Tls.SelfSignedCertificate != nil {
+		altNames = append(altNames, h.Spec.Endpoint.LoadBalancer.Tls.SelfSignedCertificate.AltNames...)
+		altIPs = append(altIPs, h.Spec.Endpoint.LoadBalancer.Tls.SelfSignedCertificate.AltIps...)
+	}
+
+	altNames = funk.UniqString(altNames)
+	altIPs = funk.UniqString(altIPs)
+	sort.Strings(altNames)
+	sort.Strings(altIPs)
+
+	return altNames, altIPs, nil
+}
+
 // GetContainerImage permit to get the image name
 func (h *Opensearch) GetContainerImage() string {
 	version := "latest"
@@ -147,11 +450,90 @@ func (h *Opensearch) GetContainerImage() string {
 	return fmt.Sprintf("%s:%s", image, version)
 }
 
+// GetNodeGroupVersion permit to get the Opensearch version used by node group
+// The node group version take precedence over the global version
+func (h *Opensearch) GetNodeGroupVersion(nodeGroup *NodeGroupSpec) string {
+	if nodeGroup.Version != "" {
+		return nodeGroup.Version
+	}
+
+	if h.Spec.Version != "" {
+		return h.Spec.Version
+	}
+
+	return "latest"
+}
+
+// GetNodeGroupContainerImage permit to get the image name used by node group
+// The node group image and version take precedence over the global ones
+func (h *Opensearch) GetNodeGroupContainerImage(nodeGroup *NodeGroupSpec) string {
+	image := defaultImage
+	if nodeGroup.Image != "" {
+		image = nodeGroup.Image
+	} else if h.Spec.Image != "" {
+		image = h.Spec.Image
+	}
+
+	return fmt.Sprintf("%s:%s", image, h.GetNodeGroupVersion(nodeGroup))
+}
+
+// GetNodeGroupPluginsList permit to get the list of plugins to install on node group
+// The node group list replace the global list when it set
+func (h *Opensearch) GetNodeGroupPluginsList(nodeGroup *NodeGroupSpec) []string {
+	if nodeGroup.PluginsList != nil {
+		return nodeGroup.PluginsList
+	}
+
+	return h.Spec.PluginsList
+}
+
+// GetNodeGroupPlugins permit to get the list of plugins to install from offline sources on node group
+// The node group list replace the global list when it set
+// The prometheus-exporter plugin is added when monitoring use plugin mode, if it not already listed
+func (h *Opensearch) GetNodeGroupPlugins(nodeGroup *NodeGroupSpec) []PluginSpec {
+	plugins := h.Spec.Plugins
+	if nodeGroup.Plugins != nil {
+		plugins = nodeGroup.Plugins
+	}
+
+	if !h.IsMonitoringEnabled() || h.GetMonitoringMode() != MonitoringModePlugin {
+		return plugins
+	}
+	monitoringPlugin := h.computeMonitoringPlugin(nodeGroup)
+	if funk.ContainsString(h.GetNodeGroupPluginsList(nodeGroup), monitoringPlugin.Name) {
+		return plugins
+	}
+	for _, plugin := range plugins {
+		if plugin.Name == monitoringPlugin.Name {
+			return plugins
+		}
+	}
+
+	return append(append(make([]PluginSpec, 0, len(plugins)+1), plugins...), monitoringPlugin)
+}
+
+// IsPrebuiltPlugins return true if plugins are baked on image
+func (h *Opensearch) IsPrebuiltPlugins() bool {
+	return h.Spec.PluginsMode == PluginsModePrebuilt
+}
+
+// GetPluginsToRemove permit to get the list of bundled plugins to remove
+// The security plugin is removed when it disabled
+func (h *Opensearch) GetPluginsToRemove() []string {
+	plugins := make([]string, 0, len(h.Spec.PluginsToRemove) + 1)
+	plugins = append(plugins, h.Spec.PluginsToRemove...)
+	if h.Spec.DisableSecurityPlugin && !funk.ContainsString(plugins, securityPluginName) {
+		plugins = append(plugins, securityPluginName)
+	}
+
+	return plugins
+}
+
 // GenerateIngress permit to generate Ingress object
 // It return error if ingress spec is not provided
 // It return nil if ingress is disabled
 func (h *Opensearch) GenerateIngress() (ingress *networkingv1.Ingress, err error) {
-	if !h.IsIngressEnabled() {
+	if !h.IsIngressEnabled() || h.IsRestoreInProgress() {
 		return nil, nil
 	}
 
@@ -169,7 +551,7 @@ func (h *Opensearch) GenerateIngress() (ingress *networkingv1.Ingress, err error
 	if h.Spec.Endpoint.Ingress.TargetNodeGroupName != "" {
 		// Check the node group specified exist
 		isFound := false
-		for _, nodeGroup := range h.Spec.NodeGroups {
+		for _, nodeGroup := range h.GetActiveNodeGroups() {
 			if nodeGroup.Name == h.Spec.Endpoint.Ingress.TargetNodeGroupName {
 				isFound = true
 				break
@@ -254,6 +636,13 @@ func (h *Opensearch) GenerateServices() (services []*corev1.Service, err error)
 	}
 
 	// Generate cluster service
+	// It target coordinating nodes if there are
+	selector := map[string]string{
+		"cluster": h.Name,
+	}
+	if h.HasCoordinatingNodeGroup() {
+		selector["coordinating"] = "true"
+	}
 	service = &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: h.Namespace,
@@ -264,9 +653,7 @@ func (h *Opensearch) GenerateServices() (services []*corev1.Service, err error)
 		Spec: corev1.ServiceSpec{
 			Type: corev1.ServiceTypeClusterIP,
 			SessionAffinity: corev1.ServiceAffinityNone,
-			Selector: map[string]string{
-				"cluster": h.Name,
-			},
+			Selector: selector,
 			Ports: []corev1.ServicePort{
 				{
 					Name: "http",
@@ -287,7 +674,7 @@ func (h *Opensearch) GenerateServices() (services []*corev1.Service, err error)
 	services = append(services, service)
 
 	// Generate service for each node group
-	for _, nodeGroup :=  range h.Spec.NodeGroups {
+	for _, nodeGroup :=  range h.GetActiveNodeGroups() {
 		service = &corev1.Service{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: h.Namespace,
@@ -362,7 +749,7 @@ func (h *Opensearch) GenerateServices() (services []*corev1.Service, err error)
 // It return nil if Loadbalancer is disabled
 func (h *Opensearch) GenerateLoadbalancer() (service *corev1.Service, err error) {
 
-	if !h.IsLoadBalancerEnabled() {
+	if !h.IsLoadBalancerEnabled() || h.IsRestoreInProgress() {
 		return nil, nil
 	}
 
@@ -373,7 +760,7 @@ func (h *Opensearch) GenerateLoadbalancer() (service *corev1.Service, err error)
 	if h.Spec.Endpoint.LoadBalancer.TargetNodeGroupName != "" {
 		// Check the node group specified exist
 		isFound := false
-		for _, nodeGroup := range h.Spec.NodeGroups {
+		for _, nodeGroup := range h.GetActiveNodeGroups() {
 			if nodeGroup.Name == h.Spec.Endpoint.LoadBalancer.TargetNodeGroupName {
 				isFound = true
 				break
@@ -384,12 +771,14 @@ func (h *Opensearch) GenerateLoadbalancer() (service *corev1.Service, err error)
 		}
 
 		selector["nodeGroup"] = h.Spec.Endpoint.LoadBalancer.TargetNodeGroupName
+	} else if h.HasCoordinatingNodeGroup() {
+		selector["coordinating"] = "true"
 	}
 
 	service = &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: h.Namespace,
-			Name: fmt.Sprintf("%s-lb", h.GetGlobalServiceName()),
+			Name: h.GetLoadBalancerServiceName(),
 			Labels: h.Labels,
 			Annotations: h.Annotations,
 		},
@@ -412,19 +801,33 @@ func (h *Opensearch) GenerateLoadbalancer() (service *corev1.Service, err error)
 }
 
 // GenerateConfigMaps permit to generate config maps for each node Groups
-func (h *Opensearch) GenerateConfigMaps() (configMaps []*corev1.ConfigMap, err error) {
+// The opensearchList is the clusters managed by operator, the nodes of clusters that declare this cluster as remote cluster are accepted on transport layer
+func (h *Opensearch) GenerateConfigMaps(opensearchList []Opensearch) (configMaps []*corev1.ConfigMap, err error) {
 	var (
 		configMap *corev1.ConfigMap
 		expectedConfig map[string]string
 	)
 
+	if err = h.checkDisableSecurityPlugin(); err != nil {
+		return nil, err
+	}
+	if err = h.checkSnapshotRepositories(); err != nil {
+		return nil, err
+	}
+	if err = h.checkRestore(); err != nil {
+		return nil, err
+	}
+	if err = h.checkRemoteClusters(); err != nil {
+		return nil, err
+	}
+
 	configMaps = make([]*corev1.ConfigMap, 0, len(h.Spec.NodeGroups))
 	injectedConfigMap := map[string]string {
 		"opensearch.yml": `
 plugins.security.ssl.transport.keystore_type: 'PKCS12/PFX'
 plugins.security.ssl.transport.keystore_filepath: 'certs/transport/${hostname}.pfx'
 plugins.security.ssl.transport.truststore_type: 'PKCS12/PFX'
-plugins.security.ssl.transport.truststore_filepath: 'certs/transport/${hostanme}.pfx'
+plugins.security.ssl.transport.truststore_filepath: 'certs/transport/truststore.pfx'
 plugins.security.ssl.transport.enforce_hostname_verification: true
 plugins.security.ssl.http.enabled: true
 plugins.security.ssl.http.keystore_type: 'PKCS12/PFX'
@@ -433,7 +836,27 @@ plugins.security.ssl.http.truststore_type: 'PKCS12/PFX'
 plugins.security.ssl.http.truststore_filepath: 'certs/http/api.pfx'`,
 	}
 
-	for _, nodeGroup := range h.Spec.NodeGroups {
+	// Security settings are unknown when security plugin is removed
+	if h.Spec.DisableSecurityPlugin {
+		delete(injectedConfigMap, "opensearch.yml")
+	}
+
+	// Inject awareness settings
+	if h.IsAwarenessEnabled() {
+		injectedConfigMap["opensearch.yml"] = injectedConfigMap["opensearch.yml"] + h.computeAwarenessConfig()
+	}
+
+	// Inject fs snapshot repositories location
+	if config := h.computeSnapshotRepositoriesConfig(); config != "" {
+		injectedConfigMap["opensearch.yml"] = injectedConfigMap["opensearch.yml"] + config
+	}
+
+	// Inject the nodes of remote clusters accepted by security plugin
+	if config := h.computeNodesDNConfig(opensearchList); config != "" && !h.Spec.DisableSecurityPlugin {
+		injectedConfigMap["opensearch.yml"] = injectedConfigMap["opensearch.yml"] + config
+	}
+
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
 		
 		if h.Spec.GlobalNodeGroup.Config != nil {
 			expectedConfig, err = helper.MergeSettings(nodeGroup.Config, h.Spec.GlobalNodeGroup.Config)
@@ -444,8 +867,18 @@ plugins.security.ssl.http.truststore_filepath: 'certs/http/api.pfx'`,
 			expectedConfig = nodeGroup.Config
 		}
 
+		// Coordinating only node need empty roles on config
+		nodeGroupInjectedConfigMap := injectedConfigMap
+		if h.IsCoordinatingRole(&nodeGroup) {
+			nodeGroupInjectedConfigMap = map[string]string{}
+			for key, value := range injectedConfigMap {
+				nodeGroupInjectedConfigMap[key] = value
+			}
+			nodeGroupInjectedConfigMap["opensearch.yml"] = nodeGroupInjectedConfigMap["opensearch.yml"] + "\nnode.roles: []\n"
+		}
+
 		// Inject computed config
-		expectedConfig, err = helper.MergeSettings(injectedConfigMap, expectedConfig)
+		expectedConfig, err = helper.MergeSettings(nodeGroupInjectedConfigMap, expectedConfig)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Error when merge expected config with computed config on node group %s", nodeGroup.Name)
 		}
@@ -466,12 +899,31 @@ plugins.security.ssl.http.truststore_filepath: 'certs/http/api.pfx'`,
 }
 
 // GenerateStatefullsets permit to generate statefullsets for each node groups
-func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet, err error) {
+// The keystoreSecrets are the snapshot repositories credentials secrets, indexed by name. They are used to restart pods when credentials change
+func (h *Opensearch) GenerateStatefullsets(keystoreSecrets map[string]*corev1.Secret) (statefullsets []*appv1.StatefulSet, err error) {
 	var (
 		sts *appv1.StatefulSet
 	)
 
-	for _, nodeGroup := range h.Spec.NodeGroups {
+	if err = h.checkVersionsCompatibility(); err != nil {
+		return nil, err
+	}
+	if err = h.checkSuccessorNodeGroups(); err != nil {
+		return nil, err
+	}
+	if err = h.checkMonitoring(); err != nil {
+		return nil, err
+	}
+
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
+
+		isCoordinating := h.IsCoordinatingRole(&nodeGroup)
+		if isCoordinating && nodeGroup.Persistence != nil {
+			return nil, errors.Errorf("Persistence can't be set on coordinating node group %s", nodeGroup.Name)
+		}
+		if err = h.checkNodeGroupRoles(&nodeGroup); err != nil {
+			return nil, err
+		}
 
 		cb := k8sbuilder.NewContainerBuilder()
 		ptb := k8sbuilder.NewPodTemplateBuilder()
@@ -497,6 +949,7 @@ func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet
 		cb.WithEnv(h.Spec.GlobalNodeGroup.Env).
 		WithEnv(nodeGroup.Env, k8sbuilder.Merge).
 		WithEnv(h.computeRoles(nodeGroup.Roles), k8sbuilder.Merge).
+		WithEnv(h.computeNodeAttributes(&nodeGroup), k8sbuilder.Merge).
 		WithEnv([]corev1.EnvVar{
 			{
 				Name: "node.name",
@@ -573,7 +1026,7 @@ func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet
 		cb.WithResource(nodeGroup.Resources, k8sbuilder.Merge)
 
 		// Compute image
-		cb.WithImage(h.GetContainerImage(), k8sbuilder.OverwriteIfDefaultValue)
+		cb.WithImage(h.GetNodeGroupContainerImage(&nodeGroup), k8sbuilder.OverwriteIfDefaultValue)
 
 		// Compute image pull policy
 		cb.WithImagePullPolicy(h.Spec.ImagePullPolicy).
@@ -620,8 +1073,47 @@ func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet
 				},
 			}, k8sbuilder.Merge)
 		}
+		if h.IsAwarenessEnabled() {
+			cb.WithVolumeMount([]corev1.VolumeMount{
+				{
+					Name: "awareness",
+					MountPath: awarenessPath,
+				},
+			}, k8sbuilder.Merge)
+		}
+		if h.hasKeystore() {
+			cb.WithVolumeMount([]corev1.VolumeMount{
+				{
+					Name: "keystore",
+					MountPath: "/usr/share/opensearch/config/opensearch.keystore",
+					SubPath: "opensearch.keystore",
+				},
+			}, k8sbuilder.Merge)
+		}
+		for _, repository := range h.Spec.SnapshotRepositories {
+			if repository.Type != "fs" {
+				continue
+			}
+			cb.WithVolumeMount([]corev1.VolumeMount{
+				{
+					Name: getSnapshotRepositoryVolumeName(&repository),
+					MountPath: repository.Settings["location"],
+				},
+			}, k8sbuilder.Merge)
+		}
+		if h.hasNodeGroupPlugins(&nodeGroup) && !h.IsPrebuiltPlugins() {
+			pluginsVolumeName, pluginsSubPath := h.getPluginsVolume(&nodeGroup)
+			cb.WithVolumeMount([]corev1.VolumeMount{
+				{
+					Name: pluginsVolumeName,
+					MountPath: "/usr/share/opensearch/plugins",
+					SubPath: path.Join(pluginsSubPath, "plugins"),
+				},
+			}, k8sbuilder.Merge)
+		}
 		// Compute mount config maps
-		configMaps, err := h.GenerateConfigMaps()
+		// The files don't depend on the other clusters
+		configMaps, err := h.GenerateConfigMaps(nil)
 		if err != nil {
 			return nil, errors.Wrap(err, "Error when generate configMaps")
 		}
@@ -639,6 +1131,15 @@ func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet
 			}
 		}
 
+		// Compute probes
+		// Coordinating nodes not hold data, so they start faster
+		readinessPeriodSeconds := int32(30)
+		startupInitialDelaySeconds := int32(10)
+		if isCoordinating {
+			readinessPeriodSeconds = 10
+			startupInitialDelaySeconds = 5
+		}
+
 		// Compute liveness
 		cb.WithLivenessProbe(&corev1.Probe{
 			TimeoutSeconds: 5,
@@ -655,7 +1156,7 @@ func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet
 		// Compute readiness
 		cb.WithReadinessProbe(&corev1.Probe{
 			TimeoutSeconds: 5,
-			PeriodSeconds: 30,
+			PeriodSeconds: readinessPeriodSeconds,
 			FailureThreshold: 3,
 			SuccessThreshold: 1,
 			ProbeHandler: corev1.ProbeHandler{
@@ -667,7 +1168,7 @@ func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet
 
 		// Compute startup
 		cb.WithStartupProbe(&corev1.Probe{
-			InitialDelaySeconds: 10,
+			InitialDelaySeconds: startupInitialDelaySeconds,
 			TimeoutSeconds: 5,
 			PeriodSeconds: 10,
 			FailureThreshold: 30,
@@ -679,20 +1180,19 @@ func (h *Opensearch) GenerateStatefullsets() (statefullsets []*appv1.StatefulSet
 			},
 		}, k8sbuilder.OverwriteIfDefaultValue)
 
-		// Add specific command to handle plugin installation
-		var pluginInstallation strings.Builder
-		pluginInstallation.WriteString(`#!/usr/bin/env bash
+		// Add specific command to handle environment computed by init containers
+		// The image entrypoint is kept when not needed, and exec is used so the JVM receive the signals
+		if h.IsAwarenessEnabled() {
+			cb.Container().Command = []string{
+				"bash",
+				"-c",
+				fmt.Sprintf(`#!/usr/bin/env bash
 set -euo pipefail
 
-`)	
-		for _, plugin :=  range h.Spec.PluginsList {
-			pluginInstallation.WriteString(fmt.Sprintf("./bin/opensearch-plugin install -b %s\n", plugin))
-		}
-		pluginInstallation.WriteString("bash opensearch-docker-entrypoint.sh\n")
-		cb.Container().Command = []string{
-			"sh",
-			"-c",
-			pluginInstallation.String(),
+export NODE_ZONE=$(cat %s/zone)
+exec ./opensearch-docker-entrypoint.sh
+`, awarenessPath),
+			}
 		}
 
 		// Initialise PodTemplate
@@ -702,10 +1202,7 @@ set -euo pipefail
 		// Compute labels
 		ptb.WithLabels(h.Labels, k8sbuilder.Merge).
 		WithLabels(h.Spec.GlobalNodeGroup.Labels, k8sbuilder.Merge).
-		WithLabels(nodeGroup.Labels, k8sbuilder.Merge).WithLabels(map[string]string{
-			"cluster": h.Name,
-			"nodeGroup": nodeGroup.Name,
-		}, k8sbuilder.Merge)
+		WithLabels(nodeGroup.Labels, k8sbuilder.Merge).WithLabels(h.computeNodeGroupLabels(&nodeGroup), k8sbuilder.Merge)
 
 		// Compute annotations
 		ptb.WithAnnotations(h.Annotations, k8sbuilder.Merge).
@@ -723,23 +1220,51 @@ set -euo pipefail
 
 
 
-		// compute anti affinity
-		antiAffinity, err := h.computeAntiAffinity(&nodeGroup)
+		// Compute topology spread constraints
+		topologySpreadConstraints, err := h.computeTopologySpreadConstraints(&nodeGroup)
 		if err != nil {
-			return nil, errors.Wrapf(err, "Error when compute anti affinity for %s", nodeGroup.Name)
+			return nil, errors.Wrapf(err, "Error when compute topology spread constraints for %s", nodeGroup.Name)
+		}
+		if topologySpreadConstraints != nil {
+			ptb.PodTemplate().Spec.TopologySpreadConstraints = topologySpreadConstraints
+		}
+
+		// compute anti affinity
+		// Default anti affinity is not set when topology spread constraints are used
+		if topologySpreadConstraints == nil || nodeGroup.AntiAffinity != nil || h.Spec.GlobalNodeGroup.AntiAffinity != nil {
+			antiAffinity, err := h.computeAntiAffinity(&nodeGroup)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error when compute anti affinity for %s", nodeGroup.Name)
+			}
+			ptb.WithAffinity(corev1.Affinity{
+				PodAntiAffinity: antiAffinity,
+			}, k8sbuilder.Merge)
 		}
-		ptb.WithAffinity(corev1.Affinity{
-			PodAntiAffinity: antiAffinity,
-		}, k8sbuilder.Merge)
 
 		// Compute containers
 		ptb.WithContainers([]corev1.Container{*cb.Container()}, k8sbuilder.Merge)
+		if h.IsMonitoringEnabled() && h.GetMonitoringMode() == MonitoringModeExporter {
+			ptb.WithContainers([]corev1.Container{h.computeMonitoringExporterContainer(&nodeGroup)}, k8sbuilder.Merge)
+			if !h.Spec.DisableSecurityPlugin {
+				ptb.WithVolumes([]corev1.Volume{
+					{
+						Name: "monitoring-credentials",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{
+								SecretName: h.GetSecretNameForMonitoringUser(),
+								Optional: pointer.Bool(true),
+							},
+						},
+					},
+				}, k8sbuilder.Merge)
+			}
+		}
 
 		// Compute init containers
 		if h.Spec.SetVMMaxMapCount == nil || *h.Spec.SetVMMaxMapCount {
 			icb := k8sbuilder.NewContainerBuilder().WithContainer(&corev1.Container{
 				Name: "configure-sysctl",
-				Image: h.GetContainerImage(),
+				Image: h.GetNodeGroupContainerImage(&nodeGroup),
 				ImagePullPolicy: h.Spec.ImagePullPolicy,
 				SecurityContext: &corev1.SecurityContext{
 					Privileged: pointer.Bool(true),
@@ -755,22 +1280,270 @@ set -euo pipefail
 
 			ptb.WithInitContainers([]corev1.Container{*icb.Container()}, k8sbuilder.Merge)
 		}
-
-		// Compute volumes
-		ptb.WithVolumes([]corev1.Volume{
-			{
-				Name: "node-tls",
-				VolumeSource: corev1.VolumeSource{
-					Secret: &corev1.SecretVolumeSource{
-						SecretName: h.GetSecretNameForTlsTransport(),
+		if h.IsAwarenessEnabled() {
+			icb := k8sbuilder.NewContainerBuilder().WithContainer(&corev1.Container{
+				Name: "awareness",
+				Image: h.GetNodeGroupContainerImage(&nodeGroup),
+				ImagePullPolicy: h.Spec.ImagePullPolicy,
+				Env: []corev1.EnvVar{
+					{
+						Name: "NODE_NAME",
+						ValueFrom: &corev1.EnvVarSource{
+							FieldRef: &corev1.ObjectFieldSelector{
+								APIVersion: "v1",
+								FieldPath: "spec.nodeName",
+							},
+						},
 					},
 				},
-			},
-			{
-				Name: "api-tls",
-				VolumeSource: corev1.VolumeSource{
-					Secret: &corev1.SecretVolumeSource{
-						SecretName: h.GetSecretNameForTlsApi(),
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name: "awareness",
+						MountPath: awarenessPath,
+					},
+				},
+				Command: []string{
+					"bash",
+					"-c",
+					h.computeAwarenessScript(),
+				},
+			})
+			icb.WithResource(h.Spec.GlobalNodeGroup.InitContainerResources)
+
+			ptb.WithInitContainers([]corev1.Container{*icb.Container()}, k8sbuilder.Merge)
+			ptb.WithVolumes([]corev1.Volume{
+				{
+					Name: "awareness",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			}, k8sbuilder.Merge)
+			// Keep the service account provided by user, it is bound to the awareness cluster role
+			if ptb.PodTemplate().Spec.ServiceAccountName == "" {
+				ptb.PodTemplate().Spec.ServiceAccountName = h.GetServiceAccountNameForAwareness()
+			}
+		}
+		if h.hasNodeGroupPlugins(&nodeGroup) && h.IsPrebuiltPlugins() {
+			icb := k8sbuilder.NewContainerBuilder().WithContainer(&corev1.Container{
+				Name: PluginsCheckContainerName,
+				Image: h.GetNodeGroupContainerImage(&nodeGroup),
+				ImagePullPolicy: h.Spec.ImagePullPolicy,
+				Command: []string{
+					"bash",
+					"-c",
+					h.computePluginsCheckScript(&nodeGroup),
+				},
+			})
+			icb.WithResource(h.Spec.GlobalNodeGroup.InitContainerResources)
+
+			ptb.WithInitContainers([]corev1.Container{*icb.Container()}, k8sbuilder.Merge)
+		} else if h.hasNodeGroupPlugins(&nodeGroup) {
+			if err = h.checkNodeGroupPlugins(&nodeGroup); err != nil {
+				return nil, err
+			}
+			pluginsHash, err := h.computePluginsHash(&nodeGroup)
+			if err != nil {
+				return nil, err
+			}
+
+			// Plugins are installed on data volume if possible, to not reinstall them on each pod start
+			pluginsVolumeName, pluginsSubPath := h.getPluginsVolume(&nodeGroup)
+			volumes := make([]corev1.Volume, 0, 1)
+			if pluginsSubPath == "" {
+				volumes = append(volumes, corev1.Volume{
+					Name: pluginsVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				})
+			}
+			volumeMounts := []corev1.VolumeMount{
+				{
+					Name: pluginsVolumeName,
+					MountPath: pluginsPath,
+					SubPath: pluginsSubPath,
+				},
+			}
+			initContainers := make([]corev1.Container, 0, 1)
+
+			// Compute plugin sources
+			for _, plugin := range h.GetNodeGroupPlugins(&nodeGroup) {
+				volume := corev1.Volume{
+					Name: getPluginVolumeName(&plugin),
+				}
+				switch {
+				case plugin.ConfigMapRef != nil:
+					volume.VolumeSource = corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: plugin.ConfigMapRef.LocalObjectReference,
+							Items: []corev1.KeyToPath{
+								{
+									Key: plugin.ConfigMapRef.Key,
+									Path: "plugin.zip",
+								},
+							},
+						},
+					}
+				case plugin.SecretRef != nil:
+					volume.VolumeSource = corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: plugin.SecretRef.Name,
+							Items: []corev1.KeyToPath{
+								{
+									Key: plugin.SecretRef.Key,
+									Path: "plugin.zip",
+								},
+							},
+						},
+					}
+				case plugin.Image != "":
+					imagePath := defaultPluginImagePath
+					if plugin.ImagePath != "" {
+						imagePath = plugin.ImagePath
+					}
+					volume.VolumeSource = corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					}
+					initContainers = append(initContainers, corev1.Container{
+						Name: getPluginVolumeName(&plugin),
+						Image: plugin.Image,
+						ImagePullPolicy: h.Spec.ImagePullPolicy,
+						VolumeMounts: []corev1.VolumeMount{
+							{
+								Name: getPluginVolumeName(&plugin),
+								MountPath: fmt.Sprintf("%s/%s", pluginSourcesPath, plugin.Name),
+							},
+						},
+						Command: []string{
+							"cp",
+							imagePath,
+							getPluginSourceFile(&plugin),
+						},
+					})
+				default:
+					continue
+				}
+				volumes = append(volumes, volume)
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name: getPluginVolumeName(&plugin),
+					MountPath: fmt.Sprintf("%s/%s", pluginSourcesPath, plugin.Name),
+				})
+			}
+
+			icb := k8sbuilder.NewContainerBuilder().WithContainer(&corev1.Container{
+				Name: "install-plugins",
+				Image: h.GetNodeGroupContainerImage(&nodeGroup),
+				ImagePullPolicy: h.Spec.ImagePullPolicy,
+				Env: []corev1.EnvVar{
+					{
+						Name: "PLUGINS_HASH",
+						Value: pluginsHash,
+					},
+				},
+				VolumeMounts: volumeMounts,
+				Command: []string{
+					"bash",
+					"-c",
+					h.computePluginsScript(&nodeGroup),
+				},
+			})
+			icb.WithResource(h.Spec.GlobalNodeGroup.InitContainerResources)
+			initContainers = append(initContainers, *icb.Container())
+
+			ptb.WithInitContainers(initContainers, k8sbuilder.Merge)
+			ptb.WithVolumes(volumes, k8sbuilder.Merge)
+			ptb.WithAnnotations(map[string]string{
+				pluginsHashAnnotation: pluginsHash,
+			}, k8sbuilder.Merge)
+		}
+
+		if h.hasKeystore() {
+			volumes := []corev1.Volume{
+				{
+					Name: "keystore",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			}
+			volumeMounts := []corev1.VolumeMount{
+				{
+					Name: "keystore",
+					MountPath: keystorePath,
+				},
+			}
+			for _, repository := range h.Spec.SnapshotRepositories {
+				if repository.CredentialsSecretRef == "" {
+					continue
+				}
+				volumes = append(volumes, corev1.Volume{
+					Name: getKeystoreVolumeName(&repository),
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: repository.CredentialsSecretRef,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name: getKeystoreVolumeName(&repository),
+					MountPath: fmt.Sprintf("%s/%s", keystoreSourcesPath, repository.Name),
+					ReadOnly: true,
+				})
+			}
+
+			icb := k8sbuilder.NewContainerBuilder().WithContainer(&corev1.Container{
+				Name: "keystore",
+				Image: h.GetNodeGroupContainerImage(&nodeGroup),
+				ImagePullPolicy: h.Spec.ImagePullPolicy,
+				VolumeMounts: volumeMounts,
+				Command: []string{
+					"bash",
+					"-c",
+					h.computeKeystoreScript(),
+				},
+			})
+			icb.WithResource(h.Spec.GlobalNodeGroup.InitContainerResources)
+
+			keystoreHash, err := h.computeKeystoreHash(keystoreSecrets)
+			if err != nil {
+				return nil, err
+			}
+
+			ptb.WithInitContainers([]corev1.Container{*icb.Container()}, k8sbuilder.Merge)
+			ptb.WithVolumes(volumes, k8sbuilder.Merge)
+			ptb.WithAnnotations(map[string]string{
+				keystoreHashAnnotation: keystoreHash,
+			}, k8sbuilder.Merge)
+		}
+
+		for _, repository := range h.Spec.SnapshotRepositories {
+			if repository.Type != "fs" {
+				continue
+			}
+			ptb.WithVolumes([]corev1.Volume{
+				{
+					Name: getSnapshotRepositoryVolumeName(&repository),
+					VolumeSource: *repository.Volume,
+				},
+			}, k8sbuilder.Merge)
+		}
+
+		// Compute volumes
+		ptb.WithVolumes([]corev1.Volume{
+			{
+				Name: "node-tls",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: h.GetSecretNameForTlsTransport(),
+					},
+				},
+			},
+			{
+				Name: "api-tls",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: h.GetSecretNameForTlsApi(),
 					},
 				},
 			},
@@ -845,6 +1618,19 @@ set -euo pipefail
 			},
 		}
 
+		// Coordinating nodes not hold shards, so they can be restarted like deployment, with many pods at the same time
+		// The max unavailable need the MaxUnavailableStatefulSet feature gate (alpha since Kubernetes 1.24)
+		// Without it, the field is ignored by Kubernetes and pods are restarted one by one
+		if isCoordinating {
+			maxUnavailable := intstr.FromString("25%")
+			sts.Spec.UpdateStrategy = appv1.StatefulSetUpdateStrategy{
+				Type: appv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appv1.RollingUpdateStatefulSetStrategy{
+					MaxUnavailable: &maxUnavailable,
+				},
+			}
+		}
+
 		// Compute persistence
 		if nodeGroup.Persistence != nil && nodeGroup.Persistence.VolumeClaimSpec != nil {
 			sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
@@ -855,6 +1641,7 @@ set -euo pipefail
 					Spec: *nodeGroup.Persistence.VolumeClaimSpec,
 				},
 			}
+			sts.Spec.PersistentVolumeClaimRetentionPolicy = nodeGroup.Persistence.PersistentVolumeClaimRetentionPolicy
 		} 		
 
 		statefullsets = append(statefullsets, sts)
@@ -873,7 +1660,7 @@ func (h *Opensearch) GeneratePodDisruptionBudget() (podDisruptionBudgets []*poli
 
 
 	maxUnavailable := intstr.FromInt(1)
-	for _, nodeGroup := range h.Spec.NodeGroups {
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
 		pdb = &policyv1.PodDisruptionBudget{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: h.Namespace,
@@ -908,15 +1695,555 @@ func (h *Opensearch) GeneratePodDisruptionBudget() (podDisruptionBudgets []*poli
 
 
 
-// isMasterRole return true if nodegroup have `cluster_manager` role
-func (h *Opensearch) IsMasterRole(nodeGroup *NodeGroupSpec) bool {
-	return funk.Contains(nodeGroup.Roles, "cluster_manager")
+// GenerateNetworkPolicies permit to generate network policies for each node group
+// Transport port is only allowed between pods of the same cluster
+// HTTP port is allowed from pods of the same cluster, the operator, the ingress controller (if ingress is enabled) and the allowed peers
+// When load balancer is enabled, HTTP port is allowed from everywhere on the targeted pods
+// It return empty list if network policies are disabled
+// The operator pods are selected on the operator namespace, so it need to be provided when operator peer is not set
+func (h *Opensearch) GenerateNetworkPolicies(operatorNamespace string) (networkPolicies []*networkingv1.NetworkPolicy, err error) {
+	networkPolicies = make([]*networkingv1.NetworkPolicy, 0, len(h.Spec.NodeGroups))
+	var (
+		networkPolicy *networkingv1.NetworkPolicy
+		httpPeers []networkingv1.NetworkPolicyPeer
+		ingressRules []networkingv1.NetworkPolicyIngressRule
+	)
+
+	if !h.IsNetworkPolicyEnabled() {
+		return networkPolicies, nil
+	}
+
+	protocol := corev1.ProtocolTCP
+	httpPort := intstr.FromInt(9200)
+	transportPort := intstr.FromInt(9300)
+	clusterPeer := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"cluster": h.Name,
+			},
+		},
+	}
+	var operatorPeer networkingv1.NetworkPolicyPeer
+	if h.Spec.NetworkPolicy.OperatorPeer != nil {
+		operatorPeer = *h.Spec.NetworkPolicy.OperatorPeer
+	} else {
+		if operatorNamespace == "" {
+			return nil, errors.New("Operator namespace must be provided when operator peer is not set")
+		}
+		operatorPeer = networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"kubernetes.io/metadata.name": operatorNamespace,
+				},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					operatorPodLabel: operatorName,
+				},
+			},
+		}
+	}
+	ingressControllerPeer := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"kubernetes.io/metadata.name": "ingress-nginx",
+			},
+		},
+	}
+	if h.Spec.NetworkPolicy.IngressControllerPeer != nil {
+		ingressControllerPeer = *h.Spec.NetworkPolicy.IngressControllerPeer
+	}
+
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
+
+		// Compute peers allowed to access on HTTP
+		httpPeers = []networkingv1.NetworkPolicyPeer{clusterPeer, operatorPeer}
+		if h.IsIngressEnabled() && h.isEndpointTarget(h.Spec.Endpoint.Ingress.TargetNodeGroupName, &nodeGroup) {
+			httpPeers = append(httpPeers, ingressControllerPeer)
+		}
+		httpPeers = append(httpPeers, h.Spec.NetworkPolicy.AllowedPeers...)
+		if h.IsLoadBalancerEnabled() && h.isEndpointTarget(h.Spec.Endpoint.LoadBalancer.TargetNodeGroupName, &nodeGroup) {
+			// Load balancer forward external traffic, so all peers must be allowed
+			httpPeers = nil
+		}
+
+		ingressRules = []networkingv1.NetworkPolicyIngressRule{
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{
+						Protocol: &protocol,
+						Port: &transportPort,
+					},
+				},
+				From: []networkingv1.NetworkPolicyPeer{clusterPeer},
+			},
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{
+						Protocol: &protocol,
+						Port: &httpPort,
+					},
+				},
+				From: httpPeers,
+			},
+		}
+		ingressRules = append(ingressRules, h.Spec.NetworkPolicy.AdditionalRules...)
+
+		networkPolicy = &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: h.Namespace,
+				Name: h.GetNodeGroupNetworkPolicyName(nodeGroup.Name),
+				Labels: h.Labels,
+				Annotations: h.Annotations,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"cluster": h.Name,
+						"nodeGroup": nodeGroup.Name,
+					},
+				},
+				PolicyTypes: []networkingv1.PolicyType{
+					networkingv1.PolicyTypeIngress,
+				},
+				Ingress: ingressRules,
+			},
+		}
+
+		networkPolicies = append(networkPolicies, networkPolicy)
+	}
+
+	return networkPolicies, nil
 }
 
+// GenerateServiceAccountForAwareness permit to generate the service account used by pods to read node labels
+// It return nil if awareness is disabled
+func (h *Opensearch) GenerateServiceAccountForAwareness() (serviceAccount *corev1.ServiceAccount, err error) {
+	if !h.IsAwarenessEnabled() {
+		return nil, nil
+	}
 
+	serviceAccount = &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name: h.GetServiceAccountNameForAwareness(),
+			Labels: h.Labels,
+			Annotations: h.Annotations,
+		},
+	}
+
+	return serviceAccount, nil
+}
+
+// GenerateClusterRoleForAwareness permit to generate the cluster role that allow to read node labels
+// It return nil if awareness is disabled
+func (h *Opensearch) GenerateClusterRoleForAwareness() (clusterRole *rbacv1.ClusterRole, err error) {
+	if !h.IsAwarenessEnabled() {
+		return nil, nil
+	}
+
+	clusterRole = &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.GetClusterRoleNameForAwareness(),
+			Labels: funk.UnionStringMap(h.Labels, map[string]string{
+				"cluster": h.Name,
+				"namespace": h.Namespace,
+			}),
+			Annotations: h.Annotations,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"nodes"},
+				Verbs: []string{"get"},
+			},
+		},
+	}
+
+	return clusterRole, nil
+}
+
+// GenerateClusterRoleBindingForAwareness permit to generate the cluster role binding that allow pods to read node labels
+// The service accounts provided by user on pod templates are bound too
+// It return nil if awareness is disabled
+func (h *Opensearch) GenerateClusterRoleBindingForAwareness() (clusterRoleBinding *rbacv1.ClusterRoleBinding, err error) {
+	if !h.IsAwarenessEnabled() {
+		return nil, nil
+	}
+
+	clusterRoleBinding = &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.GetClusterRoleBindingNameForAwareness(),
+			Labels: funk.UnionStringMap(h.Labels, map[string]string{
+				"cluster": h.Name,
+				"namespace": h.Namespace,
+			}),
+			Annotations: h.Annotations,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind: "ClusterRole",
+			Name: h.GetClusterRoleNameForAwareness(),
+		},
+	}
+	for _, serviceAccountName := range h.getNodeGroupServiceAccountNamesForAwareness() {
+		clusterRoleBinding.Subjects = append(clusterRoleBinding.Subjects, rbacv1.Subject{
+			Kind: rbacv1.ServiceAccountKind,
+			Name: serviceAccountName,
+			Namespace: h.Namespace,
+		})
+	}
+
+	return clusterRoleBinding, nil
+}
+
+// IsMonitoringEnabled return true if monitoring is enabled
+func (h *Opensearch) IsMonitoringEnabled() bool {
+	return h.Spec.Monitoring != nil && h.Spec.Monitoring.Enabled
+}
+
+// GetMonitoringMode permit to get the way to expose metrics
+// Default to plugin
+func (h *Opensearch) GetMonitoringMode() string {
+	if h.Spec.Monitoring == nil || h.Spec.Monitoring.Mode == "" {
+		return MonitoringModePlugin
+	}
+
+	return h.Spec.Monitoring.Mode
+}
+
+// GetMonitorType permit to get the Prometheus operator resource that scrape the metrics
+// Default to ServiceMonitor
+func (h *Opensearch) GetMonitorType() string {
+	if h.Spec.Monitoring == nil || h.Spec.Monitoring.MonitorType == "" {
+		return MonitorTypeServiceMonitor
+	}
+
+	return h.Spec.Monitoring.MonitorType
+}
 
+// GetMonitoringUserName permit to get the OpensearchUser, OpensearchRole and OpensearchRoleMapping resource name used to read metrics
+// It also the user name and the role name on Opensearch
+func (h *Opensearch) GetMonitoringUserName() string {
+	return fmt.Sprintf("%s-monitoring", h.GetGlobalServiceName())
+}
+
+// GetSecretNameForMonitoringUser permit to get the secret name that store the credentials of monitoring user
+// The secret is generated by the OpensearchUser controller
+func (h *Opensearch) GetSecretNameForMonitoringUser() string {
+	user := &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: h.GetMonitoringUserName(),
+		},
+	}
+
+	return user.GetSecretNameForPassword()
+}
 
+// GetMonitoringServiceName permit to get the service name that expose metrics
+func (h *Opensearch) GetMonitoringServiceName() string {
+	return fmt.Sprintf("%s-metrics", h.GetGlobalServiceName())
+}
 
+// GetMonitoringExporterImage permit to get the image name of exporter sidecar
+func (h *Opensearch) GetMonitoringExporterImage() string {
+	image := defaultMonitoringExporterImage
+	version := defaultMonitoringExporterVersion
 
+	if h.Spec.Monitoring != nil && h.Spec.Monitoring.Exporter != nil {
+		if h.Spec.Monitoring.Exporter.Image != "" {
+			image = h.Spec.Monitoring.Exporter.Image
+		}
+		if h.Spec.Monitoring.Exporter.Version != "" {
+			version = h.Spec.Monitoring.Exporter.Version
+		}
+	}
 
+	return fmt.Sprintf("%s:%s", image, version)
+}
+
+// GenerateMonitoringUser permit to generate the user used to read metrics
+// It return nil if monitoring is disabled or if security plugin is disabled
+func (h *Opensearch) GenerateMonitoringUser() (user *OpensearchUser) {
+	if !h.IsMonitoringEnabled() || h.Spec.DisableSecurityPlugin {
+		return nil
+	}
+
+	return &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetMonitoringUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchUserSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Name,
+			},
+			Description: fmt.Sprintf("Monitoring user for %s", h.Name),
+		},
+	}
+}
 
+// GenerateMonitoringRole permit to generate the role with the minimal permissions to read metrics
+// It return nil if monitoring is disabled or if security plugin is disabled
+func (h *Opensearch) GenerateMonitoringRole() (role *OpensearchRole) {
+	if !h.IsMonitoringEnabled() || h.Spec.DisableSecurityPlugin {
+		return nil
+	}
+
+	return &OpensearchRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetMonitoringUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchRoleSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Name,
+			},
+			Description: fmt.Sprintf("Monitoring role for %s", h.Name),
+			ClusterPermissions: []string{
+				"cluster_monitor",
+			},
+			IndexPermissions: []IndexPermissionSpec{
+				{
+					IndexPatterns: []string{"*"},
+					AllowedActions: []string{
+						"indices_monitor",
+					},
+				},
+			},
+		},
+	}
+}
+
+// GenerateMonitoringRoleMapping permit to map the monitoring role to the monitoring user
+// It return nil if monitoring is disabled or if security plugin is disabled
+func (h *Opensearch) GenerateMonitoringRoleMapping() (roleMapping *OpensearchRoleMapping) {
+	if !h.IsMonitoringEnabled() || h.Spec.DisableSecurityPlugin {
+		return nil
+	}
+
+	return &OpensearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetMonitoringUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchRoleMappingSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Name,
+			},
+			Description: fmt.Sprintf("Monitoring role mapping for %s", h.Name),
+			Users:       []string{h.GetMonitoringUserName()},
+		},
+	}
+}
+
+// GenerateMonitoringService permit to generate the headless service that expose metrics of all nodes
+// It target the exporter sidecar or the Opensearch API, depending of monitoring mode
+// It return nil if monitoring is disabled
+func (h *Opensearch) GenerateMonitoringService() (service *corev1.Service, err error) {
+	if !h.IsMonitoringEnabled() {
+		return nil, nil
+	}
+
+	port := int32(9200)
+	if h.GetMonitoringMode() == MonitoringModeExporter {
+		port = monitoringExporterPort
+	}
+
+	service = &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetMonitoringServiceName(),
+			Labels:      funk.UnionStringMap(h.Labels, h.computeMonitoringSelector()),
+			Annotations: h.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeClusterIP,
+			ClusterIP:       "None",
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Selector: map[string]string{
+				"cluster": h.Name,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "metrics",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+				},
+			},
+		},
+	}
+
+	return service, nil
+}
+
+// GenerateMonitor permit to generate the ServiceMonitor or the PodMonitor that scrape metrics
+// The Prometheus operator API is not vendored, so it return unstructured object
+// It return nil if monitoring is disabled or if monitor type is None
+func (h *Opensearch) GenerateMonitor() (monitor *unstructured.Unstructured, err error) {
+	if !h.IsMonitoringEnabled() || h.GetMonitorType() == MonitorTypeNone {
+		return nil, nil
+	}
+
+	endpoint := map[string]any{
+		"port":     "metrics",
+		"path":     "/metrics",
+		"scheme":   "http",
+		"interval": defaultMonitoringInterval,
+	}
+	if h.Spec.Monitoring.Interval != "" {
+		endpoint["interval"] = h.Spec.Monitoring.Interval
+	}
+
+	if h.GetMonitoringMode() == MonitoringModePlugin {
+		endpoint["path"] = "/_prometheus/metrics"
+		if h.GetMonitorType() == MonitorTypePodMonitor {
+			endpoint["port"] = "http"
+		}
+
+		if !h.Spec.DisableSecurityPlugin {
+			endpoint["scheme"] = "https"
+			endpoint["basicAuth"] = map[string]any{
+				"username": map[string]any{
+					"name": h.GetSecretNameForMonitoringUser(),
+					"key":  "username",
+				},
+				"password": map[string]any{
+					"name": h.GetSecretNameForMonitoringUser(),
+					"key":  defaultUserPasswordKey,
+				},
+			}
+			endpoint["tlsConfig"] = h.computeMonitoringTlsConfig()
+		}
+	}
+
+	monitor = &unstructured.Unstructured{
+		Object: map[string]any{
+			"spec": map[string]any{},
+		},
+	}
+	monitor.SetAPIVersion("monitoring.coreos.com/v1")
+	monitor.SetKind(h.GetMonitorType())
+	monitor.SetNamespace(h.Namespace)
+	monitor.SetName(h.GetGlobalServiceName())
+	monitor.SetLabels(funk.UnionStringMap(h.Labels, h.Spec.Monitoring.Labels))
+	monitor.SetAnnotations(h.Annotations)
+
+	selector := map[string]any{
+		"matchLabels": map[string]any{
+			"cluster": h.Name,
+		},
+	}
+	if h.GetMonitorType() == MonitorTypePodMonitor {
+		err = unstructured.SetNestedField(monitor.Object, []any{endpoint}, "spec", "podMetricsEndpoints")
+	} else {
+		selector["matchLabels"] = map[string]any{}
+		for key, value := range h.computeMonitoringSelector() {
+			selector["matchLabels"].(map[string]any)[key] = value
+		}
+		err = unstructured.SetNestedField(monitor.Object, []any{endpoint}, "spec", "endpoints")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when set monitor endpoints")
+	}
+	if err = unstructured.SetNestedField(monitor.Object, selector, "spec", "selector"); err != nil {
+		return nil, errors.Wrap(err, "Error when set monitor selector")
+	}
+
+	return monitor, nil
+}
+
+// ComputeAllocationExclusion permit to compute the nodes excluded from shards allocation during node groups migrations
+// The exclusions set by other migrations, by user or by cluster settings are kept, so it only add the nodes to relocate and remove the nodes of completed migrations
+// It return nil when no node is excluded, to reset the setting
+func ComputeAllocationExclusion(current any, nodesToExclude []string, nodesToRelease []string) any {
+	nodeNames := make([]string, 0)
+	candidates := make([]string, 0, len(nodesToExclude))
+	if currentNodes, ok := current.(string); ok {
+		candidates = append(candidates, strings.Split(currentNodes, ",")...)
+	}
+	candidates = append(candidates, nodesToExclude...)
+
+	// Keep the order to not update the setting without change
+	for _, nodeName := range candidates {
+		nodeName = strings.TrimSpace(nodeName)
+		if nodeName != "" && !funk.ContainsString(nodeNames, nodeName) && !funk.ContainsString(nodesToRelease, nodeName) {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+
+	if len(nodeNames) == 0 {
+		return nil
+	}
+
+	return strings.Join(nodeNames, ",")
+}
+
+// ComputeVolumeExpansion permit to compute the persistent volume claims to expand when the storage request of node group increase
+// The volume claim templates are immutable, so it also return true when the statefullset need to be recreated with orphan semantic
+// It return error if storage request decrease or if storage class not allow volume expansion
+func ComputeVolumeExpansion(currentSts *appv1.StatefulSet, expectedSts *appv1.StatefulSet, pvcs []corev1.PersistentVolumeClaim, storageClass *storagev1.StorageClass) (pvcsToExpand []corev1.PersistentVolumeClaim, needRecreate bool, err error) {
+	pvcsToExpand = make([]corev1.PersistentVolumeClaim, 0)
+
+	if currentSts == nil || expectedSts == nil {
+		return pvcsToExpand, false, nil
+	}
+
+	expectedSize := getDataStorageRequest(expectedSts)
+	currentSize := getDataStorageRequest(currentSts)
+	if expectedSize == nil || currentSize == nil {
+		return pvcsToExpand, false, nil
+	}
+
+	switch expectedSize.Cmp(*currentSize) {
+	case -1:
+		return nil, false, errors.Errorf("Storage request of statefullset %s can't be decreased from %s to %s", expectedSts.Name, currentSize.String(), expectedSize.String())
+	case 1:
+		needRecreate = true
+	}
+
+	for _, pvc := range pvcs {
+		size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if ok && size.Cmp(*expectedSize) >= 0 {
+			continue
+		}
+		pvc.Spec.Resources.Requests = pvc.Spec.Resources.Requests.DeepCopy()
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *expectedSize
+		pvcsToExpand = append(pvcsToExpand, pvc)
+	}
+
+	if len(pvcsToExpand) > 0 && (storageClass == nil || storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion) {
+		return nil, false, errors.Errorf("Storage class of statefullset %s not allow volume expansion", expectedSts.Name)
+	}
+
+	return pvcsToExpand, needRecreate, nil
+}
+
+// isMasterRole return true if nodegroup have `cluster_manager` role
+func (h *Opensearch) IsMasterRole(nodeGroup *NodeGroupSpec) bool {
+	return funk.Contains(nodeGroup.Roles, "cluster_manager")
+}
+
+// IsCoordinatingRole return true if nodegroup not have any roles
+// Coordinating nodes only route requests, so they not hold data and can't be master
+func (h *Opensearch) IsCoordinatingRole(nodeGroup *NodeGroupSpec) bool {
+	return len(nodeGroup.Roles) == 0
+}
+
+// HasCoordinatingNodeGroup return true if at least one node group is coordinating only
+func (h *Opensearch) HasCoordinatingNodeGroup() bool {
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
+		if h.IsCoordinatingRole(&nodeGroup) {
+			return true
+		}
+	}
+
+	return false
+}
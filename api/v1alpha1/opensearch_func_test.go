@@ -9,6 +9,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -225,6 +227,23 @@ func TestGetGlobalServiceName(t *testing.T) {
 	assert.Equal(t, "test-os", o.GetGlobalServiceName())
 }
 
+func TestGetApiUrl(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{},
+	}
+
+	// With default value
+	assert.Equal(t, "https://test-os.default.svc:9200", o.GetApiUrl())
+
+	// When security plugin is disabled
+	o.Spec.DisableSecurityPlugin = true
+	assert.Equal(t, "http://test-os.default.svc:9200", o.GetApiUrl())
+}
+
 func TestGetNodeGroupServiceName(t *testing.T) {
 	o := &Opensearch{
 		ObjectMeta: metav1.ObjectMeta{
@@ -294,6 +313,47 @@ func TestGetNodeGroupPDBName(t *testing.T) {
 	assert.Equal(t, "test-master-os", o.GetNodeGroupPDBName(o.Spec.NodeGroups[0].Name))
 }
 
+func TestGetNodeGroupNetworkPolicyName(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, "test-master-os", o.GetNodeGroupNetworkPolicyName(o.Spec.NodeGroups[0].Name))
+}
+
+func TestIsNetworkPolicyEnabled(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{},
+	}
+	assert.False(t, o.IsNetworkPolicyEnabled())
+
+	// When network policy is specified but disabled
+	o.Spec.NetworkPolicy = &NetworkPolicySpec{
+		Enabled: false,
+	}
+	assert.False(t, o.IsNetworkPolicyEnabled())
+
+	// When network policy is enabled
+	o.Spec.NetworkPolicy.Enabled = true
+	assert.True(t, o.IsNetworkPolicyEnabled())
+}
+
 func TestIsIngressEnabled(t *testing.T) {
 
 	// With default values
@@ -344,6 +404,127 @@ func TestIsLoadBalancerEnabled(t *testing.T) {
 
 }
 
+func TestGetLoadBalancerServiceName(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{},
+	}
+
+	assert.Equal(t, "test-os-lb", o.GetLoadBalancerServiceName())
+}
+
+func TestGetApiCertificateAltNames(t *testing.T) {
+	var (
+		o *Opensearch
+		altNames []string
+		altIPs []string
+		err error
+	)
+
+	// With default values
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{},
+	}
+
+	altNames, altIPs, err = o.GetApiCertificateAltNames(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"test-os",
+		"test-os.default",
+		"test-os.default.svc",
+		"test-os.default.svc.cluster.local",
+	}, altNames)
+	assert.Empty(t, altIPs)
+
+	// With node groups, ingress, load balancer and custom alt names
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+				},
+			},
+			Endpoint: &EndpointSpec{
+				Ingress: &IngressSpec{
+					Enabled: true,
+					Host: "my-test.cluster.local",
+				},
+				LoadBalancer: &LoadBalancerSpec{
+					Enabled: true,
+					Tls: &TlsSpec{
+						SelfSignedCertificate: &SelfSignedCertificateSpec{
+							AltNames: []string{"opensearch.domain.com"},
+							AltIps: []string{"10.0.0.1"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	lb := &corev1.Service{
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{
+					{
+						IP: "192.168.0.1",
+					},
+					{
+						Hostname: "lb.domain.com",
+					},
+				},
+			},
+		},
+	}
+
+	altNames, altIPs, err = o.GetApiCertificateAltNames(lb)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"*.test-master-os-headless.default.svc",
+		"*.test-master-os-headless.default.svc.cluster.local",
+		"lb.domain.com",
+		"my-test.cluster.local",
+		"opensearch.domain.com",
+		"test-master-os",
+		"test-master-os-headless",
+		"test-master-os-headless.default",
+		"test-master-os-headless.default.svc",
+		"test-master-os-headless.default.svc.cluster.local",
+		"test-master-os.default",
+		"test-master-os.default.svc",
+		"test-master-os.default.svc.cluster.local",
+		"test-os",
+		"test-os-lb",
+		"test-os-lb.default",
+		"test-os-lb.default.svc",
+		"test-os-lb.default.svc.cluster.local",
+		"test-os.default",
+		"test-os.default.svc",
+		"test-os.default.svc.cluster.local",
+	}, altNames)
+	assert.Equal(t, []string{"10.0.0.1", "192.168.0.1"}, altIPs)
+
+	// When load balancer is disabled, custom alt names are kept
+	o.Spec.Endpoint.LoadBalancer.Enabled = false
+	altNames, altIPs, err = o.GetApiCertificateAltNames(lb)
+	assert.NoError(t, err)
+	assert.Contains(t, altNames, "opensearch.domain.com")
+	assert.NotContains(t, altNames, "lb.domain.com")
+	assert.Equal(t, []string{"10.0.0.1"}, altIPs)
+}
+
 func TestGetContainerImage(t *testing.T) {
 	// With default values
 	o := &Opensearch{
@@ -364,6 +545,86 @@ func TestGetContainerImage(t *testing.T) {
 	assert.Equal(t, "my-image:v1", o.GetContainerImage())
 }
 
+func TestGetNodeGroupVersion(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+				},
+			},
+		},
+	}
+	assert.Equal(t, "latest", o.GetNodeGroupVersion(&o.Spec.NodeGroups[0]))
+
+	// When global version is specified
+	o.Spec.Version = "2.3.0"
+	assert.Equal(t, "2.3.0", o.GetNodeGroupVersion(&o.Spec.NodeGroups[0]))
+
+	// When node group version is specified
+	o.Spec.NodeGroups[0].Version = "2.4.0"
+	assert.Equal(t, "2.4.0", o.GetNodeGroupVersion(&o.Spec.NodeGroups[0]))
+}
+
+func TestGetNodeGroupContainerImage(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+				},
+			},
+		},
+	}
+	assert.Equal(t, "public.ecr.aws/opensearchproject/opensearch:latest", o.GetNodeGroupContainerImage(&o.Spec.NodeGroups[0]))
+
+	// When global image and version are specified
+	o.Spec.Image = "my-image"
+	o.Spec.Version = "2.3.0"
+	assert.Equal(t, "my-image:2.3.0", o.GetNodeGroupContainerImage(&o.Spec.NodeGroups[0]))
+
+	// When node group image and version are specified
+	o.Spec.NodeGroups[0].Image = "my-canary-image"
+	o.Spec.NodeGroups[0].Version = "2.4.0"
+	assert.Equal(t, "my-canary-image:2.4.0", o.GetNodeGroupContainerImage(&o.Spec.NodeGroups[0]))
+}
+
+func TestGetNodeGroupPluginsList(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+				},
+			},
+		},
+	}
+	assert.Empty(t, o.GetNodeGroupPluginsList(&o.Spec.NodeGroups[0]))
+
+	// When global plugins are specified
+	o.Spec.PluginsList = []string{"repository-s3"}
+	assert.Equal(t, []string{"repository-s3"}, o.GetNodeGroupPluginsList(&o.Spec.NodeGroups[0]))
+
+	// When node group plugins are specified
+	o.Spec.NodeGroups[0].PluginsList = []string{"repository-s3", "analysis-icu"}
+	assert.Equal(t, []string{"repository-s3", "analysis-icu"}, o.GetNodeGroupPluginsList(&o.Spec.NodeGroups[0]))
+}
+
 func TestGetConfigMaps(t *testing.T) {
 
 	o := &Opensearch{
@@ -402,11 +663,82 @@ node:
 		},
 	}
 
-	configMaps, err := o.GenerateConfigMaps()
+	configMaps, err := o.GenerateConfigMaps(nil)
 	assert.NoError(t, err)
 	test.EqualFromYamlFile(t, "../../fixture/api/os-configmap.yml", configMaps[0])
 }
 
+func TestGetPluginsToRemove(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+	}
+	assert.Empty(t, o.GetPluginsToRemove())
+
+	// When plugins to remove are specified
+	o.Spec.PluginsToRemove = []string{"opensearch-performance-analyzer"}
+	assert.Equal(t, []string{"opensearch-performance-analyzer"}, o.GetPluginsToRemove())
+
+	// When security plugin is disabled
+	o.Spec.DisableSecurityPlugin = true
+	assert.Equal(t, []string{"opensearch-performance-analyzer", "opensearch-security"}, o.GetPluginsToRemove())
+}
+
+func TestGenerateWithoutSecurityPlugin(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			DisableSecurityPlugin: true,
+			PluginsToRemove: []string{
+				"opensearch-performance-analyzer",
+			},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+					Config: map[string]string{
+						"opensearch.yml": "node.value: test",
+					},
+				},
+			},
+		},
+	}
+
+	// Security settings are not injected
+	configMaps, err := o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "node.value: test", configMaps[0].Data["opensearch.yml"])
+
+	// Plugins are removed from init container
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	script := sts[0].Spec.Template.Spec.InitContainers[1].Command[2]
+	assert.Contains(t, script, "./bin/opensearch-plugin remove opensearch-performance-analyzer\n")
+	assert.Contains(t, script, "./bin/opensearch-plugin remove opensearch-security\n")
+
+	// When security config is managed by operator
+	o.Spec.NodeGroups[0].SecurityRef = "opensearch-security"
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+	_, err = o.GenerateStatefullsets(nil)
+	assert.Error(t, err)
+
+	o.Spec.NodeGroups[0].SecurityRef = ""
+	o.Spec.GlobalNodeGroup.SecurityRef = "opensearch-security"
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+}
+
 func TestGenerateIngress(t *testing.T) {
 	var (
 		err error
@@ -825,10 +1157,16 @@ func TestGenerateLoadbalancer(t *testing.T) {
 				{
 					Name: "master",
 					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+					},
 				},
 				{
 					Name: "data",
 					Replicas: 1,
+					Roles: []string{
+						"data",
+					},
 				},
 			},
 			Endpoint: &EndpointSpec{
@@ -1047,11 +1385,15 @@ func TestGeneratePodDisruptionBudget(t *testing.T) {
 
 }
 
-func TestIsMasterRole(t *testing.T) {
+func TestGenerateNetworkPolicies(t *testing.T) {
 
-	var o *Opensearch
+	var (
+		err error
+		nps []*networkingv1.NetworkPolicy
+		o *Opensearch
+	)
 
-	// With only master role
+	// With default values
 	o = &Opensearch{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "default",
@@ -1061,33 +1403,142 @@ func TestIsMasterRole(t *testing.T) {
 			NodeGroups: []NodeGroupSpec{
 				{
 					Name: "master",
-					Replicas: 1,
-					Roles: []string{
-						"cluster_manager",
-					},
+					Replicas: 3,
 				},
 			},
 		},
 	}
 
-	assert.True(t, o.IsMasterRole(&o.Spec.NodeGroups[0]))
+	nps, err = o.GenerateNetworkPolicies("opensearch-operator-system")
+	assert.NoError(t, err)
+	assert.Empty(t, nps)
 
-	// With multiple role
+	// When network policy is enabled
 	o = &Opensearch{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "default",
 			Name: "test",
 		},
 		Spec: OpensearchSpec{
+			NetworkPolicy: &NetworkPolicySpec{
+				Enabled: true,
+				AllowedPeers: []networkingv1.NetworkPolicyPeer{
+					{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"kubernetes.io/metadata.name": "app",
+							},
+						},
+					},
+				},
+				AdditionalRules: []networkingv1.NetworkPolicyIngressRule{
+					{
+						From: []networkingv1.NetworkPolicyPeer{
+							{
+								PodSelector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{
+										"app": "backup",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Endpoint: &EndpointSpec{
+				Ingress: &IngressSpec{
+					Enabled: true,
+					Host: "my-test.cluster.local",
+					TargetNodeGroupName: "client",
+				},
+			},
 			NodeGroups: []NodeGroupSpec{
 				{
 					Name: "master",
-					Replicas: 1,
-					Roles: []string{
-						"data",
-						"cluster_manager",
-						"ingest",
-					},
+					Replicas: 3,
+				},
+				{
+					Name: "client",
+					Replicas: 2,
+				},
+			},
+		},
+	}
+
+	nps, err = o.GenerateNetworkPolicies("opensearch-operator-system")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(nps))
+	test.EqualFromYamlFile(t, "../../fixture/api/os-networkpolicy-master.yml", nps[0])
+	test.EqualFromYamlFile(t, "../../fixture/api/os-networkpolicy-client.yml", nps[1])
+
+	// When load balancer is enabled, http is allowed from everywhere on target node group
+	o.Spec.Endpoint.LoadBalancer = &LoadBalancerSpec{
+		Enabled: true,
+		TargetNodeGroupName: "client",
+	}
+	nps, err = o.GenerateNetworkPolicies("opensearch-operator-system")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, nps[0].Spec.Ingress[1].From)
+	assert.Empty(t, nps[1].Spec.Ingress[1].From)
+
+	// When operator namespace is unknown
+	_, err = o.GenerateNetworkPolicies("")
+	assert.Error(t, err)
+
+	// When operator peer is set
+	o.Spec.NetworkPolicy.OperatorPeer = &networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app": "operator",
+			},
+		},
+	}
+	nps, err = o.GenerateNetworkPolicies("")
+	assert.NoError(t, err)
+	assert.Equal(t, *o.Spec.NetworkPolicy.OperatorPeer, nps[0].Spec.Ingress[1].From[1])
+}
+
+func TestIsMasterRole(t *testing.T) {
+
+	var o *Opensearch
+
+	// With only master role
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 1,
+					Roles: []string{
+						"cluster_manager",
+					},
+				},
+			},
+		},
+	}
+
+	assert.True(t, o.IsMasterRole(&o.Spec.NodeGroups[0]))
+
+	// With multiple role
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 1,
+					Roles: []string{
+						"data",
+						"cluster_manager",
+						"ingest",
+					},
 				},
 			},
 		},
@@ -1147,7 +1598,7 @@ func TestGenerateStatefullset(t *testing.T) {
 		},
 	}
 
-	sts, err = o.GenerateStatefullsets()
+	sts, err = o.GenerateStatefullsets(nil)
 	assert.NoError(t, err)
 	test.EqualFromYamlFile(t, "../../fixture/api/os-statefullset-all.yml", sts[0])
 
@@ -1235,6 +1686,9 @@ func TestGenerateStatefullset(t *testing.T) {
 					Roles: []string{
 						"data",
 					},
+					NodeAttributes: map[string]string{
+						"temp": "hot",
+					},
 					Persistence: &PersistenceSpec{
 						Volume: &corev1.VolumeSource{
 							HostPath: &corev1.HostPathVolumeSource{
@@ -1300,10 +1754,1559 @@ func TestGenerateStatefullset(t *testing.T) {
 		},
 	}
 
-	sts, err = o.GenerateStatefullsets()
+	sts, err = o.GenerateStatefullsets(nil)
 
 	assert.NoError(t, err)
 	test.EqualFromYamlFile(t, "../../fixture/api/os-statefullset-master.yml", sts[0])
 	test.EqualFromYamlFile(t, "../../fixture/api/os-statefullset-data.yml", sts[1])
 	test.EqualFromYamlFile(t, "../../fixture/api/os-statefullset-client.yml", sts[2])
-}
\ No newline at end of file
+
+	// With coordinating node group
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+				{
+					Name: "client",
+					Replicas: 2,
+				},
+			},
+		},
+	}
+
+	sts, err = o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, sts[0].Spec.UpdateStrategy.Type)
+	assert.Empty(t, sts[0].Spec.Template.Labels["coordinating"])
+	test.EqualFromYamlFile(t, "../../fixture/api/os-statefullset-coordinating.yml", sts[1])
+
+	// When persistence is set on coordinating node group
+	o.Spec.NodeGroups[1].Persistence = &PersistenceSpec{
+		Volume: &corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	_, err = o.GenerateStatefullsets(nil)
+	assert.Error(t, err)
+}
+
+func TestIsCoordinatingRole(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+					},
+				},
+				{
+					Name: "client",
+					Replicas: 2,
+				},
+			},
+		},
+	}
+
+	assert.False(t, o.IsCoordinatingRole(&o.Spec.NodeGroups[0]))
+	assert.True(t, o.IsCoordinatingRole(&o.Spec.NodeGroups[1]))
+}
+
+func TestHasCoordinatingNodeGroup(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+					},
+				},
+			},
+		},
+	}
+	assert.False(t, o.HasCoordinatingNodeGroup())
+
+	o.Spec.NodeGroups = append(o.Spec.NodeGroups, NodeGroupSpec{
+		Name: "client",
+		Replicas: 2,
+	})
+	assert.True(t, o.HasCoordinatingNodeGroup())
+}
+
+func TestGenerateWithCoordinatingNodeGroup(t *testing.T) {
+	var (
+		o *Opensearch
+		err error
+	)
+
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+				{
+					Name: "client",
+					Replicas: 2,
+				},
+			},
+			Endpoint: &EndpointSpec{
+				LoadBalancer: &LoadBalancerSpec{
+					Enabled: true,
+				},
+			},
+		},
+	}
+
+	// Global service target coordinating nodes
+	services, err := o.GenerateServices()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cluster": "test", "coordinating": "true"}, services[0].Spec.Selector)
+
+	// Load balancer target coordinating nodes
+	lb, err := o.GenerateLoadbalancer()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cluster": "test", "coordinating": "true"}, lb.Spec.Selector)
+
+	// Coordinating nodes are not master
+	assert.Equal(t, "test-master-os-0 test-master-os-1 test-master-os-2", o.computeInitialMasterNodes())
+	assert.Equal(t, "test-master-os-headless", o.computeDiscoverySeedHosts())
+
+	// Coordinating nodes have empty roles on config
+	configMaps, err := o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, configMaps[0].Data["opensearch.yml"], "node.roles")
+	test.EqualFromYamlFile(t, "../../fixture/api/os-configmap-coordinating.yml", configMaps[1])
+}
+
+func TestIsAwarenessEnabled(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{},
+	}
+	assert.False(t, o.IsAwarenessEnabled())
+
+	// When awareness is specified but disabled
+	o.Spec.Awareness = &AwarenessSpec{
+		Enabled: false,
+	}
+	assert.False(t, o.IsAwarenessEnabled())
+
+	// When awareness is enabled
+	o.Spec.Awareness.Enabled = true
+	assert.True(t, o.IsAwarenessEnabled())
+}
+
+func TestGetAwarenessTopologyKey(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Awareness: &AwarenessSpec{
+				Enabled: true,
+			},
+		},
+	}
+	assert.Equal(t, "topology.kubernetes.io/zone", o.GetAwarenessTopologyKey())
+
+	// When topology key is set
+	o.Spec.Awareness.TopologyKey = "failure-domain.beta.kubernetes.io/zone"
+	assert.Equal(t, "failure-domain.beta.kubernetes.io/zone", o.GetAwarenessTopologyKey())
+}
+
+func TestGetServiceAccountNameForAwareness(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+	}
+
+	assert.Equal(t, "test-os-awareness", o.GetServiceAccountNameForAwareness())
+}
+
+func TestGetClusterRoleNameForAwareness(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+	}
+
+	assert.Equal(t, "default-test-os-awareness", o.GetClusterRoleNameForAwareness())
+}
+
+func TestGetClusterRoleBindingNameForAwareness(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+	}
+
+	assert.Equal(t, "default-test-os-awareness", o.GetClusterRoleBindingNameForAwareness())
+}
+
+func TestGenerateAwarenessRbac(t *testing.T) {
+	var (
+		o *Opensearch
+		err error
+	)
+
+	// With default values
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+	}
+
+	sa, err := o.GenerateServiceAccountForAwareness()
+	assert.NoError(t, err)
+	assert.Nil(t, sa)
+	cr, err := o.GenerateClusterRoleForAwareness()
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+	crb, err := o.GenerateClusterRoleBindingForAwareness()
+	assert.NoError(t, err)
+	assert.Nil(t, crb)
+
+	// When awareness is enabled
+	o.Spec.Awareness = &AwarenessSpec{
+		Enabled: true,
+	}
+
+	sa, err = o.GenerateServiceAccountForAwareness()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-awareness-serviceaccount.yml", sa)
+	cr, err = o.GenerateClusterRoleForAwareness()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-awareness-clusterrole.yml", cr)
+	crb, err = o.GenerateClusterRoleBindingForAwareness()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-awareness-clusterrolebinding.yml", crb)
+
+	// When user provide service account on pod template
+	o.Spec.GlobalNodeGroup.PodTemplate = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "my-sa",
+		},
+	}
+	o.Spec.NodeGroups = []NodeGroupSpec{
+		{
+			Name: "all",
+		},
+	}
+	crb, err = o.GenerateClusterRoleBindingForAwareness()
+	assert.NoError(t, err)
+	assert.Equal(t, []rbacv1.Subject{
+		{
+			Kind: rbacv1.ServiceAccountKind,
+			Name: "test-os-awareness",
+			Namespace: "default",
+		},
+		{
+			Kind: rbacv1.ServiceAccountKind,
+			Name: "my-sa",
+			Namespace: "default",
+		},
+	}, crb.Subjects)
+}
+
+func TestGenerateWithAwareness(t *testing.T) {
+	var (
+		o *Opensearch
+		err error
+	)
+
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Awareness: &AwarenessSpec{
+				Enabled: true,
+				ForcedValues: []string{
+					"zone-a",
+					"zone-b",
+				},
+			},
+			SetVMMaxMapCount: pointer.Bool(false),
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+			},
+		},
+	}
+
+	// Awareness settings are injected on config
+	configMaps, err := o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "node.attr.zone: ${NODE_ZONE}")
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "cluster.routing.allocation.awareness.attributes: zone")
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "cluster.routing.allocation.awareness.force.zone.values: zone-a,zone-b")
+
+	// Zone is read from init container
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	podSpec := sts[0].Spec.Template.Spec
+	assert.Equal(t, "test-os-awareness", podSpec.ServiceAccountName)
+	assert.Equal(t, 1, len(podSpec.InitContainers))
+	assert.Equal(t, "awareness", podSpec.InitContainers[0].Name)
+	assert.Contains(t, podSpec.InitContainers[0].Command[2], "topology.kubernetes.io/zone")
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "awareness",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name: "awareness",
+		MountPath: "/mnt/awareness",
+	})
+	assert.Contains(t, podSpec.Containers[0].Command[2], "export NODE_ZONE=$(cat /mnt/awareness/zone)")
+	assert.Contains(t, podSpec.Containers[0].Command[2], "exec ./opensearch-docker-entrypoint.sh")
+
+	// Service account provided by user is kept
+	o.Spec.NodeGroups[0].PodTemplate = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "my-sa",
+		},
+	}
+	sts, err = o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "my-sa", sts[0].Spec.Template.Spec.ServiceAccountName)
+	o.Spec.NodeGroups[0].PodTemplate = nil
+
+	// Without forced values
+	o.Spec.Awareness.ForcedValues = nil
+	configMaps, err = o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, configMaps[0].Data["opensearch.yml"], "cluster.routing.allocation.awareness.force.zone.values")
+}
+
+func TestGenerateWithTopologySpread(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			GlobalNodeGroup: GlobalNodeGroupSpec{
+				TopologySpread: &TopologySpreadSpec{
+					Constraints: []TopologySpreadConstraintSpec{
+						{
+							TopologyKey: "topology.kubernetes.io/zone",
+						},
+					},
+				},
+			},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+			},
+		},
+	}
+
+	// Default anti affinity is replaced by topology spread constraints
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sts[0].Spec.Template.Spec.TopologySpreadConstraints))
+	assert.Equal(t, "topology.kubernetes.io/zone", sts[0].Spec.Template.Spec.TopologySpreadConstraints[0].TopologyKey)
+	assert.Nil(t, sts[0].Spec.Template.Spec.Affinity)
+
+	// When anti affinity is explicitly set, both are used
+	o.Spec.NodeGroups[0].AntiAffinity = &AntiAffinitySpec{
+		Type: "hard",
+	}
+	sts, err = o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sts[0].Spec.Template.Spec.TopologySpreadConstraints))
+	assert.NotEmpty(t, sts[0].Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+}
+
+func TestGenerateWithCanaryNodeGroup(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+			PluginsList: []string{
+				"repository-s3",
+			},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+				{
+					Name: "client",
+					Replicas: 1,
+					Image: "my-image",
+					Version: "2.4.0",
+					PluginsList: []string{
+						"analysis-icu",
+					},
+				},
+			},
+		},
+	}
+
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "public.ecr.aws/opensearchproject/opensearch:2.3.0", sts[0].Spec.Template.Spec.Containers[0].Image)
+	assert.Contains(t, sts[0].Spec.Template.Spec.InitContainers[1].Command[2], "install -b repository-s3")
+	assert.Equal(t, "my-image:2.4.0", sts[1].Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "my-image:2.4.0", sts[1].Spec.Template.Spec.InitContainers[0].Image)
+	assert.Contains(t, sts[1].Spec.Template.Spec.InitContainers[1].Command[2], "install -b analysis-icu")
+	assert.NotContains(t, sts[1].Spec.Template.Spec.InitContainers[1].Command[2], "repository-s3")
+
+	// When version is not wire compatible
+	o.Spec.NodeGroups[1].Version = "3.0.0"
+	_, err = o.GenerateStatefullsets(nil)
+	assert.Error(t, err)
+}
+
+func TestGetNodeGroupPlugins(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+				},
+			},
+		},
+	}
+	assert.Empty(t, o.GetNodeGroupPlugins(&o.Spec.NodeGroups[0]))
+
+	// When global plugins are specified
+	o.Spec.Plugins = []PluginSpec{{Name: "repository-s3"}}
+	assert.Equal(t, []PluginSpec{{Name: "repository-s3"}}, o.GetNodeGroupPlugins(&o.Spec.NodeGroups[0]))
+
+	// When node group plugins are specified
+	o.Spec.NodeGroups[0].Plugins = []PluginSpec{{Name: "analysis-icu"}}
+	assert.Equal(t, []PluginSpec{{Name: "analysis-icu"}}, o.GetNodeGroupPlugins(&o.Spec.NodeGroups[0]))
+}
+
+func TestGenerateWithPlugins(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+			SetVMMaxMapCount: pointer.Bool(false),
+			Plugins: []PluginSpec{
+				{
+					Name: "repository-s3",
+				},
+				{
+					Name: "prometheus-exporter",
+					Url: "https://repo.local/prometheus-exporter.zip",
+					Checksum: "a8b5ec2b0e0a4e0a3d0a9c1b8e6e6f7f5a9b1c2d3e4f5a6b7c8d9e0f1a2b3c4d",
+				},
+				{
+					Name: "custom",
+					ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "plugins",
+						},
+						Key: "custom.zip",
+					},
+				},
+				{
+					Name: "private",
+					SecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "plugins",
+						},
+						Key: "private.zip",
+					},
+				},
+				{
+					Name: "oci",
+					Image: "registry.local/plugins/oci:1.0.0",
+				},
+			},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+			},
+		},
+	}
+
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-statefullset-plugins.yml", sts[0])
+
+	// Hash change when plugin set change
+	hash := sts[0].Spec.Template.Annotations["opensearch.k8s.webcenter.fr/plugins-hash"]
+	o.Spec.Plugins = o.Spec.Plugins[1:]
+	sts, err = o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, sts[0].Spec.Template.Annotations["opensearch.k8s.webcenter.fr/plugins-hash"])
+
+	// When plugin have multiple sources
+	o.Spec.Plugins[0].Image = "registry.local/plugins/custom:1.0.0"
+	_, err = o.GenerateStatefullsets(nil)
+	assert.Error(t, err)
+
+	// When checksum is not sha256
+	o.Spec.Plugins[0].Image = ""
+	o.Spec.Plugins[0].Checksum = "bad"
+	_, err = o.GenerateStatefullsets(nil)
+	assert.Error(t, err)
+
+	// When plugin name is not valid
+	o.Spec.Plugins[0].Checksum = ""
+	o.Spec.Plugins[0].Name = "Bad_Name"
+	_, err = o.GenerateStatefullsets(nil)
+	assert.Error(t, err)
+}
+
+func TestIsPrebuiltPlugins(t *testing.T) {
+	// With default values
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+	}
+	assert.False(t, o.IsPrebuiltPlugins())
+
+	// When install mode
+	o.Spec.PluginsMode = "install"
+	assert.False(t, o.IsPrebuiltPlugins())
+
+	// When prebuilt mode
+	o.Spec.PluginsMode = "prebuilt"
+	assert.True(t, o.IsPrebuiltPlugins())
+}
+
+func TestGenerateWithPrebuiltPlugins(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+			SetVMMaxMapCount: pointer.Bool(false),
+			PluginsMode: "prebuilt",
+			PluginsList: []string{
+				"repository-s3",
+			},
+			Plugins: []PluginSpec{
+				{
+					Name: "custom",
+					Url: "https://repo.local/custom.zip",
+				},
+			},
+			PluginsToRemove: []string{
+				"opensearch-performance-analyzer",
+			},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+			},
+		},
+	}
+
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	podSpec := sts[0].Spec.Template.Spec
+
+	// Plugins are only checked
+	assert.Equal(t, 1, len(podSpec.InitContainers))
+	assert.Equal(t, "check-plugins", podSpec.InitContainers[0].Name)
+	assert.Contains(t, podSpec.InitContainers[0].Command[2], "for plugin in repository-s3 custom; do")
+	assert.Contains(t, podSpec.InitContainers[0].Command[2], "for plugin in opensearch-performance-analyzer; do")
+	assert.NotContains(t, podSpec.InitContainers[0].Command[2], "opensearch-plugin install")
+	assert.Empty(t, sts[0].Spec.Template.Annotations)
+	for _, volume := range podSpec.Volumes {
+		assert.NotEqual(t, "plugins", volume.Name)
+	}
+	for _, volumeMount := range podSpec.Containers[0].VolumeMounts {
+		assert.NotEqual(t, "plugins", volumeMount.Name)
+	}
+}
+
+func TestGenerateStatefullsetWithRetentionPolicy(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+					Persistence: &PersistenceSpec{
+						VolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse("5Gi"),
+								},
+							},
+						},
+						PersistentVolumeClaimRetentionPolicy: &appv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+							WhenDeleted: appv1.DeletePersistentVolumeClaimRetentionPolicyType,
+							WhenScaled: appv1.RetainPersistentVolumeClaimRetentionPolicyType,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &appv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appv1.DeletePersistentVolumeClaimRetentionPolicyType,
+		WhenScaled: appv1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}, sts[0].Spec.PersistentVolumeClaimRetentionPolicy)
+}
+
+func TestComputeVolumeExpansion(t *testing.T) {
+	var (
+		pvcsToExpand []corev1.PersistentVolumeClaim
+		needRecreate bool
+		err error
+	)
+
+	generateSts := func(size string) *appv1.StatefulSet {
+		return &appv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name: "test-all-os",
+			},
+			Spec: appv1.StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "opensearch-data",
+						},
+						Spec: corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse(size),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	generatePvc := func(name string, size string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name: name,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: pointer.String("standard"),
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(size),
+					},
+				},
+			},
+		}
+	}
+	pvcs := []corev1.PersistentVolumeClaim{
+		generatePvc("opensearch-data-test-all-os-0", "5Gi"),
+		generatePvc("opensearch-data-test-all-os-1", "5Gi"),
+	}
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "standard",
+		},
+		AllowVolumeExpansion: pointer.Bool(true),
+	}
+
+	// When statefullset not yet exist
+	pvcsToExpand, needRecreate, err = ComputeVolumeExpansion(nil, generateSts("10Gi"), nil, storageClass)
+	assert.NoError(t, err)
+	assert.Empty(t, pvcsToExpand)
+	assert.False(t, needRecreate)
+
+	// When storage request not change
+	pvcsToExpand, needRecreate, err = ComputeVolumeExpansion(generateSts("5Gi"), generateSts("5Gi"), pvcs, storageClass)
+	assert.NoError(t, err)
+	assert.Empty(t, pvcsToExpand)
+	assert.False(t, needRecreate)
+
+	// When storage request increase
+	pvcsToExpand, needRecreate, err = ComputeVolumeExpansion(generateSts("5Gi"), generateSts("10Gi"), pvcs, storageClass)
+	assert.NoError(t, err)
+	assert.True(t, needRecreate)
+	assert.Equal(t, 2, len(pvcsToExpand))
+	assert.Equal(t, resource.MustParse("10Gi"), pvcsToExpand[0].Spec.Resources.Requests[corev1.ResourceStorage])
+	assert.Equal(t, resource.MustParse("5Gi"), pvcs[0].Spec.Resources.Requests[corev1.ResourceStorage])
+
+	// When statefullset is already recreated but some PVC are not yet expanded
+	pvcs[0] = generatePvc("opensearch-data-test-all-os-0", "10Gi")
+	pvcsToExpand, needRecreate, err = ComputeVolumeExpansion(generateSts("10Gi"), generateSts("10Gi"), pvcs, storageClass)
+	assert.NoError(t, err)
+	assert.False(t, needRecreate)
+	assert.Equal(t, 1, len(pvcsToExpand))
+	assert.Equal(t, "opensearch-data-test-all-os-1", pvcsToExpand[0].Name)
+
+	// When storage class not allow volume expansion
+	storageClass.AllowVolumeExpansion = pointer.Bool(false)
+	_, _, err = ComputeVolumeExpansion(generateSts("5Gi"), generateSts("10Gi"), pvcs, storageClass)
+	assert.Error(t, err)
+	_, _, err = ComputeVolumeExpansion(generateSts("5Gi"), generateSts("10Gi"), pvcs, nil)
+	assert.Error(t, err)
+
+	// When storage request decrease
+	storageClass.AllowVolumeExpansion = pointer.Bool(true)
+	_, _, err = ComputeVolumeExpansion(generateSts("10Gi"), generateSts("5Gi"), pvcs, storageClass)
+	assert.Error(t, err)
+}
+
+func TestGetActiveNodeGroups(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "data",
+					Replicas: 1,
+				},
+				{
+					Name: "data-ssd",
+					Replicas: 1,
+					SuccessorOf: "data",
+				},
+			},
+		},
+	}
+
+	// When migration is not started
+	assert.Equal(t, 2, len(o.GetActiveNodeGroups()))
+	assert.Equal(t, 1, len(o.GetSuccessorNodeGroups()))
+	assert.Nil(t, o.GetNodeGroupMigration("data"))
+	assert.False(t, o.IsNodeGroupMigrated("data"))
+
+	// When migration is on progress
+	o.Status.NodeGroupMigrations = []NodeGroupMigrationStatus{
+		{
+			NodeGroup: "data",
+			Successor: "data-ssd",
+			Phase: NodeGroupMigrationRelocating,
+		},
+	}
+	assert.Equal(t, 2, len(o.GetActiveNodeGroups()))
+	assert.Equal(t, NodeGroupMigrationRelocating, o.GetNodeGroupMigration("data").Phase)
+	assert.False(t, o.IsNodeGroupMigrated("data"))
+	assert.Equal(t, []string{"test-data-os-0", "test-data-ssd-os-0"}, o.GetNodeNames())
+
+	// When migration is completed
+	o.Status.NodeGroupMigrations[0].Phase = NodeGroupMigrationCompleted
+	assert.Equal(t, 1, len(o.GetActiveNodeGroups()))
+	assert.Equal(t, "data-ssd", o.GetActiveNodeGroups()[0].Name)
+	assert.True(t, o.IsNodeGroupMigrated("data"))
+	assert.Equal(t, []string{"test-data-ssd-os-0"}, o.GetNodeNames())
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sts))
+	assert.Equal(t, "test-data-ssd-os", sts[0].Name)
+}
+
+func TestGenerateWithSnapshotRepositories(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+			SetVMMaxMapCount: pointer.Bool(false),
+			SnapshotRepositories: []SnapshotRepositorySpec{
+				{
+					Name: "backup",
+					Type: "fs",
+					Settings: map[string]string{
+						"location": "/mnt/snapshots",
+					},
+					Volume: &corev1.VolumeSource{
+						NFS: &corev1.NFSVolumeSource{
+							Server: "nfs.cluster.local",
+							Path: "/snapshots",
+						},
+					},
+				},
+				{
+					Name: "s3",
+					Type: "s3",
+					Settings: map[string]string{
+						"bucket": "snapshots",
+					},
+					CredentialsSecretRef: "s3-credentials",
+				},
+			},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+			},
+		},
+	}
+
+	// Location of fs repository is allowed on path.repo
+	configMaps, err := o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "path.repo:")
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "/mnt/snapshots")
+
+	// Credentials are injected on keystore by init container
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "keystore", sts[0].Spec.Template.Spec.InitContainers[0].Name)
+	assert.Contains(t, sts[0].Spec.Template.Spec.InitContainers[0].Command[2], "s3.client.default.${key}")
+	assert.Contains(t, sts[0].Spec.Template.Spec.InitContainers[0].VolumeMounts, corev1.VolumeMount{
+		Name: "keystore-s3",
+		MountPath: "/mnt/keystore-sources/s3",
+		ReadOnly: true,
+	})
+	assert.Contains(t, sts[0].Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "keystore-s3",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: "s3-credentials",
+			},
+		},
+	})
+	assert.Contains(t, sts[0].Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name: "keystore",
+		MountPath: "/usr/share/opensearch/config/opensearch.keystore",
+		SubPath: "opensearch.keystore",
+	})
+
+	// Volume of fs repository is mounted on location
+	assert.Contains(t, sts[0].Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name: "snapshot-repository-backup",
+		MountPath: "/mnt/snapshots",
+	})
+	assert.Contains(t, sts[0].Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "snapshot-repository-backup",
+		VolumeSource: *o.Spec.SnapshotRepositories[0].Volume,
+	})
+
+	// Pods are restarted when credentials change
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "s3-credentials",
+		},
+		Data: map[string][]byte{
+			"access_key": []byte("access"),
+			"secret_key": []byte("secret"),
+		},
+	}
+	sts, err = o.GenerateStatefullsets(map[string]*corev1.Secret{"s3-credentials": secret})
+	assert.NoError(t, err)
+	keystoreHash := sts[0].Spec.Template.Annotations[keystoreHashAnnotation]
+	assert.NotEmpty(t, keystoreHash)
+	secret.Data["secret_key"] = []byte("new-secret")
+	sts, err = o.GenerateStatefullsets(map[string]*corev1.Secret{"s3-credentials": secret})
+	assert.NoError(t, err)
+	assert.NotEqual(t, keystoreHash, sts[0].Spec.Template.Annotations[keystoreHashAnnotation])
+
+	// Without credentials
+	o.Spec.SnapshotRepositories[1].CredentialsSecretRef = ""
+	sts, err = o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(sts[0].Spec.Template.Spec.InitContainers))
+	assert.NotContains(t, sts[0].Spec.Template.Annotations, keystoreHashAnnotation)
+
+	// When fs repository have not volume
+	volume := o.Spec.SnapshotRepositories[0].Volume
+	o.Spec.SnapshotRepositories[0].Volume = nil
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+
+	// When other repository have volume
+	o.Spec.SnapshotRepositories[0].Volume = volume
+	o.Spec.SnapshotRepositories[1].Volume = volume
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+	o.Spec.SnapshotRepositories[1].Volume = nil
+
+	// When fs repository have not location
+	o.Spec.SnapshotRepositories[0].Settings = nil
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+
+	// When repository name is duplicated
+	o.Spec.SnapshotRepositories[0].Settings = map[string]string{"location": "/mnt/snapshots"}
+	o.Spec.SnapshotRepositories[1].Name = "backup"
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+}
+
+func TestGenerateWithRestore(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			SnapshotRepositories: []SnapshotRepositorySpec{
+				{
+					Name: "backup",
+					Type: "fs",
+					Settings: map[string]string{
+						"location": "/mnt/snapshots",
+					},
+					Volume: &corev1.VolumeSource{
+						NFS: &corev1.NFSVolumeSource{
+							Server: "nfs.cluster.local",
+							Path: "/snapshots",
+						},
+					},
+				},
+			},
+			Restore: &RestoreSpec{
+				Repository: "backup",
+				Snapshot: "daily-2022.11.01",
+			},
+			Endpoint: &EndpointSpec{
+				Ingress: &IngressSpec{
+					Enabled: true,
+					Host: "my-test.cluster.local",
+				},
+				LoadBalancer: &LoadBalancerSpec{
+					Enabled: true,
+				},
+			},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 1,
+				},
+			},
+		},
+	}
+
+	// When restore is added on existing cluster, with empty phase
+	status := o.GenerateRestoreStatus([]appv1.StatefulSet{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name: "test-all-os",
+			},
+		},
+	})
+	assert.Equal(t, RestoreIgnored, status.Phase)
+	o.Status.Restore = status
+	assert.False(t, o.IsRestoreInProgress())
+	assert.True(t, o.IsRestoreIgnored())
+	ingress, err := o.GenerateIngress()
+	assert.NoError(t, err)
+	assert.NotNil(t, ingress)
+	service, err := o.GenerateLoadbalancer()
+	assert.NoError(t, err)
+	assert.NotNil(t, service)
+
+	// When restore is not yet initialized, external endpoints are kept
+	o.Status.Restore = nil
+	assert.False(t, o.IsRestoreInProgress())
+	assert.False(t, o.IsRestoreIgnored())
+	ingress, err = o.GenerateIngress()
+	assert.NoError(t, err)
+	assert.NotNil(t, ingress)
+
+	// External endpoints are not generated while restore is in progress on new cluster
+	status = o.GenerateRestoreStatus(nil)
+	assert.Equal(t, RestorePending, status.Phase)
+	o.Status.Restore = status
+	assert.True(t, o.IsRestoreInProgress())
+	assert.False(t, o.IsRestoreIgnored())
+	ingress, err = o.GenerateIngress()
+	assert.NoError(t, err)
+	assert.Nil(t, ingress)
+	service, err = o.GenerateLoadbalancer()
+	assert.NoError(t, err)
+	assert.Nil(t, service)
+	_, err = o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+
+	// When restore is started, the cluster is not yet ready
+	o.Status.Phase = OpensearchPhaseRestoring
+	o.Status.Restore = &RestoreStatus{
+		Phase: RestoreRunning,
+	}
+	assert.True(t, o.IsRestoreInProgress())
+	assert.False(t, o.IsRestoreIgnored())
+
+	// When restore is completed
+	o.Status.Restore = &RestoreStatus{
+		Phase: RestoreCompleted,
+	}
+	assert.False(t, o.IsRestoreInProgress())
+	assert.False(t, o.IsRestoreIgnored())
+	ingress, err = o.GenerateIngress()
+	assert.NoError(t, err)
+	assert.NotNil(t, ingress)
+	service, err = o.GenerateLoadbalancer()
+	assert.NoError(t, err)
+	assert.NotNil(t, service)
+
+	// When rename replacement is missing
+	o.Spec.Restore.RenamePattern = "(.+)"
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+
+	// When repository is not declared
+	o.Spec.Restore.RenamePattern = ""
+	o.Spec.Restore.Repository = "s3"
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+}
+
+func TestComputeClusterSettingsToApply(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+	}
+
+	// When no cluster settings
+	assert.Empty(t, o.ComputeClusterSettingsToApply(map[string]any{
+		"cluster.routing.allocation.enable": "primaries",
+	}))
+
+	// When settings must be applied
+	o.Spec.ClusterSettings = map[string]string{
+		"action.destructive_requires_name": "true",
+		"cluster.routing.allocation.disk.watermark.low": "90%",
+		"cluster.routing.allocation.awareness.attributes": "zone,rack",
+	}
+	assert.Equal(t, map[string]any{
+		"action.destructive_requires_name": "true",
+		"cluster.routing.allocation.disk.watermark.low": "90%",
+	}, o.ComputeClusterSettingsToApply(map[string]any{
+		"action.destructive_requires_name": "false",
+		"cluster.routing.allocation.awareness.attributes": []any{"zone", "rack"},
+		"cluster.routing.allocation.enable": "primaries",
+	}))
+
+	// When settings are already applied
+	assert.Empty(t, o.ComputeClusterSettingsToApply(map[string]any{
+		"action.destructive_requires_name": "true",
+		"cluster.routing.allocation.disk.watermark.low": "90%",
+		"cluster.routing.allocation.awareness.attributes": []any{"zone", "rack"},
+	}))
+
+	// When managed setting is removed from spec
+	o.Status.ClusterSettings = &ClusterSettingsStatus{
+		Revision: 1,
+		Keys: []string{
+			"action.destructive_requires_name",
+			"cluster.routing.allocation.awareness.attributes",
+			"cluster.routing.allocation.disk.watermark.high",
+			"cluster.routing.allocation.disk.watermark.low",
+		},
+	}
+	delete(o.Spec.ClusterSettings, "cluster.routing.allocation.disk.watermark.low")
+	assert.Equal(t, map[string]any{
+		"cluster.routing.allocation.disk.watermark.low": nil,
+	}, o.ComputeClusterSettingsToApply(map[string]any{
+		"action.destructive_requires_name": "true",
+		"cluster.routing.allocation.disk.watermark.low": "90%",
+		"cluster.routing.allocation.awareness.attributes": []any{"zone", "rack"},
+	}))
+}
+
+func TestRemoteClusters(t *testing.T) {
+	leader := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "leader",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+					},
+				},
+				{
+					Name: "data",
+					Replicas: 2,
+					Roles: []string{
+						"data",
+					},
+				},
+			},
+		},
+	}
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 1,
+				},
+			},
+			RemoteClusters: []RemoteClusterSpec{
+				{
+					Name: "leader",
+					OpensearchRef: &RemoteOpensearchRef{
+						Name: "test",
+						Namespace: "leader",
+					},
+					Replication: &RemoteClusterReplicationSpec{
+						AutoFollowRules: []AutoFollowRuleSpec{
+							{
+								Name: "logs",
+								Pattern: "logs-*",
+								LeaderClusterRole: "leader_role",
+								FollowerClusterRole: "follower_role",
+							},
+						},
+					},
+				},
+				{
+					Name: "external",
+					Seeds: []string{"external-0:9300", "external-1:9300"},
+					CaSecretRef: "external-ca",
+					SkipUnavailable: true,
+				},
+			},
+		},
+	}
+
+	// Seeds are the headless services of master node groups
+	assert.Equal(t, []string{"test-master-os-headless.leader.svc:9300"}, leader.GetTransportSeeds())
+
+	// Reverse reference
+	assert.True(t, leader.IsRemoteClusterOf(o))
+	assert.False(t, o.IsRemoteClusterOf(leader))
+	assert.Equal(t, "leader", o.GetRemoteOpensearchNamespace(&o.Spec.RemoteClusters[0]))
+	assert.Equal(t, "default", o.GetRemoteOpensearchNamespace(&o.Spec.RemoteClusters[1]))
+
+	configMaps, err := o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "CN=test-*,OU=Opensearch node,O=Opensearch Org,L=TORONTO,ST=ONTARIO,C=US")
+	assert.NotContains(t, configMaps[0].Data["opensearch.yml"], "CN=*.external")
+
+	// When external remote cluster provide nodes DN
+	o.Spec.RemoteClusters[1].NodesDN = []string{"CN=*.external,O=External"}
+	configMaps, err = o.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "CN=*.external,O=External")
+	o.Spec.RemoteClusters[1].NodesDN = nil
+
+	// The nodes of clusters that declare this cluster as remote cluster are accepted
+	follower := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "leader",
+			Name: "follower",
+		},
+		Spec: OpensearchSpec{
+			RemoteClusters: []RemoteClusterSpec{
+				{
+					Name: "test",
+					OpensearchRef: &RemoteOpensearchRef{
+						Name: "test",
+					},
+				},
+			},
+		},
+	}
+	leader.Spec.RemoteClusters = nil
+	configMaps, err = leader.GenerateConfigMaps(nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, configMaps[0].Data["opensearch.yml"], "nodes_dn")
+	configMaps, err = leader.GenerateConfigMaps([]Opensearch{*o, *follower})
+	assert.NoError(t, err)
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "CN=test-*,OU=Opensearch node")
+	assert.Contains(t, configMaps[0].Data["opensearch.yml"], "CN=follower-*,OU=Opensearch node")
+
+	// When seeds are missing
+	o.Spec.RemoteClusters[1].Seeds = nil
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+	o.Spec.RemoteClusters[1].Seeds = []string{"external-0:9300", "external-1:9300"}
+
+	// When the cluster reference itself
+	o.Spec.RemoteClusters[0].OpensearchRef.Namespace = ""
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+	o.Spec.RemoteClusters[0].OpensearchRef.Namespace = "leader"
+
+	// When roles are missing with security plugin
+	o.Spec.RemoteClusters[0].Replication.AutoFollowRules[0].LeaderClusterRole = ""
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+	o.Spec.RemoteClusters[0].Replication.AutoFollowRules[0].LeaderClusterRole = "leader_role"
+
+	// When remote cluster is declared more than once
+	o.Spec.RemoteClusters[1].Name = "leader"
+	_, err = o.GenerateConfigMaps(nil)
+	assert.Error(t, err)
+	o.Spec.RemoteClusters[1].Name = "external"
+}
+
+func TestComputeRemoteClusterSettingsToApply(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			RemoteClusters: []RemoteClusterSpec{
+				{
+					Name: "leader",
+					OpensearchRef: &RemoteOpensearchRef{
+						Name: "leader",
+					},
+				},
+				{
+					Name: "external",
+					Seeds: []string{"external-0:9300", "external-1:9300"},
+					SkipUnavailable: true,
+				},
+			},
+		},
+	}
+	seeds := map[string][]string{
+		"leader": {"leader-master-os-headless.default.svc:9300"},
+		"external": {"external-0:9300", "external-1:9300"},
+	}
+
+	// When remote clusters are not yet configured
+	assert.Equal(t, map[string]any{
+		"cluster.remote.leader.seeds": []string{"leader-master-os-headless.default.svc:9300"},
+		"cluster.remote.external.seeds": []string{"external-0:9300", "external-1:9300"},
+		"cluster.remote.external.skip_unavailable": true,
+	}, o.ComputeRemoteClusterSettingsToApply(seeds, map[string]any{}))
+
+	// When remote clusters are already configured
+	current := map[string]any{
+		"cluster.remote.leader.seeds": []any{"leader-master-os-headless.default.svc:9300"},
+		"cluster.remote.external.seeds": []any{"external-0:9300", "external-1:9300"},
+		"cluster.remote.external.skip_unavailable": "true",
+	}
+	assert.Empty(t, o.ComputeRemoteClusterSettingsToApply(seeds, current))
+
+	// When the remote cluster managed by operator not yet exist
+	assert.Equal(t, map[string]any{
+		"cluster.remote.external.seeds": []string{"external-0:9300", "external-1:9300"},
+		"cluster.remote.external.skip_unavailable": true,
+	}, o.ComputeRemoteClusterSettingsToApply(map[string][]string{"external": seeds["external"]}, map[string]any{}))
+
+	// When remote cluster is removed from spec
+	o.Status.RemoteClusters = []RemoteClusterStatus{
+		{
+			Name: "leader",
+			Connected: true,
+		},
+		{
+			Name: "external",
+			Connected: true,
+		},
+	}
+	o.Spec.RemoteClusters = o.Spec.RemoteClusters[:1]
+	assert.Equal(t, map[string]any{
+		"cluster.remote.external.seeds": nil,
+		"cluster.remote.external.skip_unavailable": nil,
+	}, o.ComputeRemoteClusterSettingsToApply(seeds, current))
+}
+
+func TestMonitoringPlugin(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+			Plugins: []PluginSpec{{Name: "repository-s3"}},
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+			},
+			Monitoring: &MonitoringSpec{
+				Enabled: true,
+			},
+		},
+	}
+
+	// The plugin match the node group version
+	assert.Equal(t, []PluginSpec{
+		{Name: "repository-s3"},
+		{
+			Name: "prometheus-exporter",
+			Url: "https://github.com/aiven/prometheus-exporter-plugin-for-opensearch/releases/download/2.3.0.0/prometheus-exporter-2.3.0.0.zip",
+		},
+	}, o.GetNodeGroupPlugins(&o.Spec.NodeGroups[0]))
+	assert.Equal(t, []PluginSpec{{Name: "repository-s3"}}, o.Spec.Plugins)
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	assert.Len(t, sts[0].Spec.Template.Spec.Containers, 1)
+
+	// When the plugin source is custom
+	o.Spec.Monitoring.Plugin = &PluginSpec{Image: "registry.local/plugins/prometheus-exporter:2.3.0"}
+	assert.Equal(t, []PluginSpec{
+		{Name: "repository-s3"},
+		{
+			Name: "prometheus-exporter",
+			Image: "registry.local/plugins/prometheus-exporter:2.3.0",
+		},
+	}, o.GetNodeGroupPlugins(&o.Spec.NodeGroups[0]))
+
+	// When the plugin is already installed by user
+	o.Spec.NodeGroups[0].Plugins = []PluginSpec{{Name: "prometheus-exporter", Url: "https://repo.local/prometheus-exporter.zip"}}
+	assert.Equal(t, []PluginSpec{{Name: "prometheus-exporter", Url: "https://repo.local/prometheus-exporter.zip"}}, o.GetNodeGroupPlugins(&o.Spec.NodeGroups[0]))
+	o.Spec.NodeGroups[0].Plugins = nil
+
+	// When the version can't be used to compute plugin source
+	o.Spec.Monitoring.Plugin = nil
+	o.Spec.NodeGroups[0].Version = "latest"
+	_, err = o.GenerateStatefullsets(nil)
+	assert.Error(t, err)
+
+	// When monitoring use exporter
+	o.Spec.Monitoring.Mode = MonitoringModeExporter
+	assert.Equal(t, []PluginSpec{{Name: "repository-s3"}}, o.GetNodeGroupPlugins(&o.Spec.NodeGroups[0]))
+	_, err = o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+}
+
+func TestGenerateWithMonitoringExporter(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+			SetVMMaxMapCount: pointer.Bool(false),
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+					Roles: []string{
+						"cluster_manager",
+						"data",
+					},
+				},
+			},
+			Monitoring: &MonitoringSpec{
+				Enabled: true,
+				Mode: MonitoringModeExporter,
+				Exporter: &MonitoringExporterSpec{
+					Args: []string{"--es.indices"},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("64Mi"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	sts, err := o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-statefullset-exporter.yml", sts[0])
+
+	// When security plugin is disabled, the exporter read metrics on localhost
+	o.Spec.DisableSecurityPlugin = true
+	sts, err = o.GenerateStatefullsets(nil)
+	assert.NoError(t, err)
+	exporter := sts[0].Spec.Template.Spec.Containers[1]
+	assert.Equal(t, "exporter", exporter.Name)
+	assert.Contains(t, exporter.Command[2], "--es.uri=http://localhost:9200")
+	assert.NotContains(t, exporter.Command[2], "ES_PASSWORD")
+	assert.Empty(t, exporter.VolumeMounts)
+}
+
+func TestGenerateMonitoring(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "all",
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	// When monitoring is disabled
+	assert.Nil(t, o.GenerateMonitoringUser())
+	assert.Nil(t, o.GenerateMonitoringRole())
+	assert.Nil(t, o.GenerateMonitoringRoleMapping())
+	s, err := o.GenerateMonitoringService()
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+	m, err := o.GenerateMonitor()
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+
+	// With plugin mode and ServiceMonitor
+	o.Spec.Monitoring = &MonitoringSpec{
+		Enabled: true,
+		Interval: "10s",
+		Labels: map[string]string{
+			"release": "prometheus",
+		},
+	}
+	assert.Equal(t, "test-os-monitoring", o.GenerateMonitoringUser().Name)
+	assert.Equal(t, "test-os-monitoring-os-user", o.GetSecretNameForMonitoringUser())
+	test.EqualFromYamlFile(t, "../../fixture/api/os-monitoring-role.yml", o.GenerateMonitoringRole())
+	assert.Equal(t, []string{"test-os-monitoring"}, o.GenerateMonitoringRoleMapping().Spec.Users)
+	s, err = o.GenerateMonitoringService()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-monitoring-service.yml", s)
+	m, err = o.GenerateMonitor()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-servicemonitor.yml", m)
+
+	// With exporter mode and PodMonitor
+	o.Spec.Monitoring.Mode = MonitoringModeExporter
+	o.Spec.Monitoring.MonitorType = MonitorTypePodMonitor
+	s, err = o.GenerateMonitoringService()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(9114), s.Spec.Ports[0].Port)
+	m, err = o.GenerateMonitor()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-podmonitor.yml", m)
+
+	// When Prometheus operator is not used
+	o.Spec.Monitoring.MonitorType = MonitorTypeNone
+	m, err = o.GenerateMonitor()
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+
+	// When security plugin is disabled
+	o.Spec.DisableSecurityPlugin = true
+	assert.Nil(t, o.GenerateMonitoringUser())
+	assert.Nil(t, o.GenerateMonitoringRole())
+	assert.Nil(t, o.GenerateMonitoringRoleMapping())
+}
+
+func TestComputeAllocationExclusion(t *testing.T) {
+	// When no node is excluded
+	assert.Equal(t, "test-old-os-0,test-old-os-1", ComputeAllocationExclusion(nil, []string{"test-old-os-0", "test-old-os-1"}, nil))
+
+	// When other nodes are already excluded by user or by other migration
+	assert.Equal(t, "node-1,test-old-os-0,test-old-os-1", ComputeAllocationExclusion("node-1, test-old-os-0", []string{"test-old-os-0", "test-old-os-1"}, nil))
+
+	// When migration is completed, only its nodes are released
+	assert.Equal(t, "node-1,test-data-os-0", ComputeAllocationExclusion("node-1,test-old-os-0,test-old-os-1,test-data-os-0", nil, []string{"test-old-os-0", "test-old-os-1"}))
+
+	// When the last excluded nodes are released
+	assert.Nil(t, ComputeAllocationExclusion("test-old-os-0", nil, []string{"test-old-os-0"}))
+}
@@ -1,28 +1,64 @@
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/thoas/go-funk"
 	"github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	defaultImage = "public.ecr.aws/opensearchproject/opensearch"
+	clusterDomain = "cluster.local"
+	defaultAwarenessTopologyKey = "topology.kubernetes.io/zone"
+	awarenessPath = "/mnt/awareness"
+	pluginsPath = "/mnt/plugins"
+	pluginSourcesPath = "/mnt/plugin-sources"
+	pluginsCacheSubPath = ".plugins-cache"
+	// nodeDNTemplate is the DN of the node transport certificates, it must match the identity used by pki.NewNodeTLS
+	nodeDNTemplate = "CN=%s,OU=Opensearch node,O=Opensearch Org,L=TORONTO,ST=ONTARIO,C=US"
+	defaultPluginImagePath = "/plugin.zip"
+	pluginsHashAnnotation = "opensearch.k8s.webcenter.fr/plugins-hash"
+	keystoreHashAnnotation = "opensearch.k8s.webcenter.fr/keystore-hash"
+	securityPluginName = "opensearch-security"
+	keystorePath = "/mnt/keystore"
+	keystoreSourcesPath = "/mnt/keystore-sources"
+	defaultSnapshotRepositoryClient = "default"
+	operatorPodLabel = "app.kubernetes.io/name"
+	operatorName = "opensearch-operator"
+	monitoringPluginName = "prometheus-exporter"
+	monitoringPluginUrl = "https://github.com/aiven/prometheus-exporter-plugin-for-opensearch/releases/download/%s.0/prometheus-exporter-%s.0.zip"
+	defaultMonitoringExporterImage = "quay.io/prometheuscommunity/elasticsearch-exporter"
+	defaultMonitoringExporterVersion = "v1.5.0"
+	monitoringExporterContainerName = "exporter"
+	monitoringExporterPort = 9114
+	monitoringCredentialsPath = "/mnt/monitoring-credentials"
+	monitoringCertsPath = "/mnt/monitoring-certs"
+	defaultMonitoringInterval = "30s"
 )
 
 var (
-	roleList = []string {
-		"cluster_manager",
-		"data",
-		"ingest",
-		"ml",
-		"remote_cluster_client",
-		"transform",
+	settingNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+	versionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)`)
+	pluginNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9\-]*[a-z0-9])?$`)
+	checksumRegexp = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+	// lastMinorVersions is the last minor version of each major version
+	// Only the last minor version is wire compatible with the next major version
+	lastMinorVersions = map[int]int{
+		1: 3,
+		2: 19,
 	}
 )
 
@@ -44,7 +80,7 @@ func (h * Opensearch) computeJavaOpts(nodeGroup *NodeGroupSpec) string {
 // computeInitialMasterNodes create the list of all master nodes
 func (h *Opensearch) computeInitialMasterNodes() string {
 	masterNodes := make([]string, 0, 3)
-	for _, nodeGroup := range h.Spec.NodeGroups {
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
 		if h.IsMasterRole(&nodeGroup) {
 			masterNodes = append(masterNodes, h.GetNodeGroupNodeNames(&nodeGroup)...)
 		}
@@ -57,7 +93,7 @@ func (h *Opensearch) computeInitialMasterNodes() string {
 func (h *Opensearch) computeDiscoverySeedHosts() string {
 	serviceNames := make([]string, 0, 1)
 
-	for _, nodeGroup := range h.Spec.NodeGroups {
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
 		if h.IsMasterRole(&nodeGroup) {
 			serviceNames = append(serviceNames, h.GetNodeGroupServiceNameHeadless(nodeGroup.Name))
 		}
@@ -68,27 +104,320 @@ func (h *Opensearch) computeDiscoverySeedHosts() string {
 
 
 // computeRoles permit to compute les roles of node groups
+// It use node.roles setting, so it support built-in roles like search and custom roles like warm
+// When roles is empty, the node is coordinating only. The empty env is skipped by docker entrypoint, so node.roles is set on config
 func (h *Opensearch) computeRoles(roles []string) (envs []corev1.EnvVar) {
-	envs = make([]corev1.EnvVar, 0, len(roles))
-
-	for _, role :=  range roleList {
-		if funk.ContainsString(roles, role) {
-			envs = append(envs, corev1.EnvVar{
-				Name: fmt.Sprintf("node.%s", role),
-				Value: "true",
-			})
-		} else {
-			envs = append(envs, corev1.EnvVar{
-				Name: fmt.Sprintf("node.%s", role),
-				Value: "false",
-			})
-		}
+	if len(roles) == 0 {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: "node.roles",
+			Value: strings.Join(roles, ","),
+		},
 	}
+}
+
+// computeNodeAttributes permit to compute the custom node attributes of node groups
+// Each attribute is set as node.attr.<key> setting. It usefull to move indices between tiers with ISM
+func (h *Opensearch) computeNodeAttributes(nodeGroup *NodeGroupSpec) (envs []corev1.EnvVar) {
+	envs = make([]corev1.EnvVar, 0, len(nodeGroup.NodeAttributes))
 
+	keys := funk.Keys(nodeGroup.NodeAttributes).([]string)
+	sort.Strings(keys)
+	for _, key := range keys {
+		envs = append(envs, corev1.EnvVar{
+			Name: fmt.Sprintf("node.attr.%s", key),
+			Value: nodeGroup.NodeAttributes[key],
+		})
+	}
 
 	return envs
 }
 
+// checkNodeGroupRoles permit to check that roles and node attributes of node group are valid settings name
+func (h *Opensearch) checkNodeGroupRoles(nodeGroup *NodeGroupSpec) (err error) {
+	for _, role := range nodeGroup.Roles {
+		if !settingNameRegexp.MatchString(role) {
+			return errors.Errorf("Role '%s' is not valid on node group %s", role, nodeGroup.Name)
+		}
+	}
+
+	for key := range nodeGroup.NodeAttributes {
+		if !settingNameRegexp.MatchString(key) {
+			return errors.Errorf("Node attribute '%s' is not valid on node group %s", key, nodeGroup.Name)
+		}
+	}
+
+	return nil
+}
+
+// checkVersionsCompatibility permit to check that versions of all node groups are wire compatible
+// Nodes can communicate if they are on the same major version, or if the older is the last minor version of the previous major version
+// Versions that can't be parsed, like latest, are not checked
+func (h *Opensearch) checkVersionsCompatibility() (err error) {
+	type nodeGroupVersion struct {
+		name string
+		major int
+		minor int
+	}
+	versions := make([]nodeGroupVersion, 0, len(h.Spec.NodeGroups))
+
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
+		version := h.GetNodeGroupVersion(&nodeGroup)
+		major, minor, ok := parseVersion(version)
+		if !ok {
+			continue
+		}
+		versions = append(versions, nodeGroupVersion{
+			name: nodeGroup.Name,
+			major: major,
+			minor: minor,
+		})
+	}
+
+	for i := 0; i < len(versions); i++ {
+		for j := i + 1; j < len(versions); j++ {
+			older, newer := versions[i], versions[j]
+			if older.major > newer.major {
+				older, newer = newer, older
+			}
+			if older.major == newer.major {
+				continue
+			}
+			if newer.major - older.major == 1 {
+				if lastMinor, ok := lastMinorVersions[older.major]; ok && older.minor >= lastMinor {
+					continue
+				}
+			}
+			return errors.Errorf("Version of node group %s is not wire compatible with version of node group %s", older.name, newer.name)
+		}
+	}
+
+	return nil
+}
+
+// checkSuccessorNodeGroups permit to check that node groups are not successor of themself, and that an old node group have only one successor
+func (h *Opensearch) checkSuccessorNodeGroups() (err error) {
+	successors := map[string]string{}
+
+	for _, nodeGroup := range h.GetSuccessorNodeGroups() {
+		if nodeGroup.SuccessorOf == nodeGroup.Name {
+			return errors.Errorf("Node group %s can't be successor of itself", nodeGroup.Name)
+		}
+		if successor, ok := successors[nodeGroup.SuccessorOf]; ok {
+			return errors.Errorf("Node group %s have already successor %s, it can't be migrated on %s", nodeGroup.SuccessorOf, successor, nodeGroup.Name)
+		}
+		successors[nodeGroup.SuccessorOf] = nodeGroup.Name
+	}
+
+	return nil
+}
+
+// parseVersion permit to extract major and minor from version
+// It return false if the version can't be parsed
+func parseVersion(version string) (major int, minor int, ok bool) {
+	matches := versionRegexp.FindStringSubmatch(version)
+	if matches == nil {
+		return 0, 0, false
+	}
+
+	major, _ = strconv.Atoi(matches[1])
+	minor, _ = strconv.Atoi(matches[2])
+
+	return major, minor, true
+}
+
+// getPluginsVolume permit to get the volume and the sub path where plugins are installed by init container
+// The plugins are cached on data volume when node group have persistence, so they are not reinstalled on each pod start
+func (h *Opensearch) getPluginsVolume(nodeGroup *NodeGroupSpec) (volumeName string, subPath string) {
+	if nodeGroup.Persistence != nil && (nodeGroup.Persistence.Volume != nil || nodeGroup.Persistence.VolumeClaimSpec != nil) {
+		return "opensearch-data", pluginsCacheSubPath
+	}
+
+	return "plugins", ""
+}
+
+// hasNodeGroupPlugins return true if there are some plugins to install or to remove on node group
+func (h *Opensearch) hasNodeGroupPlugins(nodeGroup *NodeGroupSpec) bool {
+	return len(h.GetNodeGroupPluginsList(nodeGroup)) > 0 || len(h.GetNodeGroupPlugins(nodeGroup)) > 0 || len(h.GetPluginsToRemove()) > 0
+}
+
+// checkDisableSecurityPlugin permit to check that security plugin is not disabled while the operator manage security config
+// The security config is applied with admin certificate, so it need security plugin
+func (h *Opensearch) checkDisableSecurityPlugin() (err error) {
+	if !h.Spec.DisableSecurityPlugin {
+		return nil
+	}
+
+	if h.Spec.GlobalNodeGroup.SecurityRef != "" {
+		return errors.New("Security plugin can't be disabled when security config is set on global node group")
+	}
+	for _, nodeGroup := range h.Spec.NodeGroups {
+		if nodeGroup.SecurityRef != "" {
+			return errors.Errorf("Security plugin can't be disabled when security config is set on node group %s", nodeGroup.Name)
+		}
+	}
+
+	return nil
+}
+
+// checkNodeGroupPlugins permit to check that plugins of node group have valid name, one source at most and valid checksum
+func (h *Opensearch) checkNodeGroupPlugins(nodeGroup *NodeGroupSpec) (err error) {
+	for _, plugin := range h.GetNodeGroupPlugins(nodeGroup) {
+		if !pluginNameRegexp.MatchString(plugin.Name) {
+			return errors.Errorf("Plugin name '%s' is not valid on node group %s", plugin.Name, nodeGroup.Name)
+		}
+
+		nbSources := 0
+		if plugin.Url != "" {
+			nbSources++
+		}
+		if plugin.ConfigMapRef != nil {
+			nbSources++
+		}
+		if plugin.SecretRef != nil {
+			nbSources++
+		}
+		if plugin.Image != "" {
+			nbSources++
+		}
+		if nbSources > 1 {
+			return errors.Errorf("Plugin %s must have only one source on node group %s", plugin.Name, nodeGroup.Name)
+		}
+
+		if plugin.Checksum != "" && !checksumRegexp.MatchString(plugin.Checksum) {
+			return errors.Errorf("Checksum of plugin %s must be sha256 on node group %s", plugin.Name, nodeGroup.Name)
+		}
+	}
+
+	return nil
+}
+
+// computePluginsHash permit to compute the hash of the plugin set of node group
+// Plugins depend of Opensearch version, so the image is part of the hash
+func (h *Opensearch) computePluginsHash(nodeGroup *NodeGroupSpec) (hash string, err error) {
+	plugins, err := json.Marshal(h.GetNodeGroupPlugins(nodeGroup))
+	if err != nil {
+		return "", errors.Wrapf(err, "Error when marshall plugins of node group %s", nodeGroup.Name)
+	}
+
+	sum := sha256.New()
+	sum.Write([]byte(h.GetNodeGroupContainerImage(nodeGroup)))
+	sum.Write([]byte(strings.Join(h.GetNodeGroupPluginsList(nodeGroup), ",")))
+	sum.Write(plugins)
+	sum.Write([]byte(strings.Join(h.GetPluginsToRemove(), ",")))
+
+	return fmt.Sprintf("%x", sum.Sum(nil)), nil
+}
+
+// computePluginsScript permit to compute the init container script that install plugins on shared volume
+// It skip installation when the plugins set hash not changed, it only happen when plugins are cached on data volume
+func (h *Opensearch) computePluginsScript(nodeGroup *NodeGroupSpec) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf(`#!/usr/bin/env bash
+set -euo pipefail
+
+if [ -f %s/hash ] && [ "$(cat %s/hash)" == "${PLUGINS_HASH}" ]; then
+  echo "Plugins are already installed"
+  exit 0
+fi
+
+`, pluginsPath, pluginsPath))
+
+	for _, plugin := range h.GetPluginsToRemove() {
+		sb.WriteString(fmt.Sprintf("./bin/opensearch-plugin remove %s\n", plugin))
+	}
+
+	for _, plugin := range h.GetNodeGroupPluginsList(nodeGroup) {
+		sb.WriteString(fmt.Sprintf("./bin/opensearch-plugin install -b %s\n", plugin))
+	}
+
+	for _, plugin := range h.GetNodeGroupPlugins(nodeGroup) {
+		source := ""
+		switch {
+		case plugin.Url != "" && plugin.Checksum == "":
+			sb.WriteString(fmt.Sprintf("./bin/opensearch-plugin install -b %s\n", plugin.Url))
+			continue
+		case plugin.Url != "":
+			source = fmt.Sprintf("/tmp/%s.zip", plugin.Name)
+			sb.WriteString(fmt.Sprintf("curl -sSfL -o %s %s\n", source, plugin.Url))
+		case plugin.ConfigMapRef != nil || plugin.SecretRef != nil || plugin.Image != "":
+			source = getPluginSourceFile(&plugin)
+		default:
+			sb.WriteString(fmt.Sprintf("./bin/opensearch-plugin install -b %s\n", plugin.Name))
+			continue
+		}
+
+		if plugin.Checksum != "" {
+			sb.WriteString(fmt.Sprintf("echo \"%s  %s\" | sha256sum -c -\n", plugin.Checksum, source))
+		}
+		sb.WriteString(fmt.Sprintf("./bin/opensearch-plugin install -b file://%s\n", source))
+	}
+
+	sb.WriteString(fmt.Sprintf(`rm -rf %s/plugins
+cp -a plugins %s/plugins
+echo -n "${PLUGINS_HASH}" > %s/hash
+`, pluginsPath, pluginsPath, pluginsPath))
+
+	return sb.String()
+}
+
+// computePluginsCheckScript permit to compute the init container script that check plugins baked on image
+// It write missing plugins on termination message, so the operator can report them on status
+func (h *Opensearch) computePluginsCheckScript(nodeGroup *NodeGroupSpec) string {
+	var sb strings.Builder
+
+	expectedPlugins := make([]string, 0, len(h.GetNodeGroupPluginsList(nodeGroup)) + len(h.GetNodeGroupPlugins(nodeGroup)))
+	expectedPlugins = append(expectedPlugins, h.GetNodeGroupPluginsList(nodeGroup)...)
+	for _, plugin := range h.GetNodeGroupPlugins(nodeGroup) {
+		expectedPlugins = append(expectedPlugins, plugin.Name)
+	}
+
+	sb.WriteString(`#!/usr/bin/env bash
+set -euo pipefail
+
+INSTALLED=$(./bin/opensearch-plugin list)
+MISSING=""
+NOT_REMOVED=""
+`)
+	if len(expectedPlugins) > 0 {
+		sb.WriteString(fmt.Sprintf(`for plugin in %s; do
+  if ! echo "${INSTALLED}" | grep -qx "${plugin}"; then
+    MISSING="${MISSING} ${plugin}"
+  fi
+done
+`, strings.Join(expectedPlugins, " ")))
+	}
+	if len(h.GetPluginsToRemove()) > 0 {
+		sb.WriteString(fmt.Sprintf(`for plugin in %s; do
+  if echo "${INSTALLED}" | grep -qx "${plugin}"; then
+    NOT_REMOVED="${NOT_REMOVED} ${plugin}"
+  fi
+done
+`, strings.Join(h.GetPluginsToRemove(), " ")))
+	}
+	sb.WriteString(`if [ -n "${MISSING}${NOT_REMOVED}" ]; then
+  echo "Missing plugins:${MISSING:- none}, plugins not removed:${NOT_REMOVED:- none}" | tee /dev/termination-log
+  exit 1
+fi
+`)
+
+	return sb.String()
+}
+
+// getPluginVolumeName permit to get the volume name that hold the plugin zip
+func getPluginVolumeName(plugin *PluginSpec) string {
+	return fmt.Sprintf("plugin-%s", plugin.Name)
+}
+
+// getPluginSourceFile permit to get the path of plugin zip on init container
+func getPluginSourceFile(plugin *PluginSpec) string {
+	return fmt.Sprintf("%s/%s/plugin.zip", pluginSourcesPath, plugin.Name)
+}
+
 // computeAntiAffinity permit to get  anti affinity spec
 // Default to soft anti affinity
 func (h *Opensearch) computeAntiAffinity(nodeGroup *NodeGroupSpec) (antiAffinity *corev1.PodAntiAffinity, err error) {
@@ -144,6 +473,53 @@ func (h *Opensearch) computeAntiAffinity(nodeGroup *NodeGroupSpec) (antiAffinity
 	return antiAffinity, nil
 }
 
+// computeTopologySpreadConstraints permit to get topology spread constraints
+// It return nil if no constraints are set
+func (h *Opensearch) computeTopologySpreadConstraints(nodeGroup *NodeGroupSpec) (constraints []corev1.TopologySpreadConstraint, err error) {
+	if nodeGroup.TopologySpread == nil && h.Spec.GlobalNodeGroup.TopologySpread == nil {
+		return nil, nil
+	}
+
+	expectedTopologySpread := &TopologySpreadSpec{}
+	if err = helper.Merge(expectedTopologySpread, nodeGroup.TopologySpread, funk.Get(h.Spec.GlobalNodeGroup, "TopologySpread")); err != nil {
+		return nil, errors.Wrapf(err, "Error when merge global topology spread with node group %s", nodeGroup.Name)
+	}
+
+	if len(expectedTopologySpread.Constraints) == 0 {
+		return nil, nil
+	}
+
+	constraints = make([]corev1.TopologySpreadConstraint, 0, len(expectedTopologySpread.Constraints))
+	for _, constraint := range expectedTopologySpread.Constraints {
+		if constraint.TopologyKey == "" {
+			return nil, errors.Errorf("Topology key is required on topology spread constraint of node group %s", nodeGroup.Name)
+		}
+
+		maxSkew := int32(1)
+		if constraint.MaxSkew > 0 {
+			maxSkew = constraint.MaxSkew
+		}
+		whenUnsatisfiable := corev1.ScheduleAnyway
+		if constraint.WhenUnsatisfiable == "hard" {
+			whenUnsatisfiable = corev1.DoNotSchedule
+		}
+
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew: maxSkew,
+			TopologyKey: constraint.TopologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster": h.Name,
+					"nodeGroup": nodeGroup.Name,
+				},
+			},
+		})
+	}
+
+	return constraints, nil
+}
+
 // computeEnvFroms permit to compute the envFrom list
 // It just append all, without to keep unique object
 func (h *Opensearch ) computeEnvFroms(nodeGroup *NodeGroupSpec) (envFroms []corev1.EnvFromSource) {
@@ -170,17 +546,543 @@ func (h *Opensearch ) computeEnvFroms(nodeGroup *NodeGroupSpec) (envFroms []core
 
 
 
+// computeNodeGroupLabels permit to compute the labels set on each pods of node group
+func (h *Opensearch) computeNodeGroupLabels(nodeGroup *NodeGroupSpec) (labels map[string]string) {
+	labels = map[string]string{
+		"cluster": h.Name,
+		"nodeGroup": nodeGroup.Name,
+	}
+
+	if h.IsCoordinatingRole(nodeGroup) {
+		labels["coordinating"] = "true"
+	}
+
+	return labels
+}
+
+// computeAwarenessConfig permit to compute the settings needed by zone awareness
+// The zone is provided by NODE_ZONE environment variable
+func (h *Opensearch) computeAwarenessConfig() string {
+	var sb strings.Builder
+
+	sb.WriteString("\nnode.attr.zone: ${NODE_ZONE}\n")
+	sb.WriteString("cluster.routing.allocation.awareness.attributes: zone\n")
+	if len(h.Spec.Awareness.ForcedValues) > 0 {
+		sb.WriteString(fmt.Sprintf("cluster.routing.allocation.awareness.force.zone.values: %s\n", strings.Join(h.Spec.Awareness.ForcedValues, ",")))
+	}
+
+	return sb.String()
+}
+
+// computeAwarenessScript permit to compute the init container script that read the zone from node labels
+// Node labels can't be exposed with downward API, so it call the Kubernetes API with pod service account
+func (h *Opensearch) computeAwarenessScript() string {
+	return fmt.Sprintf(`#!/usr/bin/env bash
+set -euo pipefail
+
+TOKEN=$(cat /var/run/secrets/kubernetes.io/serviceaccount/token)
+curl -sSf --cacert /var/run/secrets/kubernetes.io/serviceaccount/ca.crt -H "Authorization: Bearer ${TOKEN}" https://kubernetes.default.svc/api/v1/nodes/${NODE_NAME} > /tmp/node.json
+ZONE=$(grep -o '"%s": *"[^"]*"' /tmp/node.json | cut -d '"' -f 4)
+if [ -z "${ZONE}" ]; then
+  echo "Label %s not found on node ${NODE_NAME}"
+  exit 1
+fi
+echo -n "${ZONE}" > %s/zone
+`, h.GetAwarenessTopologyKey(), h.GetAwarenessTopologyKey(), awarenessPath)
+}
+
+// getNodeGroupServiceAccountNamesForAwareness permit to get the service accounts used by node groups pods
+// The service account provided by user on pod template is kept, so it need to read nodes too
+func (h *Opensearch) getNodeGroupServiceAccountNamesForAwareness() []string {
+	serviceAccountNames := []string{h.GetServiceAccountNameForAwareness()}
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
+		serviceAccountName := ""
+		if nodeGroup.PodTemplate != nil && nodeGroup.PodTemplate.Spec.ServiceAccountName != "" {
+			serviceAccountName = nodeGroup.PodTemplate.Spec.ServiceAccountName
+		} else if h.Spec.GlobalNodeGroup.PodTemplate != nil && h.Spec.GlobalNodeGroup.PodTemplate.Spec.ServiceAccountName != "" {
+			serviceAccountName = h.Spec.GlobalNodeGroup.PodTemplate.Spec.ServiceAccountName
+		}
+		if serviceAccountName != "" && !funk.ContainsString(serviceAccountNames, serviceAccountName) {
+			serviceAccountNames = append(serviceAccountNames, serviceAccountName)
+		}
+	}
+
+	return serviceAccountNames
+}
+
+// isEndpointTarget return true if the node group receive the external traffic from ingress or load balancer
+// If the target node group name is not provided, it target the coordinating node groups if there are, else all node groups
+func (h *Opensearch) isEndpointTarget(targetNodeGroupName string, nodeGroup *NodeGroupSpec) bool {
+	if targetNodeGroupName != "" {
+		return targetNodeGroupName == nodeGroup.Name
+	}
+
+	if h.HasCoordinatingNodeGroup() {
+		return h.IsCoordinatingRole(nodeGroup)
+	}
+
+	return true
+}
+
+// computeServiceDNSNames permit to get all DNS names to access on service (short, namespaced and FQDN forms)
+// For headless service, it also add wildcard to access on each pods
+func computeServiceDNSNames(serviceName string, namespace string, isHeadless bool) (dnsNames []string) {
+	dnsNames = []string{
+		serviceName,
+		fmt.Sprintf("%s.%s", serviceName, namespace),
+		fmt.Sprintf("%s.%s.svc", serviceName, namespace),
+		fmt.Sprintf("%s.%s.svc.%s", serviceName, namespace, clusterDomain),
+	}
+
+	if isHeadless {
+		dnsNames = append(dnsNames,
+			fmt.Sprintf("*.%s.%s.svc", serviceName, namespace),
+			fmt.Sprintf("*.%s.%s.svc.%s", serviceName, namespace, clusterDomain),
+		)
+	}
+
+	return dnsNames
+}
+
+// getDataStorageRequest permit to get the storage request of data volume claim template from statefullset
+// It return nil if there are no data volume claim template
+func getDataStorageRequest(sts *appv1.StatefulSet) *resource.Quantity {
+	for _, pvc := range sts.Spec.VolumeClaimTemplates {
+		if pvc.Name != "opensearch-data" {
+			continue
+		}
+		if size, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			return &size
+		}
+	}
+
+	return nil
+}
+
 // getOpensearchContainer permit to get opensearch container containning from pod template
 func getOpensearchContainer(podTemplate *corev1.PodTemplateSpec) (container *corev1.Container) {
+	return getContainer(podTemplate, "opensearch")
+}
+
+// getContainer permit to get the container from pod template provided by user
+func getContainer(podTemplate *corev1.PodTemplateSpec, name string) (container *corev1.Container) {
 	if podTemplate == nil {
 		return nil
 	}
 
 	for _, p := range podTemplate.Spec.Containers {
-		if p.Name == "opensearch" {
+		if p.Name == name {
 			return &p
 		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// hasKeystore return true if some snapshot repositories need credentials on keystore
+func (h *Opensearch) hasKeystore() bool {
+	for _, repository := range h.Spec.SnapshotRepositories {
+		if repository.CredentialsSecretRef != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSnapshotRepositories permit to check that snapshot repositories have unique valid name, location for fs type and no credentials for fs type
+func (h *Opensearch) checkSnapshotRepositories() (err error) {
+	names := map[string]bool{}
+
+	for _, repository := range h.Spec.SnapshotRepositories {
+		if !pluginNameRegexp.MatchString(repository.Name) {
+			return errors.Errorf("Snapshot repository name '%s' is not valid", repository.Name)
+		}
+		if names[repository.Name] {
+			return errors.Errorf("Snapshot repository %s is declared more than once", repository.Name)
+		}
+		names[repository.Name] = true
+
+		if repository.Type == "fs" {
+			if repository.Settings["location"] == "" {
+				return errors.Errorf("Snapshot repository %s need location setting", repository.Name)
+			}
+			if repository.CredentialsSecretRef != "" {
+				return errors.Errorf("Snapshot repository %s with type fs can't have credentials", repository.Name)
+			}
+			if repository.Volume == nil {
+				return errors.Errorf("Snapshot repository %s with type fs need volume", repository.Name)
+			}
+		} else if repository.Volume != nil {
+			return errors.Errorf("Snapshot repository %s with type %s can't have volume", repository.Name, repository.Type)
+		}
+	}
+
+	return nil
+}
+
+// computeSnapshotRepositoriesConfig permit to compute path.repo setting from fs snapshot repositories
+// Opensearch only allow fs repositories on path declared on path.repo
+func (h *Opensearch) computeSnapshotRepositoriesConfig() string {
+	locations := make([]string, 0)
+	for _, repository := range h.Spec.SnapshotRepositories {
+		if repository.Type == "fs" && !funk.ContainsString(locations, repository.Settings["location"]) {
+			locations = append(locations, repository.Settings["location"])
+		}
+	}
+
+	if len(locations) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("\npath.repo: [%s]\n", strings.Join(locations, ", "))
+}
+
+// computeNodesDNConfig permit to compute the DN of nodes accepted by security plugin on transport layer
+// It's the nodes of this cluster, of the remote clusters and of the clusters from opensearchList that declare this cluster as remote cluster
+// It return empty string when there are no remote clusters, to keep the nodes_dn set on config
+func (h *Opensearch) computeNodesDNConfig(opensearchList []Opensearch) string {
+	clusterNames := make([]string, 0)
+	nodesDN := make([]string, 0)
+
+	for _, remoteCluster := range h.Spec.RemoteClusters {
+		if remoteCluster.OpensearchRef != nil {
+			clusterNames = append(clusterNames, remoteCluster.OpensearchRef.Name)
+		} else {
+			nodesDN = append(nodesDN, remoteCluster.NodesDN...)
+		}
+	}
+	for _, o := range opensearchList {
+		if h.IsRemoteClusterOf(&o) {
+			clusterNames = append(clusterNames, o.Name)
+		}
+	}
+
+	if len(clusterNames) == 0 && len(nodesDN) == 0 {
+		return ""
+	}
+
+	// The node names are prefixed by the cluster name
+	clusterNames = append([]string{h.Name}, clusterNames...)
+	expectedNodesDN := make([]string, 0, len(clusterNames)+len(nodesDN))
+	for _, clusterName := range clusterNames {
+		expectedNodesDN = append(expectedNodesDN, fmt.Sprintf(nodeDNTemplate, clusterName+"-*"))
+	}
+	expectedNodesDN = append(expectedNodesDN, nodesDN...)
+	expectedNodesDN = funk.UniqString(expectedNodesDN)
+
+	return fmt.Sprintf("\nplugins.security.nodes_dn: ['%s']\n", strings.Join(expectedNodesDN, "', '"))
+}
+
+// computeKeystoreScript permit to compute the init container script that create the keystore with the credentials of snapshot repositories
+func (h *Opensearch) computeKeystoreScript() string {
+	var sb strings.Builder
+
+	sb.WriteString(`#!/usr/bin/env bash
+set -euo pipefail
+
+./bin/opensearch-keystore create
+`)
+	for _, repository := range h.Spec.SnapshotRepositories {
+		if repository.CredentialsSecretRef == "" {
+			continue
+		}
+		client := repository.Client
+		if client == "" {
+			client = defaultSnapshotRepositoryClient
+		}
+		sb.WriteString(fmt.Sprintf(`for file in %s/%s/*; do
+  key=$(basename ${file})
+  if [ "${key}" == "credentials_file" ]; then
+    ./bin/opensearch-keystore add-file -f %s.client.%s.${key} ${file}
+  else
+    ./bin/opensearch-keystore add -f -x %s.client.%s.${key} < ${file}
+  fi
+done
+`, keystoreSourcesPath, repository.Name, repository.Type, client, repository.Type, client))
+	}
+	sb.WriteString(fmt.Sprintf("cp config/opensearch.keystore %s/opensearch.keystore\n", keystorePath))
+
+	return sb.String()
+}
+
+// getKeystoreVolumeName permit to get the volume name of snapshot repository credentials
+func getKeystoreVolumeName(repository *SnapshotRepositorySpec) string {
+	return fmt.Sprintf("keystore-%s", repository.Name)
+}
+
+// getSnapshotRepositoryVolumeName permit to get the volume name of fs snapshot repository
+func getSnapshotRepositoryVolumeName(repository *SnapshotRepositorySpec) string {
+	return fmt.Sprintf("snapshot-repository-%s", repository.Name)
+}
+
+// computeKeystoreHash permit to compute the hash of the snapshot repositories credentials
+// The keystore is only built on init container, so pods need to be restarted when credentials change
+func (h *Opensearch) computeKeystoreHash(keystoreSecrets map[string]*corev1.Secret) (hash string, err error) {
+	sum := sha256.New()
+	for _, repository := range h.Spec.SnapshotRepositories {
+		if repository.CredentialsSecretRef == "" {
+			continue
+		}
+		sum.Write([]byte(repository.Name))
+		if secret := keystoreSecrets[repository.CredentialsSecretRef]; secret != nil {
+			data, err := json.Marshal(secret.Data)
+			if err != nil {
+				return "", errors.Wrapf(err, "Error when marshall secret %s", secret.Name)
+			}
+			sum.Write(data)
+		}
+	}
+
+	return fmt.Sprintf("%x", sum.Sum(nil)), nil
+}
+
+// checkRestore permit to check that restore use snapshot repository declared on cluster
+func (h *Opensearch) checkRestore() (err error) {
+	if h.Spec.Restore == nil {
+		return nil
+	}
+
+	if h.Spec.Restore.Snapshot == "" {
+		return errors.New("Snapshot must be provided to restore")
+	}
+	isFound := false
+	for _, repository := range h.Spec.SnapshotRepositories {
+		if repository.Name == h.Spec.Restore.Repository {
+			isFound = true
+			break
+		}
+	}
+	if !isFound {
+		return errors.Errorf("Snapshot repository %s must be declared on snapshotRepositories to restore", h.Spec.Restore.Repository)
+	}
+	if (h.Spec.Restore.RenamePattern == "") != (h.Spec.Restore.RenameReplacement == "") {
+		return errors.New("Rename pattern and rename replacement must be provided together")
+	}
+
+	return nil
+}
+
+// checkRemoteClusters permit to check that remote clusters have unique valid name, and seeds or Opensearch reference
+// The auto follow rules must have unique name on cluster, because the Opensearch API not return the leader alias
+func (h *Opensearch) checkRemoteClusters() (err error) {
+	names := map[string]bool{}
+	ruleNames := map[string]bool{}
+
+	for _, remoteCluster := range h.Spec.RemoteClusters {
+		if !pluginNameRegexp.MatchString(remoteCluster.Name) {
+			return errors.Errorf("Remote cluster name '%s' is not valid", remoteCluster.Name)
+		}
+		if names[remoteCluster.Name] {
+			return errors.Errorf("Remote cluster %s is declared more than once", remoteCluster.Name)
+		}
+		names[remoteCluster.Name] = true
+
+		if remoteCluster.OpensearchRef != nil {
+			if remoteCluster.OpensearchRef.Name == "" {
+				return errors.Errorf("Remote cluster %s need Opensearch name", remoteCluster.Name)
+			}
+			if remoteCluster.OpensearchRef.Name == h.Name && h.GetRemoteOpensearchNamespace(&remoteCluster) == h.Namespace {
+				return errors.Errorf("Remote cluster %s can't reference the cluster itself", remoteCluster.Name)
+			}
+			if len(remoteCluster.Seeds) > 0 || remoteCluster.CaSecretRef != "" {
+				return errors.Errorf("Remote cluster %s can't have seeds or CA secret with Opensearch reference", remoteCluster.Name)
+			}
+		} else if len(remoteCluster.Seeds) == 0 {
+			return errors.Errorf("Remote cluster %s need seeds or Opensearch reference", remoteCluster.Name)
+		}
+
+		if remoteCluster.Replication == nil {
+			continue
+		}
+		for _, rule := range remoteCluster.Replication.AutoFollowRules {
+			if ruleNames[rule.Name] {
+				return errors.Errorf("Auto follow rule %s is declared more than once", rule.Name)
+			}
+			ruleNames[rule.Name] = true
+
+			if rule.Pattern == "" {
+				return errors.Errorf("Auto follow rule %s need pattern", rule.Name)
+			}
+			if !h.Spec.DisableSecurityPlugin && (rule.LeaderClusterRole == "" || rule.FollowerClusterRole == "") {
+				return errors.Errorf("Auto follow rule %s need leader and follower cluster roles when security plugin is enabled", rule.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// clusterSettingToString permit to convert cluster setting returned by Opensearch as string
+// List settings are joined with comma
+func clusterSettingToString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// checkMonitoring permit to check that the prometheus-exporter plugin source can be computed from node group versions
+// The plugin version must match the Opensearch version
+func (h *Opensearch) checkMonitoring() (err error) {
+	if !h.IsMonitoringEnabled() || h.GetMonitoringMode() != MonitoringModePlugin || h.Spec.Monitoring.Plugin != nil {
+		return nil
+	}
+
+	for _, nodeGroup := range h.GetActiveNodeGroups() {
+		if _, _, ok := parseVersion(h.GetNodeGroupVersion(&nodeGroup)); !ok {
+			return errors.Errorf("Monitoring plugin source must be provided when version is not set on node group %s", nodeGroup.Name)
+		}
+	}
+
+	return nil
+}
+
+// computeMonitoringPlugin permit to compute the prometheus-exporter plugin to install on node group
+// It default to the release that match the node group version
+func (h *Opensearch) computeMonitoringPlugin(nodeGroup *NodeGroupSpec) PluginSpec {
+	if h.Spec.Monitoring.Plugin != nil {
+		plugin := *h.Spec.Monitoring.Plugin
+		if plugin.Name == "" {
+			plugin.Name = monitoringPluginName
+		}
+		return plugin
+	}
+
+	version := strings.TrimPrefix(h.GetNodeGroupVersion(nodeGroup), "v")
+	return PluginSpec{
+		Name: monitoringPluginName,
+		Url: fmt.Sprintf(monitoringPluginUrl, version, version),
+	}
+}
+
+// computeMonitoringSelector permit to compute the labels of metrics service, selected by ServiceMonitor
+func (h *Opensearch) computeMonitoringSelector() map[string]string {
+	return map[string]string{
+		"cluster": h.Name,
+		"metrics": "true",
+	}
+}
+
+// computeMonitoringTlsConfig permit to compute the TLS config used by Prometheus to scrape the Opensearch API
+// The metrics are scraped on pod IP, so the certificate is checked with the global service name
+func (h *Opensearch) computeMonitoringTlsConfig() map[string]any {
+	if h.Spec.Monitoring.Tls != nil && h.Spec.Monitoring.Tls.InsecureSkipVerify {
+		return map[string]any{
+			"insecureSkipVerify": true,
+		}
+	}
+
+	serverName := fmt.Sprintf("%s.%s.svc", h.GetGlobalServiceName(), h.Namespace)
+	if h.Spec.Monitoring.Tls != nil && h.Spec.Monitoring.Tls.ServerName != "" {
+		serverName = h.Spec.Monitoring.Tls.ServerName
+	}
+
+	return map[string]any{
+		"ca": map[string]any{
+			"secret": map[string]any{
+				"name": h.GetSecretNameForTlsApi(),
+				"key":  "ca.crt",
+			},
+		},
+		"serverName": serverName,
+	}
+}
+
+// computeMonitoringExporterScript permit to compute the exporter sidecar script
+// The credentials secret is created by the OpensearchUser controller once the cluster is up, so it wait them before start exporter
+func (h *Opensearch) computeMonitoringExporterScript(nodeGroup *NodeGroupSpec) string {
+	var sb strings.Builder
+	args := []string{
+		fmt.Sprintf("--web.listen-address=:%d", monitoringExporterPort),
+	}
+
+	sb.WriteString(`#!/bin/sh
+set -e
+
+`)
+	if h.Spec.DisableSecurityPlugin {
+		args = append(args, "--es.uri=http://localhost:9200")
+	} else {
+		sb.WriteString(fmt.Sprintf(`until [ -f %s/password ]; do
+  echo "Wait credentials of monitoring user"
+  sleep 5
+done
+export ES_USERNAME=$(cat %s/username)
+export ES_PASSWORD=$(cat %s/password)
+`, monitoringCredentialsPath, monitoringCredentialsPath, monitoringCredentialsPath))
+
+		// The certificate contain the pod names of headless service
+		args = append(args, fmt.Sprintf("--es.uri=https://${POD_NAME}.%s.%s.svc:9200", h.GetNodeGroupServiceNameHeadless(nodeGroup.Name), h.Namespace))
+		if h.Spec.Monitoring.Tls != nil && h.Spec.Monitoring.Tls.InsecureSkipVerify {
+			args = append(args, "--es.ssl-skip-verify")
+		} else {
+			args = append(args, fmt.Sprintf("--es.ca=%s/ca.crt", monitoringCertsPath))
+		}
+	}
+	if h.Spec.Monitoring.Exporter != nil {
+		args = append(args, h.Spec.Monitoring.Exporter.Args...)
+	}
+	sb.WriteString(fmt.Sprintf("exec /bin/elasticsearch_exporter %s\n", strings.Join(args, " ")))
+
+	return sb.String()
+}
+
+// computeMonitoringExporterContainer permit to compute the exporter sidecar that expose the metrics of the node
+func (h *Opensearch) computeMonitoringExporterContainer(nodeGroup *NodeGroupSpec) corev1.Container {
+	container := corev1.Container{
+		Name: monitoringExporterContainerName,
+		Image: h.GetMonitoringExporterImage(),
+		ImagePullPolicy: h.Spec.ImagePullPolicy,
+		Env: []corev1.EnvVar{
+			{
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						APIVersion: "v1",
+						FieldPath: "metadata.name",
+					},
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name: "metrics",
+				ContainerPort: monitoringExporterPort,
+				Protocol: corev1.ProtocolTCP,
+			},
+		},
+		Command: []string{
+			"/bin/sh",
+			"-c",
+			h.computeMonitoringExporterScript(nodeGroup),
+		},
+	}
+	if h.Spec.Monitoring.Exporter != nil {
+		container.Resources = h.Spec.Monitoring.Exporter.Resources
+	}
+	if !h.Spec.DisableSecurityPlugin {
+		container.VolumeMounts = []corev1.VolumeMount{
+			{
+				Name: "monitoring-credentials",
+				MountPath: monitoringCredentialsPath,
+				ReadOnly: true,
+			},
+			{
+				Name: "api-tls",
+				MountPath: monitoringCertsPath,
+				ReadOnly: true,
+			},
+		}
+	}
+
+	return container
+}
@@ -195,10 +195,6 @@ func TestComputeDiscoverySeedHosts(t *testing.T) {
 }
 
 func TestComputeRoles(t *testing.T) {
-	roles := []string {
-		"cluster_manager",
-	}
-
 	o := &Opensearch{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "default",
@@ -207,34 +203,107 @@ func TestComputeRoles(t *testing.T) {
 		Spec: OpensearchSpec{},
 	}
 
+	// With built-in roles
 	expectedEnvs := []corev1.EnvVar {
 		{
-			Name: "node.cluster_manager",
-			Value: "true",
+			Name: "node.roles",
+			Value: "cluster_manager",
 		},
+	}
+	assert.Equal(t, expectedEnvs, o.computeRoles([]string{"cluster_manager"}))
+
+	// With search and custom roles
+	expectedEnvs = []corev1.EnvVar {
 		{
-			Name: "node.data",
-			Value: "false",
+			Name: "node.roles",
+			Value: "data,search,warm",
 		},
-		{
-			Name: "node.ingest",
-			Value: "false",
+	}
+	assert.Equal(t, expectedEnvs, o.computeRoles([]string{"data", "search", "warm"}))
+
+	// When coordinating node, roles are set on config
+	assert.Empty(t, o.computeRoles(nil))
+}
+
+func TestComputeNodeAttributes(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
 		},
-		{
-			Name: "node.ml",
-			Value: "false",
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 1,
+				},
+				{
+					Name: "warm",
+					Replicas: 1,
+					Roles: []string{
+						"data",
+						"warm",
+					},
+					NodeAttributes: map[string]string{
+						"temp": "warm",
+						"box_type": "hdd",
+					},
+				},
+			},
 		},
+	}
+
+	// Without attributes
+	assert.Empty(t, o.computeNodeAttributes(&o.Spec.NodeGroups[0]))
+
+	// With attributes
+	expectedEnvs := []corev1.EnvVar{
 		{
-			Name: "node.remote_cluster_client",
-			Value: "false",
+			Name: "node.attr.box_type",
+			Value: "hdd",
 		},
 		{
-			Name: "node.transform",
-			Value: "false",
+			Name: "node.attr.temp",
+			Value: "warm",
 		},
 	}
+	assert.Equal(t, expectedEnvs, o.computeNodeAttributes(&o.Spec.NodeGroups[1]))
+}
 
-	assert.Equal(t, expectedEnvs, o.computeRoles(roles))
+func TestCheckNodeGroupRoles(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "warm",
+					Replicas: 1,
+					Roles: []string{
+						"data",
+						"warm",
+					},
+					NodeAttributes: map[string]string{
+						"temp": "warm",
+					},
+				},
+			},
+		},
+	}
+
+	// With valid roles and attributes
+	assert.NoError(t, o.checkNodeGroupRoles(&o.Spec.NodeGroups[0]))
+
+	// With invalid role
+	o.Spec.NodeGroups[0].Roles = []string{"data,warm"}
+	assert.Error(t, o.checkNodeGroupRoles(&o.Spec.NodeGroups[0]))
+
+	// With invalid attribute
+	o.Spec.NodeGroups[0].Roles = []string{"data"}
+	o.Spec.NodeGroups[0].NodeAttributes = map[string]string{"my temp": "warm"}
+	assert.Error(t, o.checkNodeGroupRoles(&o.Spec.NodeGroups[0]))
 }
 
 func TestComputeAntiAffinity(t *testing.T) {
@@ -368,6 +437,107 @@ func TestComputeAntiAffinity(t *testing.T) {
 	assert.Equal(t, expectedAntiAffinity, antiAffinity)
 }
 
+func TestComputeTopologySpreadConstraints(t *testing.T) {
+
+	var (
+		o *Opensearch
+		err error
+		constraints []corev1.TopologySpreadConstraint
+	)
+
+	// With default values
+	o = &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+					Replicas: 1,
+				},
+			},
+		},
+	}
+
+	constraints, err = o.computeTopologySpreadConstraints(&o.Spec.NodeGroups[0])
+	assert.NoError(t, err)
+	assert.Nil(t, constraints)
+
+	// When global topology spread is set
+	o.Spec.GlobalNodeGroup.TopologySpread = &TopologySpreadSpec{
+		Constraints: []TopologySpreadConstraintSpec{
+			{
+				TopologyKey: "topology.kubernetes.io/zone",
+			},
+			{
+				TopologyKey: "kubernetes.io/hostname",
+				MaxSkew: 2,
+				WhenUnsatisfiable: "hard",
+			},
+		},
+	}
+	expectedConstraints := []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew: 1,
+			TopologyKey: "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster": "test",
+					"nodeGroup": "master",
+				},
+			},
+		},
+		{
+			MaxSkew: 2,
+			TopologyKey: "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster": "test",
+					"nodeGroup": "master",
+				},
+			},
+		},
+	}
+	constraints, err = o.computeTopologySpreadConstraints(&o.Spec.NodeGroups[0])
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConstraints, constraints)
+
+	// When node group topology spread is set, it take precedence
+	o.Spec.NodeGroups[0].TopologySpread = &TopologySpreadSpec{
+		Constraints: []TopologySpreadConstraintSpec{
+			{
+				TopologyKey: "kubernetes.io/hostname",
+				WhenUnsatisfiable: "hard",
+			},
+		},
+	}
+	expectedConstraints = []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew: 1,
+			TopologyKey: "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster": "test",
+					"nodeGroup": "master",
+				},
+			},
+		},
+	}
+	constraints, err = o.computeTopologySpreadConstraints(&o.Spec.NodeGroups[0])
+	assert.NoError(t, err)
+	assert.Equal(t, expectedConstraints, constraints)
+
+	// When topology key is missing
+	o.Spec.NodeGroups[0].TopologySpread.Constraints[0].TopologyKey = ""
+	_, err = o.computeTopologySpreadConstraints(&o.Spec.NodeGroups[0])
+	assert.Error(t, err)
+}
+
 func TestComputeEnvFroms(t *testing.T) {
 	var (
 		o *Opensearch
@@ -515,4 +685,98 @@ func TestComputeEnvFroms(t *testing.T) {
 	}
 
 	assert.Equal(t, expectedEnvFroms, o.computeEnvFroms(&o.Spec.NodeGroups[0]))
-}
\ No newline at end of file
+}
+func TestCheckVersionsCompatibility(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "master",
+				},
+				{
+					Name: "client",
+				},
+			},
+		},
+	}
+
+	// With same version
+	assert.NoError(t, o.checkVersionsCompatibility())
+
+	// With same major version
+	o.Spec.NodeGroups[1].Version = "2.4.1"
+	assert.NoError(t, o.checkVersionsCompatibility())
+
+	// With last minor of previous major version
+	o.Spec.Version = "1.3.6"
+	o.Spec.NodeGroups[1].Version = "2.4.0"
+	assert.NoError(t, o.checkVersionsCompatibility())
+
+	// With older minor of previous major version
+	o.Spec.Version = "1.2.4"
+	assert.Error(t, o.checkVersionsCompatibility())
+
+	// With two major versions of difference
+	o.Spec.Version = "1.3.6"
+	o.Spec.NodeGroups[1].Version = "3.0.0"
+	assert.Error(t, o.checkVersionsCompatibility())
+
+	// When version can't be parsed
+	o.Spec.Version = "latest"
+	assert.NoError(t, o.checkVersionsCompatibility())
+}
+
+func TestParseVersion(t *testing.T) {
+	major, minor, ok := parseVersion("2.4.1")
+	assert.True(t, ok)
+	assert.Equal(t, 2, major)
+	assert.Equal(t, 4, minor)
+
+	major, minor, ok = parseVersion("v1.3")
+	assert.True(t, ok)
+	assert.Equal(t, 1, major)
+	assert.Equal(t, 3, minor)
+
+	_, _, ok = parseVersion("latest")
+	assert.False(t, ok)
+}
+
+func TestCheckSuccessorNodeGroups(t *testing.T) {
+	o := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name: "test",
+		},
+		Spec: OpensearchSpec{
+			NodeGroups: []NodeGroupSpec{
+				{
+					Name: "data",
+				},
+				{
+					Name: "data-ssd",
+					SuccessorOf: "data",
+				},
+			},
+		},
+	}
+
+	// With one successor
+	assert.NoError(t, o.checkSuccessorNodeGroups())
+
+	// When node group is successor of itself
+	o.Spec.NodeGroups[1].SuccessorOf = "data-ssd"
+	assert.Error(t, o.checkSuccessorNodeGroups())
+
+	// When node group have two successors
+	o.Spec.NodeGroups[1].SuccessorOf = "data"
+	o.Spec.NodeGroups = append(o.Spec.NodeGroups, NodeGroupSpec{
+		Name: "data-nvme",
+		SuccessorOf: "data",
+	})
+	assert.Error(t, o.checkSuccessorNodeGroups())
+}
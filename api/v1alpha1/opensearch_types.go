@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -54,6 +55,36 @@ type OpensearchSpec struct {
 	// +optional
 	PluginsList []string `json:"pluginsList,omitempty"`
 
+	// Plugins is the list of additionnal plugin to install on each Opensearch node from offline sources
+	// Plugins are installed by init container on shared volume. They are cached on data volume when node group have persistence, so they are only reinstalled when the plugin set change
+	// Default is empty
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Plugins []PluginSpec `json:"plugins,omitempty"`
+
+	// PluginsMode permit to choose how plugins are provided
+	// install: plugins are installed by init container
+	// prebuilt: plugins are baked on image. The init container only check that plugins are installed and report missing plugins on status
+	// Default to install
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=install;prebuilt
+	// +optional
+	PluginsMode string `json:"pluginsMode,omitempty"`
+
+	// PluginsToRemove is the list of bundled plugin to remove on each Opensearch node, like opensearch-performance-analyzer
+	// Default is empty
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PluginsToRemove []string `json:"pluginsToRemove,omitempty"`
+
+	// DisableSecurityPlugin permit to remove the security plugin
+	// It can't be used when security config is managed by operator, because it depend on admin certificate
+	// The operator call the API on HTTP without credentials, so OpensearchUser, OpensearchRole and OpensearchRoleMapping can't be used
+	// Default is false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DisableSecurityPlugin bool `json:"disableSecurityPlugin,omitempty"`
+
 	// GlobalNodeGroup permit to set some default parameters for each node groups
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
@@ -70,6 +101,345 @@ type OpensearchSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
 	Endpoint *EndpointSpec `json:"endpoint,omitempty"`
+
+	// NetworkPolicy permit to generate network policies to restrict access on Opensearch nodes
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// Awareness permit to enable zone-aware shard allocation from Kubernetes topology labels
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Awareness *AwarenessSpec `json:"awareness,omitempty"`
+
+	// SnapshotRepositories permit to register snapshot repositories on cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	SnapshotRepositories []SnapshotRepositorySpec `json:"snapshotRepositories,omitempty"`
+
+	// Restore permit to restore snapshot when the cluster is created, like for disaster recovery or to clone environment
+	// The restore is only done one time, and it's ignored when added on cluster that already have statefullsets. The ingress and the load balancer are created once the restore is completed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Restore *RestoreSpec `json:"restore,omitempty"`
+
+	// ClusterSettings is the persistent dynamic cluster settings to apply, like action.destructive_requires_name
+	// Use comma to separate values of list settings. The out-of-band changes on these keys are reverted, and the keys removed from the map are reset to default
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ClusterSettings map[string]string `json:"clusterSettings,omitempty"`
+
+	// RemoteClusters permit to connect remote clusters for cross cluster search and cross cluster replication
+	// The transport CA of remote clusters are added on the transport truststore. The remote clusters managed by the operator trust this cluster too
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RemoteClusters []RemoteClusterSpec `json:"remoteClusters,omitempty"`
+
+	// Monitoring permit to expose the cluster metrics to Prometheus
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+}
+
+type MonitoringSpec struct {
+	// Enabled permit to enabled / disabled the monitoring
+	// Default is false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Mode is the way to expose metrics
+	// With plugin, the prometheus-exporter plugin is installed on nodes and the metrics are read on /_prometheus/metrics
+	// With exporter, an elasticsearch exporter sidecar is added on nodes
+	// Default to plugin
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=plugin;exporter
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Plugin permit to custom the source of prometheus-exporter plugin
+	// Default to the release that match the node group version
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Plugin *PluginSpec `json:"plugin,omitempty"`
+
+	// Exporter permit to custom the exporter sidecar
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Exporter *MonitoringExporterSpec `json:"exporter,omitempty"`
+
+	// MonitorType is the Prometheus operator resource that scrape the metrics
+	// Use None when Prometheus operator is not installed, the metrics service is always created
+	// Default to ServiceMonitor
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor;None
+	// +optional
+	MonitorType string `json:"monitorType,omitempty"`
+
+	// Interval is the scrape interval
+	// Default to 30s
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Labels is the additional labels set on ServiceMonitor or PodMonitor, like the labels selected by Prometheus
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Tls permit to custom the TLS config used to read the metrics on Opensearch API
+	// It used by Prometheus with plugin mode and by the exporter sidecar with exporter mode
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Tls *MonitoringTlsSpec `json:"tls,omitempty"`
+}
+
+type MonitoringExporterSpec struct {
+	// Image is the exporter image
+	// Default to quay.io/prometheuscommunity/elasticsearch-exporter
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Version is the exporter image tag
+	// Default to v1.5.0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Args is the additional arguments of exporter, like --es.indices
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Resources is the exporter resources
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+type MonitoringTlsSpec struct {
+	// InsecureSkipVerify permit to not verify the certificate of Opensearch API
+	// Default is false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// ServerName is the name used by Prometheus to verify the certificate of Opensearch API, because the metrics are scraped on pod IP
+	// Default to the FQDN of global service. The exporter sidecar always use the FQDN of pod
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
+type RemoteClusterSpec struct {
+	// Name is the remote cluster alias, used on cluster.remote.<name>.seeds
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	// OpensearchRef is the remote cluster managed by the operator
+	// The seeds and the transport CA are computed from it
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	OpensearchRef *RemoteOpensearchRef `json:"opensearchRef,omitempty"`
+
+	// Seeds is the transport addresses of external remote cluster, like host:9300
+	// It's only used when opensearchRef is not set
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Seeds []string `json:"seeds,omitempty"`
+
+	// CaSecretRef is the secret that store the transport CA of external remote cluster on key ca.crt
+	// It's only used when opensearchRef is not set
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	CaSecretRef string `json:"caSecretRef,omitempty"`
+
+	// NodesDN is the DN of the transport certificates of external remote cluster nodes, added on plugins.security.nodes_dn
+	// Wildcards are allowed, like CN=*.remote.domain.com. It's only used when opensearchRef is not set
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	NodesDN []string `json:"nodesDN,omitempty"`
+
+	// SkipUnavailable permit to skip the remote cluster on cross cluster search when it's unavailable
+	// Default to false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	SkipUnavailable bool `json:"skipUnavailable,omitempty"`
+
+	// Replication permit to replicate indices from the remote cluster, used as leader
+	// The plugin opensearch-cross-cluster-replication is needed on both clusters
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Replication *RemoteClusterReplicationSpec `json:"replication,omitempty"`
+}
+
+type RemoteOpensearchRef struct {
+	// Name is the Opensearch resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	// Namespace is the Opensearch resource namespace
+	// Default to the same namespace
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type RemoteClusterReplicationSpec struct {
+	// AutoFollowRules is the rules that automatically replicate the leader indices matching the pattern
+	// The rule name must be unique on cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AutoFollowRules []AutoFollowRuleSpec `json:"autoFollowRules,omitempty"`
+}
+
+type AutoFollowRuleSpec struct {
+	// Name is the rule name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	// Pattern is the index pattern of leader indices to replicate
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Pattern string `json:"pattern"`
+
+	// LeaderClusterRole is the security role used on leader cluster
+	// It's needed when the security plugin is enabled
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	LeaderClusterRole string `json:"leaderClusterRole,omitempty"`
+
+	// FollowerClusterRole is the security role used on follower cluster
+	// It's needed when the security plugin is enabled
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	FollowerClusterRole string `json:"followerClusterRole,omitempty"`
+}
+
+type RestoreSpec struct {
+	// Repository is the snapshot repository name
+	// It must be declared on snapshotRepositories
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Repository string `json:"repository"`
+
+	// Snapshot is the snapshot name to restore
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Snapshot string `json:"snapshot"`
+
+	// Indices is the index patterns to restore
+	// Default to all indices
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// RenamePattern is the regex used to rename the restored indices
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RenamePattern string `json:"renamePattern,omitempty"`
+
+	// RenameReplacement is the replacement of indices name that match the rename pattern
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RenameReplacement string `json:"renameReplacement,omitempty"`
+
+	// IncludeGlobalState permit to restore the cluster state
+	// Default to false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IncludeGlobalState bool `json:"includeGlobalState,omitempty"`
+}
+
+type SnapshotRepositorySpec struct {
+	// Name is the repository name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	// Type is the repository type
+	// The s3, azure and gcs types need the plugins repository-s3, repository-azure and repository-gcs
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=s3;azure;gcs;fs
+	Type string `json:"type"`
+
+	// Settings is the repository settings, like bucket, base_path or location
+	// The location of fs repository is added on path.repo
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// Client is the client name used on keystore settings
+	// Default to default
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Client string `json:"client,omitempty"`
+
+	// CredentialsSecretRef is the secret that store the client credentials
+	// Each key is injected on keystore as <type>.client.<client>.<key>, like access_key and secret_key for s3, account and key for azure
+	// The key credentials_file is injected as file, like for gcs
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	CredentialsSecretRef string `json:"credentialsSecretRef,omitempty"`
+
+	// Volume is the volume source mounted on location of fs repository
+	// It must be shared by all nodes, like nfs. It's required for fs type
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Volume *corev1.VolumeSource `json:"volume,omitempty"`
+}
+
+type AwarenessSpec struct {
+	// Enabled permit to enabled / disabled the zone awareness
+	// The zone is read from node label on init container, so the pod need to read nodes on Kubernetes API
+	// The service account provided on pod template is kept and bound to the cluster role that allow to read nodes
+	// Default is false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// TopologyKey is the node label used to get the zone
+	// Default to topology.kubernetes.io/zone
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// ForcedValues is the list of zones used by forced awareness
+	// Opensearch not allocate all replicas on remaining zones when one zone is lost
+	// Default is empty
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ForcedValues []string `json:"forcedValues,omitempty"`
+}
+
+type NetworkPolicySpec struct {
+	// Enabled permit to enabled / disabled network policies
+	// Default is false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AllowedPeers is the list of peers (namespace / pod selectors) allowed to access on HTTP port
+	// Default is empty
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AllowedPeers []networkingv1.NetworkPolicyPeer `json:"allowedPeers,omitempty"`
+
+	// IngressControllerPeer is the peer that select the ingress controller
+	// It only used when ingress is enabled
+	// Default to namespace ingress-nginx
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IngressControllerPeer *networkingv1.NetworkPolicyPeer `json:"ingressControllerPeer,omitempty"`
+
+	// OperatorPeer is the peer that select the operator pods
+	// Default to pods with label app.kubernetes.io/name=opensearch-operator on the operator namespace
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	OperatorPeer *networkingv1.NetworkPolicyPeer `json:"operatorPeer,omitempty"`
+
+	// AdditionalRules permit to add extra ingress rules on each network policies
+	// Default is empty
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AdditionalRules []networkingv1.NetworkPolicyIngressRule `json:"additionalRules,omitempty"`
 }
 
 type EndpointSpec struct {
@@ -117,14 +487,16 @@ type TlsSpec struct {
 type SelfSignedCertificateSpec struct {
 
 	// AltIps permit to set subject alt names of type ip when generate certificate
+	// They are added to the alt names automatically computed from services, ingress and load balancer
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
-	AltIps []string `json:"altIPs:,omitempty"`
+	AltIps []string `json:"altIPs,omitempty"`
 
 	// AltNames permit to set subject alt names of type dns when generate certificate
+	// They are added to the alt names automatically computed from services, ingress and load balancer
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
-	AltNames []string `json:"altNames:,omitempty"`
+	AltNames []string `json:"altNames,omitempty"`
 }
 
 type IngressSpec struct {
@@ -177,6 +549,12 @@ type GlobalNodeGroupSpec struct {
 	// +optional
 	AntiAffinity *AntiAffinitySpec `json:"antiAffinity,omitempty"`
 
+	// TopologySpread permit to spread pods across topology domains with max skew semantics
+	// It's an alternative to anti affinity, default anti affinity is not set when it used
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	TopologySpread *TopologySpreadSpec `json:"topologySpread,omitempty"`
+
 	// PodDisruptionBudget is the pod disruption budget policy
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
@@ -239,10 +617,47 @@ type NodeGroupSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Replicas int32 `json:"replicas,omitempty"`
 
-	// Roles is the list of Opensearch roles
+	// Roles is the list of Opensearch roles, like cluster_manager, data, ingest, ml, remote_cluster_client, search
+	// You can also set custom roles, like warm
+	// When empty, the node group is coordinating only. In this case, it can't have persistence
+	// and it become the default target of the global service, ingress and load balancer
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	Roles []string `json:"roles,omitempty"`
 
+	// NodeAttributes permit to set custom node attributes, like temp: hot
+	// Each attribute is set as node.attr.<key> setting. It usefull to move indices between tiers with ISM
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	NodeAttributes map[string]string `json:"nodeAttributes,omitempty"`
+
+	// SuccessorOf permit to migrate an existing node group on this node group, like to rename it or to change its persistence
+	// Once this node group is ready, the shards are relocated from the old nodes. Then the old statefullset, certificates and services are removed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	SuccessorOf string `json:"successorOf,omitempty"`
+
+	// Image permit to overwrite the global image for this node group
+	// It usefull to canary a custom build image before rolling it on all node groups
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Version permit to overwrite the global Opensearch version for this node group
+	// It must be wire compatible with the version of the other node groups
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// PluginsList permit to overwrite the global list of additionnal plugin to install for this node group
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PluginsList []string `json:"pluginsList,omitempty"`
+
+	// Plugins permit to overwrite the global list of additionnal plugin to install from offline sources for this node group
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Plugins []PluginSpec `json:"plugins,omitempty"`
+
 	// Persistence is the spec to persist data
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
@@ -253,6 +668,12 @@ type NodeGroupSpec struct {
 	// +optional
 	AntiAffinity *AntiAffinitySpec `json:"antiAffinity,omitempty"`
 
+	// TopologySpread permit to spread pods across topology domains with max skew semantics
+	// It's an alternative to anti affinity, default anti affinity is not set when it used
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	TopologySpread *TopologySpreadSpec `json:"topologySpread,omitempty"`
+
 	// Resources permit to set ressources on Opensearch container
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	// +optional
@@ -325,6 +746,12 @@ type PersistenceSpec struct {
 	// It usefull if you should to use hostPath
 	// +optional
 	Volume *corev1.VolumeSource `json:"volume,omitempty"`
+
+	// PersistentVolumeClaimRetentionPolicy permit to retain or delete persistent volume claims on scale down or when cluster is deleted
+	// Default is retain
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
 }
 
 type VolumeSpec struct {
@@ -350,6 +777,148 @@ type AntiAffinitySpec struct {
 	TopologyKey string `json:"topologyKey,omitempty"`
 }
 
+const (
+	// MonitoringModePlugin is the mode where metrics are exposed by prometheus-exporter plugin
+	MonitoringModePlugin = "plugin"
+
+	// MonitoringModeExporter is the mode where metrics are exposed by exporter sidecar
+	MonitoringModeExporter = "exporter"
+)
+
+const (
+	// MonitorTypeServiceMonitor is the monitor type that scrape the metrics service
+	MonitorTypeServiceMonitor = "ServiceMonitor"
+
+	// MonitorTypePodMonitor is the monitor type that scrape the pods
+	MonitorTypePodMonitor = "PodMonitor"
+
+	// MonitorTypeNone is the monitor type when Prometheus operator is not used
+	MonitorTypeNone = "None"
+)
+
+const (
+	// PluginsModeInstall is the mode where plugins are installed by init container
+	PluginsModeInstall = "install"
+
+	// PluginsModePrebuilt is the mode where plugins are baked on image
+	PluginsModePrebuilt = "prebuilt"
+
+	// PluginsCheckContainerName is the init container name that check plugins baked on image
+	PluginsCheckContainerName = "check-plugins"
+)
+
+const (
+	// NodeGroupMigrationPending is the phase where the successor node group is not yet ready
+	NodeGroupMigrationPending = "Pending"
+
+	// NodeGroupMigrationRelocating is the phase where shards are relocated from the old nodes
+	NodeGroupMigrationRelocating = "Relocating"
+
+	// NodeGroupMigrationCompleted is the phase where old nodes are removed
+	NodeGroupMigrationCompleted = "Completed"
+)
+
+const (
+	// SnapshotRepositoryRegistered is the state of repository registered but not verified
+	SnapshotRepositoryRegistered = "Registered"
+
+	// SnapshotRepositoryVerified is the state of repository registered and verified by all nodes
+	SnapshotRepositoryVerified = "Verified"
+
+	// SnapshotRepositoryFailed is the state of repository that can't be registered or verified
+	SnapshotRepositoryFailed = "Failed"
+)
+
+const (
+	// RestorePending is the phase where the cluster or the snapshot repository is not yet ready
+	RestorePending = "Pending"
+
+	// RestoreRunning is the phase where shards are restored from snapshot
+	RestoreRunning = "Restoring"
+
+	// RestoreCompleted is the phase where all shards are restored
+	RestoreCompleted = "Completed"
+
+	// RestoreIgnored is the phase where restore is added on existing cluster
+	RestoreIgnored = "Ignored"
+
+	// OpensearchPhaseRestoring is the cluster phase while the snapshot is restored
+	OpensearchPhaseRestoring = "Restoring"
+
+	// OpensearchPhaseReady is the cluster phase when cluster can be used
+	OpensearchPhaseReady = "Ready"
+)
+
+type PluginSpec struct {
+
+	// Name is the plugin name
+	// When no source is provided, the plugin is installed from the official repository
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	// Url is the url to download the plugin zip
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Url string `json:"url,omitempty"`
+
+	// ConfigMapRef is the configMap key that hold the plugin zip
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// SecretRef is the secret key that hold the plugin zip
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// Image is the container image that hold the plugin zip
+	// The zip is copied by running cp inside the image, so the image need to provide the cp command (like busybox based image). Scratch images and OCI artifacts that are not runnable are not supported
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ImagePath is the path of plugin zip on image
+	// Default to /plugin.zip
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ImagePath string `json:"imagePath,omitempty"`
+
+	// Checksum is the sha256 checksum of the plugin zip
+	// It not used when plugin is installed from the official repository
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Checksum string `json:"checksum,omitempty"`
+}
+
+type TopologySpreadSpec struct {
+
+	// Constraints is the list of topology spread constraints
+	// Pods are selected by cluster and node group labels
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Constraints []TopologySpreadConstraintSpec `json:"constraints,omitempty"`
+}
+
+type TopologySpreadConstraintSpec struct {
+
+	// TopologyKey is the node label used to compute the topology domains
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxSkew is the maximum permitted difference of pods between topology domains
+	// Default to 1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable permit to set constraint as soft or hard
+	// Default to soft
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=soft;hard
+	// +optional
+	WhenUnsatisfiable string `json:"whenUnsatisfiable,omitempty"`
+}
+
 // OpensearchStatus defines the observed state of Opensearch
 type OpensearchStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -366,6 +935,96 @@ type OpensearchStatus struct {
 	// List of conditions
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// NodeGroupMigrations is the state of node groups migrations
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	NodeGroupMigrations []NodeGroupMigrationStatus `json:"nodeGroupMigrations,omitempty"`
+
+	// SnapshotRepositories is the state of snapshot repositories
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	SnapshotRepositories []SnapshotRepositoryStatus `json:"snapshotRepositories,omitempty"`
+
+	// Restore is the state of restore
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Restore *RestoreStatus `json:"restore,omitempty"`
+
+	// ClusterSettings is the state of cluster settings applied by operator
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	ClusterSettings *ClusterSettingsStatus `json:"clusterSettings,omitempty"`
+
+	// RemoteClusters is the state of remote clusters configured by operator
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	RemoteClusters []RemoteClusterStatus `json:"remoteClusters,omitempty"`
+}
+
+type RemoteClusterStatus struct {
+	// Name is the remote cluster alias
+	Name string `json:"name"`
+
+	// Connected is true when the nodes are connected on remote cluster
+	Connected bool `json:"connected"`
+
+	// AutoFollowRules is the auto follow rules created by operator
+	// +optional
+	AutoFollowRules []string `json:"autoFollowRules,omitempty"`
+}
+
+type ClusterSettingsStatus struct {
+	// Revision is incremented each time the cluster settings are applied
+	Revision int64 `json:"revision"`
+
+	// Keys is the cluster settings managed by operator
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// LastAppliedTime is the last time the cluster settings were applied
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+}
+
+type RestoreStatus struct {
+	// Phase is the restore phase (Pending, Restoring, Completed or Ignored)
+	Phase string `json:"phase"`
+
+	// Message is the error message when restore can't be started, or the reason why it's ignored
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is the time when restore is started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when all shards are restored
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type SnapshotRepositoryStatus struct {
+	// Name is the repository name
+	Name string `json:"name"`
+
+	// Status is the repository state (Registered, Verified or Failed)
+	Status string `json:"status"`
+
+	// Message is the error message when repository failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type NodeGroupMigrationStatus struct {
+	// NodeGroup is the old node group name
+	NodeGroup string `json:"nodeGroup"`
+
+	// Successor is the new node group name
+	Successor string `json:"successor"`
+
+	// Phase is the migration phase (Pending, Relocating or Completed)
+	Phase string `json:"phase"`
 }
 
 //+kubebuilder:object:root=true
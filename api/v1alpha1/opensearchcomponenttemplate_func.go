@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchComponentTemplate) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchComponentTemplate) GetStatus() any {
+	return h.Status
+}
+
+// GetTemplateName permit to get the component template name on Opensearch
+// It use the resource name if not provided
+func (h *OpensearchComponentTemplate) GetTemplateName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.Name
+}
+
+// GenerateComponentTemplate permit to generate the component template
+// Component template always need template, even if empty
+func (h *OpensearchComponentTemplate) GenerateComponentTemplate() (template *opensearch.ComponentTemplate, err error) {
+	t, err := h.Spec.TemplateSpec.generateTemplate()
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		t = &opensearch.Template{}
+	}
+
+	return &opensearch.ComponentTemplate{
+		Template: t,
+		Version:  h.Spec.Version,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetComponentTemplateName(t *testing.T) {
+	o := &OpensearchComponentTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs-mappings",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "logs-mappings", o.GetTemplateName())
+
+	// When name is provided
+	o.Spec.Name = "mappings"
+	assert.Equal(t, "mappings", o.GetTemplateName())
+}
+
+func TestGenerateComponentTemplate(t *testing.T) {
+	o := &OpensearchComponentTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs-mappings",
+		},
+		Spec: OpensearchComponentTemplateSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+		},
+	}
+
+	// Without template
+	template, err := o.GenerateComponentTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, &opensearch.ComponentTemplate{
+		Template: &opensearch.Template{},
+	}, template)
+
+	// With mappings
+	o.Spec.Version = pointer.Int64(1)
+	o.Spec.Mappings = `{"properties": {"message": {"type": "text"}}}`
+	template, err = o.GenerateComponentTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, &opensearch.ComponentTemplate{
+		Template: &opensearch.Template{
+			Mappings: map[string]any{
+				"properties": map[string]any{
+					"message": map[string]any{
+						"type": "text",
+					},
+				},
+			},
+		},
+		Version: pointer.Int64(1),
+	}, template)
+
+	// When JSON is not valid
+	o.Spec.Settings = `{"index":`
+	_, err = o.GenerateComponentTemplate()
+	assert.Error(t, err)
+}
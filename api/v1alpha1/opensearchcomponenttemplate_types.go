@@ -0,0 +1,83 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchComponentTemplateSpec defines the desired state of OpensearchComponentTemplate
+// +k8s:openapi-gen=true
+type OpensearchComponentTemplateSpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the component template
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Name is the component template name on Opensearch
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Version is the template version
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Version *int64 `json:"version,omitempty"`
+
+	// TemplateSpec is the settings, mappings and aliases of the template
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TemplateSpec `json:",inline"`
+}
+
+// OpensearchComponentTemplateStatus defines the observed state of OpensearchComponentTemplate
+type OpensearchComponentTemplateStatus struct {
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchComponentTemplate is the Schema for the opensearchcomponenttemplates API
+// +operator-sdk:csv:customresourcedefinitions:resources={{None,None,None}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchComponentTemplate')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchComponentTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchComponentTemplateSpec   `json:"spec,omitempty"`
+	Status OpensearchComponentTemplateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchComponentTemplateList contains a list of OpensearchComponentTemplate
+type OpensearchComponentTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchComponentTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchComponentTemplate{}, &OpensearchComponentTemplateList{})
+}
@@ -0,0 +1,508 @@
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/disaster37/k8sbuilder"
+	"github.com/pkg/errors"
+	"github.com/thoas/go-funk"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	defaultDashboardsImage         = "public.ecr.aws/opensearchproject/opensearch-dashboards"
+	dashboardsContainerName        = "dashboards"
+	dashboardsConfigPath           = "/usr/share/opensearch-dashboards/config"
+	dashboardsServerRole           = "kibana_server"
+	dashboardsConfigHashAnnotation = "opensearch.k8s.webcenter.fr/config-hash"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchDashboards) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchDashboards) GetStatus() any {
+	return h.Status
+}
+
+// GetDeploymentName permit to get the deployment name
+func (h *OpensearchDashboards) GetDeploymentName() string {
+	return fmt.Sprintf("%s-osd", h.Name)
+}
+
+// GetServiceName permit to get the service name
+func (h *OpensearchDashboards) GetServiceName() string {
+	return h.GetDeploymentName()
+}
+
+// GetIngressName permit to get the ingress name
+func (h *OpensearchDashboards) GetIngressName() string {
+	return h.GetDeploymentName()
+}
+
+// GetConfigMapName permit to get the configMap name that store the Dashboards config
+func (h *OpensearchDashboards) GetConfigMapName() string {
+	return fmt.Sprintf("%s-config", h.GetDeploymentName())
+}
+
+// GetServerUserName permit to get the name of the server user used by Dashboards
+// It is used as OpensearchUser and OpensearchRoleMapping resource name, and as username
+func (h *OpensearchDashboards) GetServerUserName() string {
+	return fmt.Sprintf("%s-server", h.GetDeploymentName())
+}
+
+// GetSecretNameForServerUser permit to get the secret name that store the credentials of the server user
+// The secret is generated by the OpensearchUser controller
+func (h *OpensearchDashboards) GetSecretNameForServerUser() string {
+	return h.GenerateServerUser().GetSecretNameForPassword()
+}
+
+// GetContainerImage permit to get the image name
+// It use the Opensearch version if version is not provided
+func (h *OpensearchDashboards) GetContainerImage(o *Opensearch) string {
+	version := "latest"
+	if h.Spec.Version != "" {
+		version = h.Spec.Version
+	} else if o != nil && o.Spec.Version != "" {
+		version = o.Spec.Version
+	}
+
+	image := defaultDashboardsImage
+	if h.Spec.Image != "" {
+		image = h.Spec.Image
+	}
+
+	return fmt.Sprintf("%s:%s", image, version)
+}
+
+// IsIngressEnabled return true if ingress is enabled
+func (h *OpensearchDashboards) IsIngressEnabled() bool {
+	return h.Spec.Endpoint != nil && h.Spec.Endpoint.Ingress != nil && h.Spec.Endpoint.Ingress.Enabled
+}
+
+// GetUrl permit to get the url to access on Dashboards
+// It use the ingress host if ingress is enabled
+func (h *OpensearchDashboards) GetUrl() string {
+	if h.IsIngressEnabled() {
+		return fmt.Sprintf("https://%s", h.Spec.Endpoint.Ingress.Host)
+	}
+
+	return fmt.Sprintf("http://%s.%s.svc:5601", h.GetServiceName(), h.Namespace)
+}
+
+// GenerateServerUser permit to generate the server user used by Dashboards to connect on Opensearch
+// Each instance get its own user, so the reserved kibanaserver account is not used
+// The password is generated by the OpensearchUser controller
+func (h *OpensearchDashboards) GenerateServerUser() (user *OpensearchUser) {
+	return &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetServerUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchUserSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Spec.OpensearchRef.Name,
+			},
+			Description: fmt.Sprintf("Dashboards server user for %s", h.Name),
+		},
+	}
+}
+
+// GenerateServerRoleMapping permit to map the kibana_server role to the server user
+// The role mapping is shared by all Dashboards connected on the same cluster, so it map the server users of each of them
+func (h *OpensearchDashboards) GenerateServerRoleMapping(dashboardsList []OpensearchDashboards) (roleMapping *OpensearchRoleMapping) {
+	users := []string{h.GetServerUserName()}
+	for _, dashboards := range dashboardsList {
+		if dashboards.Namespace == h.Namespace && dashboards.Spec.OpensearchRef.Name == h.Spec.OpensearchRef.Name && dashboards.DeletionTimestamp.IsZero() && !funk.ContainsString(users, dashboards.GetServerUserName()) {
+			users = append(users, dashboards.GetServerUserName())
+		}
+	}
+	sort.Strings(users)
+
+	return &OpensearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetServerUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchRoleMappingSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Spec.OpensearchRef.Name,
+			},
+			Role:        dashboardsServerRole,
+			Description: fmt.Sprintf("Dashboards server role mapping for %s", h.Name),
+			Users:       users,
+		},
+	}
+}
+
+// GenerateConfigMap permit to generate the config map that store the Dashboards config
+// It inject the settings to connect on Opensearch with the CA of API certificate
+func (h *OpensearchDashboards) GenerateConfigMap(o *Opensearch) (configMap *corev1.ConfigMap, err error) {
+	if o == nil {
+		return nil, errors.New("Opensearch must be provided")
+	}
+
+	injectedConfig := map[string]string{
+		"opensearch_dashboards.yml": fmt.Sprintf(`
+server.host: '0.0.0.0'
+opensearch.hosts: ['https://%s.%s.svc:9200']
+opensearch.ssl.verificationMode: full
+opensearch.ssl.certificateAuthorities: ['%s/certs/api/ca.crt']`, o.GetGlobalServiceName(), o.Namespace, dashboardsConfigPath),
+	}
+
+	expectedConfig, err := helper.MergeSettings(injectedConfig, h.Spec.Config)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when merge expected config with computed config")
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetConfigMapName(),
+			Labels:      h.Labels,
+			Annotations: h.Annotations,
+		},
+		Data: expectedConfig,
+	}, nil
+}
+
+// GenerateDeployment permit to generate the Dashboards deployment
+// The pods are restarted when the config change
+func (h *OpensearchDashboards) GenerateDeployment(o *Opensearch) (deployment *appv1.Deployment, err error) {
+	configMap, err := h.GenerateConfigMap(o)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when generate configMap")
+	}
+	config, err := json.Marshal(configMap.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when marshall config")
+	}
+	configHash := fmt.Sprintf("%x", sha256.Sum256(config))
+
+	cb := k8sbuilder.NewContainerBuilder()
+	ptb := k8sbuilder.NewPodTemplateBuilder()
+	dashboardsContainer := getContainer(h.Spec.PodTemplate, dashboardsContainerName)
+	if dashboardsContainer == nil {
+		dashboardsContainer = &corev1.Container{}
+	}
+
+	// Initialise Dashboards container from user provided
+	cb.WithContainer(dashboardsContainer).
+		Container().Name = dashboardsContainerName
+
+	// Compute EnvFrom
+	cb.WithEnvFrom(h.Spec.EnvFrom, k8sbuilder.Merge)
+
+	// Compute Env
+	// The credentials are read by the docker entrypoint
+	cb.WithEnv(h.Spec.Env, k8sbuilder.Merge).
+		WithEnv([]corev1.EnvVar{
+			{
+				Name: "OPENSEARCH_USERNAME",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: h.GetSecretNameForServerUser(),
+						},
+						Key: "username",
+					},
+				},
+			},
+			{
+				Name: "OPENSEARCH_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: h.GetSecretNameForServerUser(),
+						},
+						Key: defaultUserPasswordKey,
+					},
+				},
+			},
+		}, k8sbuilder.Merge)
+
+	// Compute ports
+	cb.WithPort([]corev1.ContainerPort{
+		{
+			Name:          "http",
+			ContainerPort: 5601,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}, k8sbuilder.Merge)
+
+	// Compute resources
+	cb.WithResource(h.Spec.Resources, k8sbuilder.Merge)
+
+	// Compute image
+	cb.WithImage(h.GetContainerImage(o), k8sbuilder.OverwriteIfDefaultValue)
+
+	// Compute image pull policy
+	cb.WithImagePullPolicy(h.Spec.ImagePullPolicy, k8sbuilder.OverwriteIfDefaultValue)
+
+	// Compute security context
+	cb.WithSecurityContext(&corev1.SecurityContext{
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{
+				"ALL",
+			},
+		},
+		RunAsUser:    pointer.Int64(1000),
+		RunAsNonRoot: pointer.Bool(true),
+	}, k8sbuilder.OverwriteIfDefaultValue)
+
+	// Compute volume mounts
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "opensearch-tls-api",
+			MountPath: fmt.Sprintf("%s/certs/api", dashboardsConfigPath),
+			ReadOnly:  true,
+		},
+	}
+	configKeys := funk.Keys(configMap.Data).([]string)
+	sort.Strings(configKeys)
+	for _, key := range configKeys {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "dashboards-config",
+			MountPath: fmt.Sprintf("%s/%s", dashboardsConfigPath, key),
+			SubPath:   key,
+		})
+	}
+	cb.WithVolumeMount(volumeMounts, k8sbuilder.Merge)
+
+	// Compute probes
+	cb.WithLivenessProbe(&corev1.Probe{
+		TimeoutSeconds:   5,
+		PeriodSeconds:    30,
+		FailureThreshold: 10,
+		SuccessThreshold: 1,
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(5601),
+			},
+		},
+	}, k8sbuilder.OverwriteIfDefaultValue)
+	cb.WithReadinessProbe(&corev1.Probe{
+		TimeoutSeconds:   5,
+		PeriodSeconds:    10,
+		FailureThreshold: 3,
+		SuccessThreshold: 1,
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(5601),
+			},
+		},
+	}, k8sbuilder.OverwriteIfDefaultValue)
+	cb.WithStartupProbe(&corev1.Probe{
+		InitialDelaySeconds: 10,
+		TimeoutSeconds:      5,
+		PeriodSeconds:       10,
+		FailureThreshold:    30,
+		SuccessThreshold:    1,
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(5601),
+			},
+		},
+	}, k8sbuilder.OverwriteIfDefaultValue)
+
+	// Initialise PodTemplate
+	ptb.WithPodTemplateSpec(h.Spec.PodTemplate)
+
+	// Compute labels
+	ptb.WithLabels(h.Labels, k8sbuilder.Merge).
+		WithLabels(map[string]string{
+			"cluster":    o.Name,
+			"dashboards": h.Name,
+		}, k8sbuilder.Merge)
+
+	// Compute annotations
+	ptb.WithAnnotations(h.Annotations, k8sbuilder.Merge).
+		WithAnnotations(map[string]string{
+			dashboardsConfigHashAnnotation: configHash,
+		}, k8sbuilder.Merge)
+
+	// Compute NodeSelector
+	ptb.WithNodeSelector(h.Spec.NodeSelector, k8sbuilder.Merge)
+
+	// Compute toleration
+	ptb.WithTolerations(h.Spec.Tolerations, k8sbuilder.Merge)
+
+	// Compute image pull secrets
+	ptb.WithImagePullSecrets(h.Spec.ImagePullSecrets, k8sbuilder.Merge)
+
+	// Compute containers
+	ptb.WithContainers([]corev1.Container{*cb.Container()}, k8sbuilder.Merge)
+
+	// Compute volumes
+	// Only the CA is needed to trust the API certificate
+	ptb.WithVolumes([]corev1.Volume{
+		{
+			Name: "opensearch-tls-api",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: o.GetSecretNameForTlsApi(),
+					Items: []corev1.KeyToPath{
+						{
+							Key:  "ca.crt",
+							Path: "ca.crt",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "dashboards-config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: h.GetConfigMapName(),
+					},
+				},
+			},
+		},
+	}, k8sbuilder.Merge)
+
+	// Compute Security context
+	ptb.WithSecurityContext(&corev1.PodSecurityContext{
+		FSGroup: pointer.Int64(1000),
+	}, k8sbuilder.Merge)
+
+	replicas := h.Spec.Replicas
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	deployment = &appv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetDeploymentName(),
+			Labels:      h.Labels,
+			Annotations: h.Annotations,
+		},
+		Spec: appv1.DeploymentSpec{
+			Replicas: pointer.Int32(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster":    o.Name,
+					"dashboards": h.Name,
+				},
+			},
+			Template: *ptb.PodTemplate(),
+		},
+	}
+
+	return deployment, nil
+}
+
+// GenerateService permit to generate the service to access on Dashboards
+func (h *OpensearchDashboards) GenerateService() (service *corev1.Service, err error) {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetServiceName(),
+			Labels:      h.Labels,
+			Annotations: h.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeClusterIP,
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Selector: map[string]string{
+				"cluster":    h.Spec.OpensearchRef.Name,
+				"dashboards": h.Name,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       5601,
+					TargetPort: intstr.FromInt(5601),
+				},
+			},
+		},
+	}, nil
+}
+
+// GenerateIngress permit to generate the ingress to access on Dashboards
+// It return nil if ingress is disabled
+func (h *OpensearchDashboards) GenerateIngress() (ingress *networkingv1.Ingress, err error) {
+	if !h.IsIngressEnabled() {
+		return nil, nil
+	}
+
+	if h.Spec.Endpoint.Ingress.Host == "" {
+		return nil, errors.New("endpoint.ingress.host must be provided")
+	}
+
+	defaultAnnotations := map[string]string{
+		"nginx.ingress.kubernetes.io/force-ssl-redirect": "true",
+	}
+
+	labels := funk.UnionStringMap(h.Spec.Endpoint.Ingress.Labels, h.Labels)
+	if len(labels) == 0 {
+		labels = nil
+	}
+	annotations := funk.UnionStringMap(defaultAnnotations, h.Spec.Endpoint.Ingress.Annotations, h.Annotations)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	pathType := networkingv1.PathTypePrefix
+	ingress = &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetIngressName(),
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: h.Spec.Endpoint.Ingress.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: h.GetServiceName(),
+											Port: networkingv1.ServiceBackendPort{Number: 5601},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			TLS: []networkingv1.IngressTLS{
+				{
+					Hosts:      []string{h.Spec.Endpoint.Ingress.Host},
+					SecretName: h.Spec.Endpoint.Ingress.SecretRef,
+				},
+			},
+		},
+	}
+
+	// Merge expected ingress with custom ingress spec
+	if err = helper.Merge(&ingress.Spec, h.Spec.Endpoint.Ingress.IngressSpec); err != nil {
+		return nil, errors.Wrap(err, "Error when merge ingress spec")
+	}
+
+	return ingress, nil
+}
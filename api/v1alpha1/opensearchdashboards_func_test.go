@@ -0,0 +1,259 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/test"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDashboardsNames(t *testing.T) {
+	o := &OpensearchDashboards{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+	}
+
+	assert.Equal(t, "test-osd", o.GetDeploymentName())
+	assert.Equal(t, "test-osd", o.GetServiceName())
+	assert.Equal(t, "test-osd", o.GetIngressName())
+	assert.Equal(t, "test-osd-config", o.GetConfigMapName())
+	assert.Equal(t, "test-osd-server", o.GetServerUserName())
+	assert.Equal(t, "test-osd-server-os-user", o.GetSecretNameForServerUser())
+}
+
+func TestGetDashboardsContainerImage(t *testing.T) {
+	o := &OpensearchDashboards{}
+	os := &Opensearch{}
+
+	// With default values
+	assert.Equal(t, "public.ecr.aws/opensearchproject/opensearch-dashboards:latest", o.GetContainerImage(os))
+
+	// When Opensearch version is set
+	os.Spec.Version = "2.3.0"
+	assert.Equal(t, "public.ecr.aws/opensearchproject/opensearch-dashboards:2.3.0", o.GetContainerImage(os))
+
+	// When version and image are set
+	o.Spec.Version = "2.4.0"
+	o.Spec.Image = "my-registry/opensearch-dashboards"
+	assert.Equal(t, "my-registry/opensearch-dashboards:2.4.0", o.GetContainerImage(os))
+}
+
+func TestGetDashboardsUrl(t *testing.T) {
+	o := &OpensearchDashboards{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+	}
+
+	// Without ingress
+	assert.Equal(t, "http://test-osd.default.svc:5601", o.GetUrl())
+
+	// With ingress
+	o.Spec.Endpoint = &DashboardsEndpointSpec{
+		Ingress: &DashboardsIngressSpec{
+			Enabled: true,
+			Host:    "dashboards.cluster.local",
+		},
+	}
+	assert.Equal(t, "https://dashboards.cluster.local", o.GetUrl())
+}
+
+func TestGenerateServerUser(t *testing.T) {
+	o := &OpensearchDashboards{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDashboardsSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+		},
+	}
+
+	user := o.GenerateServerUser()
+	assert.Equal(t, "test-osd-server", user.Name)
+	assert.Equal(t, "default", user.Namespace)
+	assert.Equal(t, "test", user.Spec.OpensearchRef.Name)
+	assert.Equal(t, "test-osd-server", user.GetUsername())
+	assert.True(t, user.IsGeneratedPassword())
+
+	roleMapping := o.GenerateServerRoleMapping(nil)
+	assert.Equal(t, "test-osd-server", roleMapping.Name)
+	assert.Equal(t, "test", roleMapping.Spec.OpensearchRef.Name)
+	assert.Equal(t, "kibana_server", roleMapping.GetRoleName())
+	assert.Equal(t, []string{"test-osd-server"}, roleMapping.Spec.Users)
+
+	// When other Dashboards are connected on the same cluster
+	dashboardsList := []OpensearchDashboards{
+		*o,
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "another",
+			},
+			Spec: OpensearchDashboardsSpec{
+				OpensearchRef: shared.OpensearchRef{
+					Name: "test",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "other-cluster",
+			},
+			Spec: OpensearchDashboardsSpec{
+				OpensearchRef: shared.OpensearchRef{
+					Name: "other",
+				},
+			},
+		},
+	}
+	roleMapping = o.GenerateServerRoleMapping(dashboardsList)
+	assert.Equal(t, []string{"another-osd-server", "test-osd-server"}, roleMapping.Spec.Users)
+}
+
+func TestGenerateDashboardsConfigMap(t *testing.T) {
+	os := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+	}
+	o := &OpensearchDashboards{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDashboardsSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Config: map[string]string{
+				"opensearch_dashboards.yml": `
+server.name: dashboards
+opensearch.requestTimeout: 60000`,
+			},
+		},
+	}
+
+	// When Opensearch is not provided
+	_, err := o.GenerateConfigMap(nil)
+	assert.Error(t, err)
+
+	configMap, err := o.GenerateConfigMap(os)
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/osd-configmap.yml", configMap)
+}
+
+func TestGenerateDashboardsDeployment(t *testing.T) {
+	os := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchSpec{
+			Version: "2.3.0",
+		},
+	}
+	o := &OpensearchDashboards{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDashboardsSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Replicas: 2,
+			Env: []corev1.EnvVar{
+				{
+					Name:  "NODE_OPTIONS",
+					Value: "--max-old-space-size=1024",
+				},
+			},
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		},
+	}
+
+	deployment, err := o.GenerateDeployment(os)
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/osd-deployment.yml", deployment)
+
+	// The pods are restarted when the config change
+	hash := deployment.Spec.Template.Annotations["opensearch.k8s.webcenter.fr/config-hash"]
+	o.Spec.Config = map[string]string{
+		"opensearch_dashboards.yml": "server.name: dashboards",
+	}
+	deployment, err = o.GenerateDeployment(os)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, deployment.Spec.Template.Annotations["opensearch.k8s.webcenter.fr/config-hash"])
+}
+
+func TestGenerateDashboardsService(t *testing.T) {
+	o := &OpensearchDashboards{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDashboardsSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+		},
+	}
+
+	service, err := o.GenerateService()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/osd-service.yml", service)
+}
+
+func TestGenerateDashboardsIngress(t *testing.T) {
+	o := &OpensearchDashboards{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDashboardsSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+		},
+	}
+
+	// When ingress is not set
+	ingress, err := o.GenerateIngress()
+	assert.NoError(t, err)
+	assert.Nil(t, ingress)
+
+	// When host is missing
+	o.Spec.Endpoint = &DashboardsEndpointSpec{
+		Ingress: &DashboardsIngressSpec{
+			Enabled: true,
+		},
+	}
+	_, err = o.GenerateIngress()
+	assert.Error(t, err)
+
+	// When ingress is enabled
+	o.Spec.Endpoint.Ingress.Host = "dashboards.cluster.local"
+	o.Spec.Endpoint.Ingress.SecretRef = "dashboards-tls"
+	o.Spec.Endpoint.Ingress.Annotations = map[string]string{
+		"cert-manager.io/cluster-issuer": "letsencrypt",
+	}
+	ingress, err = o.GenerateIngress()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/osd-ingress.yml", ingress)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchDashboardsSpec defines the desired state of OpensearchDashboards
+// +k8s:openapi-gen=true
+type OpensearchDashboardsSpec struct {
+
+	// OpensearchRef is the Opensearch cluster to connect Dashboards
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	shared.ImageSpec `json:",inline"`
+
+	// Version is the Dashboards version to use
+	// Default to the Opensearch version
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Replicas is the number of Dashboards instances
+	// Default to 1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:default=1
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Config is the Dashboards config files
+	// The key is the file name and the value is the file contend
+	// The connection settings to Opensearch are injected on opensearch_dashboards.yml
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// Env permit to set some environment variable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom permit to set some environment variable from config map or secret
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Resources permit to set resources on Dashboards container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector permit to set node selector
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations permit to set tolerations
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PodTemplate permit to overwrite the pod template of Dashboards
+	// The container must be named dashboards to be merged
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PodTemplate *corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// Endpoint permit to expose Dashboards
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Endpoint *DashboardsEndpointSpec `json:"endpoint,omitempty"`
+}
+
+type DashboardsEndpointSpec struct {
+
+	// Ingress permit to expose Dashboards with ingress
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Ingress *DashboardsIngressSpec `json:"ingress,omitempty"`
+}
+
+type DashboardsIngressSpec struct {
+
+	// Enabled permit to enabled / disabled ingress
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Host is the hostname to access on Dashboards
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Host string `json:"host,omitempty"`
+
+	// SecretRef is the secret ref that store certificates
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	SecretRef string `json:"secretRef,omitempty"`
+
+	// Labels to set in ingress
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations to set in ingress
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// IngressSpec it merge with expected ingress spec
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IngressSpec *networkingv1.IngressSpec `json:"ingressSpec,omitempty"`
+}
+
+// OpensearchDashboardsStatus defines the observed state of OpensearchDashboards
+type OpensearchDashboardsStatus struct {
+
+	// Url is the url to access on Dashboards
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Url string `json:"url,omitempty"`
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchDashboards is the Schema for the opensearchdashboards API
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,""},{Service,v1,""},{ConfigMap,v1,""},{Ingress,v1,""},{OpensearchUser,v1alpha1,""}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Url",type="string",JSONPath=".status.url"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchDashboards')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchDashboards struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchDashboardsSpec   `json:"spec,omitempty"`
+	Status OpensearchDashboardsStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchDashboardsList contains a list of OpensearchDashboards
+type OpensearchDashboardsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchDashboards `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchDashboards{}, &OpensearchDashboardsList{})
+}
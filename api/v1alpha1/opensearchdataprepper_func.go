@@ -0,0 +1,577 @@
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/disaster37/k8sbuilder"
+	"github.com/pkg/errors"
+	"github.com/thoas/go-funk"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultDataPrepperImage            = "opensearchproject/data-prepper"
+	dataPrepperContainerName           = "data-prepper"
+	dataPrepperPath                    = "/usr/share/data-prepper"
+	dataPrepperPipelinesHashAnnotation = "opensearch.k8s.webcenter.fr/pipelines-hash"
+)
+
+var (
+	// indexDatePatternRegexp match the date pattern of index name, like %{yyyy.MM.dd}
+	indexDatePatternRegexp = regexp.MustCompile(`%\{[^}]*\}`)
+
+	// dataPrepperIndexTypePatterns is the index patterns used by the opensearch sink for each index type
+	dataPrepperIndexTypePatterns = map[string]string{
+		"trace-analytics-raw":         "otel-v1-apm-span-*",
+		"trace-analytics-service-map": "otel-v1-apm-service-map*",
+	}
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchDataPrepper) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchDataPrepper) GetStatus() any {
+	return h.Status
+}
+
+// GetDeploymentName permit to get the deployment name
+func (h *OpensearchDataPrepper) GetDeploymentName() string {
+	return fmt.Sprintf("%s-dp", h.Name)
+}
+
+// GetServiceName permit to get the service name
+func (h *OpensearchDataPrepper) GetServiceName() string {
+	return h.GetDeploymentName()
+}
+
+// GetConfigMapName permit to get the configMap name that store the Data Prepper config
+func (h *OpensearchDataPrepper) GetConfigMapName() string {
+	return fmt.Sprintf("%s-config", h.GetDeploymentName())
+}
+
+// GetSecretNameForPipelines permit to get the secret name that store the pipelines
+// Pipelines are stored on secret because they contain the credentials
+func (h *OpensearchDataPrepper) GetSecretNameForPipelines() string {
+	return fmt.Sprintf("%s-pipelines", h.GetDeploymentName())
+}
+
+// GetUserName permit to get the OpensearchUser, OpensearchRole and OpensearchRoleMapping resource name
+// It also the user name and the role name on Opensearch
+func (h *OpensearchDataPrepper) GetUserName() string {
+	return h.GetDeploymentName()
+}
+
+// GetSecretNameForUser permit to get the secret name that store the credentials of Data Prepper user
+// The secret is generated by the OpensearchUser controller
+func (h *OpensearchDataPrepper) GetSecretNameForUser() string {
+	return h.GenerateUser().GetSecretNameForPassword()
+}
+
+// GetContainerImage permit to get the image name
+func (h *OpensearchDataPrepper) GetContainerImage() string {
+	version := "latest"
+	if h.Spec.Version != "" {
+		version = h.Spec.Version
+	}
+
+	image := defaultDataPrepperImage
+	if h.Spec.Image != "" {
+		image = h.Spec.Image
+	}
+
+	return fmt.Sprintf("%s:%s", image, version)
+}
+
+// GetIndexPatterns permit to get the index patterns where Data Prepper can write
+// When not provided, it use the indices of opensearch sinks without hosts
+func (h *OpensearchDataPrepper) GetIndexPatterns() (indexPatterns []string, err error) {
+	if len(h.Spec.IndexPatterns) > 0 {
+		return h.Spec.IndexPatterns, nil
+	}
+
+	indexPatterns = make([]string, 0)
+	for file, contend := range h.Spec.Pipelines {
+		pipelines, err := unmarshalPipelines(file, contend)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sink := range getOpensearchSinks(pipelines) {
+			if _, ok := sink["hosts"]; ok {
+				continue
+			}
+			if index, ok := sink["index"].(string); ok && index != "" {
+				indexPatterns = append(indexPatterns, indexDatePatternRegexp.ReplaceAllString(index, "*"))
+			} else if indexType, ok := sink["index_type"].(string); ok && dataPrepperIndexTypePatterns[indexType] != "" {
+				indexPatterns = append(indexPatterns, dataPrepperIndexTypePatterns[indexType])
+			}
+		}
+	}
+
+	if len(indexPatterns) == 0 {
+		return nil, errors.New("IndexPatterns must be provided when opensearch sinks not set index")
+	}
+
+	indexPatterns = funk.UniqString(indexPatterns)
+	sort.Strings(indexPatterns)
+
+	return indexPatterns, nil
+}
+
+// GenerateUser permit to generate the user used by Data Prepper to write on Opensearch
+// The password is generated by the OpensearchUser controller
+func (h *OpensearchDataPrepper) GenerateUser() (user *OpensearchUser) {
+	return &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchUserSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Spec.OpensearchRef.Name,
+			},
+			Description: fmt.Sprintf("Data Prepper user for %s", h.Name),
+		},
+	}
+}
+
+// GenerateRole permit to generate the role with the minimal permissions to write on index patterns
+func (h *OpensearchDataPrepper) GenerateRole() (role *OpensearchRole, err error) {
+	indexPatterns, err := h.GetIndexPatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpensearchRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchRoleSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Spec.OpensearchRef.Name,
+			},
+			Description: fmt.Sprintf("Data Prepper role for %s", h.Name),
+			ClusterPermissions: []string{
+				"cluster_monitor",
+				"cluster_composite_ops",
+				"indices:admin/template/get",
+				"indices:admin/template/put",
+				"indices:admin/index_template/get",
+				"indices:admin/index_template/put",
+			},
+			IndexPermissions: []IndexPermissionSpec{
+				{
+					IndexPatterns: indexPatterns,
+					AllowedActions: []string{
+						"create_index",
+						"write",
+						"indices_monitor",
+						"indices:admin/mapping/put",
+						"indices:admin/aliases",
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// GenerateRoleMapping permit to map the Data Prepper role to the Data Prepper user
+func (h *OpensearchDataPrepper) GenerateRoleMapping() (roleMapping *OpensearchRoleMapping) {
+	return &OpensearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetUserName(),
+			Labels:    h.Labels,
+		},
+		Spec: OpensearchRoleMappingSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: h.Spec.OpensearchRef.Name,
+			},
+			Description: fmt.Sprintf("Data Prepper role mapping for %s", h.Name),
+			Users:       []string{h.GetUserName()},
+		},
+	}
+}
+
+// GenerateConfigMap permit to generate the config map that store the Data Prepper config
+func (h *OpensearchDataPrepper) GenerateConfigMap() (configMap *corev1.ConfigMap, err error) {
+	injectedConfig := map[string]string{
+		"data-prepper-config.yaml": "ssl: false",
+	}
+
+	expectedConfig, err := helper.MergeSettings(h.Spec.Config, injectedConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when merge expected config with computed config")
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetConfigMapName(),
+			Labels:      h.Labels,
+			Annotations: h.Annotations,
+		},
+		Data: expectedConfig,
+	}, nil
+}
+
+// GeneratePipelinesSecret permit to generate the secret that store the pipelines
+// The endpoint, the CA and the credentials of Opensearch are injected on opensearch sinks without hosts
+func (h *OpensearchDataPrepper) GeneratePipelinesSecret(o *Opensearch, credentials *corev1.Secret) (secret *corev1.Secret, err error) {
+	if o == nil {
+		return nil, errors.New("Opensearch must be provided")
+	}
+	if credentials == nil {
+		return nil, errors.Errorf("Secret %s must be provided", h.GetSecretNameForUser())
+	}
+	if len(h.Spec.Pipelines) == 0 {
+		return nil, errors.New("Pipelines must be provided")
+	}
+
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetSecretNameForPipelines(),
+			Labels:      h.Labels,
+			Annotations: h.Annotations,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: make(map[string][]byte, len(h.Spec.Pipelines)),
+	}
+
+	for file, contend := range h.Spec.Pipelines {
+		pipelines, err := unmarshalPipelines(file, contend)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sink := range getOpensearchSinks(pipelines) {
+			if _, ok := sink["hosts"]; ok {
+				continue
+			}
+			sink["hosts"] = []string{fmt.Sprintf("https://%s.%s.svc:9200", o.GetGlobalServiceName(), o.Namespace)}
+			sink["cert"] = fmt.Sprintf("%s/certs/api/ca.crt", dataPrepperPath)
+			sink["username"] = string(credentials.Data["username"])
+			sink["password"] = string(credentials.Data[defaultUserPasswordKey])
+		}
+
+		b, err := yaml.Marshal(pipelines)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error when marshall pipelines %s", file)
+		}
+		secret.Data[file] = b
+	}
+
+	return secret, nil
+}
+
+// GenerateDeployment permit to generate the Data Prepper deployment
+// The pods are restarted when the pipelines or the config change
+func (h *OpensearchDataPrepper) GenerateDeployment(o *Opensearch, credentials *corev1.Secret) (deployment *appv1.Deployment, err error) {
+	configMap, err := h.GenerateConfigMap()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when generate configMap")
+	}
+	pipelinesSecret, err := h.GeneratePipelinesSecret(o, credentials)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when generate pipelines secret")
+	}
+	b, err := json.Marshal([]any{configMap.Data, pipelinesSecret.Data})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when marshall pipelines")
+	}
+	pipelinesHash := fmt.Sprintf("%x", sha256.Sum256(b))
+
+	cb := k8sbuilder.NewContainerBuilder()
+	ptb := k8sbuilder.NewPodTemplateBuilder()
+	dataPrepperContainer := getContainer(h.Spec.PodTemplate, dataPrepperContainerName)
+	if dataPrepperContainer == nil {
+		dataPrepperContainer = &corev1.Container{}
+	}
+
+	// Initialise Data Prepper container from user provided
+	cb.WithContainer(dataPrepperContainer).
+		Container().Name = dataPrepperContainerName
+
+	// Compute EnvFrom
+	cb.WithEnvFrom(h.Spec.EnvFrom, k8sbuilder.Merge)
+
+	// Compute Env
+	cb.WithEnv(h.Spec.Env, k8sbuilder.Merge)
+
+	// Compute ports
+	// The server port expose the core API and metrics
+	ports := []corev1.ContainerPort{
+		{
+			Name:          "server",
+			ContainerPort: 4900,
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+	for _, port := range h.Spec.Ports {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.Port,
+			Protocol:      port.Protocol,
+		})
+	}
+	cb.WithPort(ports, k8sbuilder.Merge)
+
+	// Compute resources
+	cb.WithResource(h.Spec.Resources, k8sbuilder.Merge)
+
+	// Compute image
+	cb.WithImage(h.GetContainerImage(), k8sbuilder.OverwriteIfDefaultValue)
+
+	// Compute image pull policy
+	cb.WithImagePullPolicy(h.Spec.ImagePullPolicy, k8sbuilder.OverwriteIfDefaultValue)
+
+	// Compute volume mounts
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "opensearch-tls-api",
+			MountPath: fmt.Sprintf("%s/certs/api", dataPrepperPath),
+			ReadOnly:  true,
+		},
+		{
+			Name:      "data-prepper-pipelines",
+			MountPath: fmt.Sprintf("%s/pipelines", dataPrepperPath),
+			ReadOnly:  true,
+		},
+	}
+	configKeys := funk.Keys(configMap.Data).([]string)
+	sort.Strings(configKeys)
+	for _, key := range configKeys {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "data-prepper-config",
+			MountPath: fmt.Sprintf("%s/config/%s", dataPrepperPath, key),
+			SubPath:   key,
+		})
+	}
+	cb.WithVolumeMount(volumeMounts, k8sbuilder.Merge)
+
+	// Compute probes
+	cb.WithLivenessProbe(&corev1.Probe{
+		TimeoutSeconds:   5,
+		PeriodSeconds:    30,
+		FailureThreshold: 10,
+		SuccessThreshold: 1,
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(4900),
+			},
+		},
+	}, k8sbuilder.OverwriteIfDefaultValue)
+	cb.WithReadinessProbe(&corev1.Probe{
+		TimeoutSeconds:   5,
+		PeriodSeconds:    10,
+		FailureThreshold: 3,
+		SuccessThreshold: 1,
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(4900),
+			},
+		},
+	}, k8sbuilder.OverwriteIfDefaultValue)
+	cb.WithStartupProbe(&corev1.Probe{
+		InitialDelaySeconds: 10,
+		TimeoutSeconds:      5,
+		PeriodSeconds:       10,
+		FailureThreshold:    30,
+		SuccessThreshold:    1,
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(4900),
+			},
+		},
+	}, k8sbuilder.OverwriteIfDefaultValue)
+
+	// Initialise PodTemplate
+	ptb.WithPodTemplateSpec(h.Spec.PodTemplate)
+
+	// Compute labels
+	ptb.WithLabels(h.Labels, k8sbuilder.Merge).
+		WithLabels(map[string]string{
+			"cluster":     o.Name,
+			"dataPrepper": h.Name,
+		}, k8sbuilder.Merge)
+
+	// Compute annotations
+	ptb.WithAnnotations(h.Annotations, k8sbuilder.Merge).
+		WithAnnotations(map[string]string{
+			dataPrepperPipelinesHashAnnotation: pipelinesHash,
+		}, k8sbuilder.Merge)
+
+	// Compute NodeSelector
+	ptb.WithNodeSelector(h.Spec.NodeSelector, k8sbuilder.Merge)
+
+	// Compute toleration
+	ptb.WithTolerations(h.Spec.Tolerations, k8sbuilder.Merge)
+
+	// Compute image pull secrets
+	ptb.WithImagePullSecrets(h.Spec.ImagePullSecrets, k8sbuilder.Merge)
+
+	// Compute containers
+	ptb.WithContainers([]corev1.Container{*cb.Container()}, k8sbuilder.Merge)
+
+	// Compute volumes
+	// Only the CA is needed to trust the API certificate
+	ptb.WithVolumes([]corev1.Volume{
+		{
+			Name: "opensearch-tls-api",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: o.GetSecretNameForTlsApi(),
+					Items: []corev1.KeyToPath{
+						{
+							Key:  "ca.crt",
+							Path: "ca.crt",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "data-prepper-pipelines",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: h.GetSecretNameForPipelines(),
+				},
+			},
+		},
+		{
+			Name: "data-prepper-config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: h.GetConfigMapName(),
+					},
+				},
+			},
+		},
+	}, k8sbuilder.Merge)
+
+	replicas := h.Spec.Replicas
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	deployment = &appv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetDeploymentName(),
+			Labels:      h.Labels,
+			Annotations: h.Annotations,
+		},
+		Spec: appv1.DeploymentSpec{
+			Replicas: pointer.Int32(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster":     o.Name,
+					"dataPrepper": h.Name,
+				},
+			},
+			Template: *ptb.PodTemplate(),
+		},
+	}
+
+	return deployment, nil
+}
+
+// GenerateService permit to generate the service to access on Data Prepper server and pipeline sources
+func (h *OpensearchDataPrepper) GenerateService() (service *corev1.Service, err error) {
+	ports := []corev1.ServicePort{
+		{
+			Name:       "server",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       4900,
+			TargetPort: intstr.FromInt(4900),
+		},
+	}
+	for _, port := range h.Spec.Ports {
+		if port.TargetPort.IntValue() == 0 && port.TargetPort.StrVal == "" {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+		ports = append(ports, port)
+	}
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   h.Namespace,
+			Name:        h.GetServiceName(),
+			Labels:      h.Labels,
+			Annotations: h.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeClusterIP,
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Selector: map[string]string{
+				"cluster":     h.Spec.OpensearchRef.Name,
+				"dataPrepper": h.Name,
+			},
+			Ports: ports,
+		},
+	}, nil
+}
+
+// unmarshalPipelines permit to read the pipelines of pipelines file
+func unmarshalPipelines(file string, contend string) (pipelines map[string]any, err error) {
+	pipelines = map[string]any{}
+	if err = yaml.Unmarshal([]byte(contend), &pipelines); err != nil {
+		return nil, errors.Wrapf(err, "Error when unmarshall pipelines %s", file)
+	}
+
+	return pipelines, nil
+}
+
+// getOpensearchSinks permit to get the opensearch sinks of all pipelines
+// The sinks are returned as reference, so they can be updated
+func getOpensearchSinks(pipelines map[string]any) (sinks []map[string]any) {
+	sinks = make([]map[string]any, 0)
+
+	for _, pipeline := range pipelines {
+		p, ok := pipeline.(map[string]any)
+		if !ok {
+			continue
+		}
+		pipelineSinks, ok := p["sink"].([]any)
+		if !ok {
+			continue
+		}
+		for _, pipelineSink := range pipelineSinks {
+			s, ok := pipelineSink.(map[string]any)
+			if !ok {
+				continue
+			}
+			if _, ok := s["opensearch"]; !ok {
+				continue
+			}
+			sink, ok := s["opensearch"].(map[string]any)
+			if !ok {
+				// Sink without options
+				sink = map[string]any{}
+				s["opensearch"] = sink
+			}
+			sinks = append(sinks, sink)
+		}
+	}
+
+	return sinks
+}
@@ -0,0 +1,259 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/test"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+var testDataPrepperPipelines = `
+log-pipeline:
+  source:
+    http:
+      ssl: false
+  sink:
+    - opensearch:
+        index: apache-logs-%{yyyy.MM.dd}
+    - opensearch:
+        hosts: ["https://external:9200"]
+        index: external-logs
+    - stdout:
+trace-pipeline:
+  source:
+    pipeline:
+      name: otel
+  sink:
+    - opensearch:
+        index_type: trace-analytics-raw
+`
+
+func TestGetDataPrepperNames(t *testing.T) {
+	o := &OpensearchDataPrepper{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+	}
+
+	assert.Equal(t, "test-dp", o.GetDeploymentName())
+	assert.Equal(t, "test-dp", o.GetServiceName())
+	assert.Equal(t, "test-dp-config", o.GetConfigMapName())
+	assert.Equal(t, "test-dp-pipelines", o.GetSecretNameForPipelines())
+	assert.Equal(t, "test-dp", o.GetUserName())
+	assert.Equal(t, "test-dp-os-user", o.GetSecretNameForUser())
+}
+
+func TestGetDataPrepperContainerImage(t *testing.T) {
+	o := &OpensearchDataPrepper{}
+
+	// With default values
+	assert.Equal(t, "opensearchproject/data-prepper:latest", o.GetContainerImage())
+
+	// When version and image are set
+	o.Spec.Version = "2.0.1"
+	o.Spec.Image = "my-registry/data-prepper"
+	assert.Equal(t, "my-registry/data-prepper:2.0.1", o.GetContainerImage())
+}
+
+func TestGetDataPrepperIndexPatterns(t *testing.T) {
+	o := &OpensearchDataPrepper{
+		Spec: OpensearchDataPrepperSpec{
+			Pipelines: map[string]string{
+				"pipelines.yaml": testDataPrepperPipelines,
+			},
+		},
+	}
+
+	// From opensearch sinks
+	indexPatterns, err := o.GetIndexPatterns()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apache-logs-*", "otel-v1-apm-span-*"}, indexPatterns)
+
+	// When provided
+	o.Spec.IndexPatterns = []string{"logs-*"}
+	indexPatterns, err = o.GetIndexPatterns()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"logs-*"}, indexPatterns)
+
+	// When sinks not set index
+	o.Spec.IndexPatterns = nil
+	o.Spec.Pipelines = map[string]string{
+		"pipelines.yaml": `
+log-pipeline:
+  source:
+    http:
+  sink:
+    - opensearch:
+`,
+	}
+	_, err = o.GetIndexPatterns()
+	assert.Error(t, err)
+
+	// When pipelines is not valid yaml
+	o.Spec.Pipelines = map[string]string{
+		"pipelines.yaml": "log-pipeline: [",
+	}
+	_, err = o.GetIndexPatterns()
+	assert.Error(t, err)
+}
+
+func TestGenerateDataPrepperRole(t *testing.T) {
+	o := &OpensearchDataPrepper{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDataPrepperSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Pipelines: map[string]string{
+				"pipelines.yaml": testDataPrepperPipelines,
+			},
+		},
+	}
+
+	user := o.GenerateUser()
+	assert.Equal(t, "test-dp", user.Name)
+	assert.Equal(t, "test-dp", user.GetUsername())
+	assert.True(t, user.IsGeneratedPassword())
+
+	role, err := o.GenerateRole()
+	assert.NoError(t, err)
+	assert.Equal(t, "test-dp", role.GetRoleName())
+	assert.Equal(t, "test", role.Spec.OpensearchRef.Name)
+	assert.Equal(t, []string{"apache-logs-*", "otel-v1-apm-span-*"}, role.Spec.IndexPermissions[0].IndexPatterns)
+	assert.NotContains(t, role.Spec.IndexPermissions[0].AllowedActions, "indices_all")
+
+	roleMapping := o.GenerateRoleMapping()
+	assert.Equal(t, "test-dp", roleMapping.GetRoleName())
+	assert.Equal(t, []string{"test-dp"}, roleMapping.Spec.Users)
+}
+
+func TestGenerateDataPrepperPipelinesSecret(t *testing.T) {
+	os := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+	}
+	credentials := &corev1.Secret{
+		Data: map[string][]byte{
+			"username": []byte("test-dp"),
+			"password": []byte("secret"),
+		},
+	}
+	o := &OpensearchDataPrepper{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDataPrepperSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Pipelines: map[string]string{
+				"pipelines.yaml": testDataPrepperPipelines,
+			},
+		},
+	}
+
+	// When credentials are missing
+	_, err := o.GeneratePipelinesSecret(os, nil)
+	assert.Error(t, err)
+
+	secret, err := o.GeneratePipelinesSecret(os, credentials)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-dp-pipelines", secret.Name)
+
+	pipelines := map[string]any{}
+	assert.NoError(t, yaml.Unmarshal(secret.Data["pipelines.yaml"], &pipelines))
+	sinks := pipelines["log-pipeline"].(map[string]any)["sink"].([]any)
+
+	// Sink without hosts target the referenced cluster
+	assert.Equal(t, map[string]any{
+		"index":    "apache-logs-%{yyyy.MM.dd}",
+		"hosts":    []any{"https://test-os.default.svc:9200"},
+		"cert":     "/usr/share/data-prepper/certs/api/ca.crt",
+		"username": "test-dp",
+		"password": "secret",
+	}, sinks[0].(map[string]any)["opensearch"])
+
+	// Sink with hosts is not changed
+	assert.Equal(t, map[string]any{
+		"index": "external-logs",
+		"hosts": []any{"https://external:9200"},
+	}, sinks[1].(map[string]any)["opensearch"])
+}
+
+func TestGenerateDataPrepperDeployment(t *testing.T) {
+	os := &Opensearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+	}
+	credentials := &corev1.Secret{
+		Data: map[string][]byte{
+			"username": []byte("test-dp"),
+			"password": []byte("secret"),
+		},
+	}
+	o := &OpensearchDataPrepper{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: OpensearchDataPrepperSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Version: "2.0.1",
+			Pipelines: map[string]string{
+				"pipelines.yaml": testDataPrepperPipelines,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:     "http",
+					Port:     2021,
+					Protocol: corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+
+	deployment, err := o.GenerateDeployment(os, credentials)
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/dp-deployment.yml", deployment)
+
+	// The pods are restarted when the password change
+	hash := deployment.Spec.Template.Annotations["opensearch.k8s.webcenter.fr/pipelines-hash"]
+	credentials.Data["password"] = []byte("new-secret")
+	deployment, err = o.GenerateDeployment(os, credentials)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, deployment.Spec.Template.Annotations["opensearch.k8s.webcenter.fr/pipelines-hash"])
+
+	// Service expose server and sources ports
+	service, err := o.GenerateService()
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.ServicePort{
+		{
+			Name:       "server",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       4900,
+			TargetPort: intstr.FromInt(4900),
+		},
+		{
+			Name:       "http",
+			Protocol:   corev1.ProtocolTCP,
+			Port:       2021,
+			TargetPort: intstr.FromInt(2021),
+		},
+	}, service.Spec.Ports)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchDataPrepperSpec defines the desired state of OpensearchDataPrepper
+// +k8s:openapi-gen=true
+type OpensearchDataPrepperSpec struct {
+
+	// OpensearchRef is the Opensearch cluster where Data Prepper write
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	shared.ImageSpec `json:",inline"`
+
+	// Version is the Data Prepper version to use
+	// Default to latest
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Replicas is the number of Data Prepper instances
+	// Default to 1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:default=1
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Pipelines is the pipelines config files
+	// The key is the file name and the value is the file contend
+	// The endpoint, the CA and the credentials of the referenced cluster are injected on opensearch sinks without hosts
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Pipelines map[string]string `json:"pipelines"`
+
+	// Config is the Data Prepper config files, like data-prepper-config.yaml or log4j2-rolling.properties
+	// The key is the file name and the value is the file contend
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// IndexPatterns is the index patterns where Data Prepper can write
+	// Default to the indices of opensearch sinks, date patterns are replaced by wildcard
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IndexPatterns []string `json:"indexPatterns,omitempty"`
+
+	// Ports is the ports exposed by pipeline sources, like http source on port 2021
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Ports []corev1.ServicePort `json:"ports,omitempty"`
+
+	// Env permit to set some environment variable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom permit to set some environment variable from config map or secret
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Resources permit to set resources on Data Prepper container
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector permit to set node selector
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations permit to set tolerations
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PodTemplate permit to overwrite the pod template of Data Prepper
+	// The container must be named data-prepper to be merged
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PodTemplate *corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+}
+
+// OpensearchDataPrepperStatus defines the observed state of OpensearchDataPrepper
+type OpensearchDataPrepperStatus struct {
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchDataPrepper is the Schema for the opensearchdatapreppers API
+// +operator-sdk:csv:customresourcedefinitions:resources={{Deployment,v1,""},{Service,v1,""},{ConfigMap,v1,""},{Secret,v1,""},{OpensearchUser,v1alpha1,""},{OpensearchRole,v1alpha1,""},{OpensearchRoleMapping,v1alpha1,""}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchDataPrepper')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchDataPrepper struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchDataPrepperSpec   `json:"spec,omitempty"`
+	Status OpensearchDataPrepperStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchDataPrepperList contains a list of OpensearchDataPrepper
+type OpensearchDataPrepperList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchDataPrepper `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchDataPrepper{}, &OpensearchDataPrepperList{})
+}
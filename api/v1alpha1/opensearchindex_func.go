@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	rolloverAliasSetting = "index.plugins.index_state_management.rollover_alias"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchIndex) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchIndex) GetStatus() any {
+	return h.Status
+}
+
+// GetIndexName permit to get the index name on Opensearch
+// It use the resource name if not provided
+func (h *OpensearchIndex) GetIndexName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.Name
+}
+
+// IsDeletionAllowed return true if the index and its data can be deleted with the resource
+func (h *OpensearchIndex) IsDeletionAllowed() bool {
+	return h.Spec.DeletionPolicy == IndexDeletionPolicyDelete
+}
+
+// GenerateIndex permit to generate the index to create
+// When rollover alias is provided, it is set as write alias and as ISM rollover alias
+func (h *OpensearchIndex) GenerateIndex() (index *opensearch.Index, err error) {
+	t, err := h.Spec.TemplateSpec.generateTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	index = &opensearch.Index{}
+	if t != nil {
+		index.Settings = t.Settings
+		index.Mappings = t.Mappings
+		index.Aliases = t.Aliases
+	}
+
+	if h.Spec.RolloverAlias != "" {
+		if index.Settings == nil {
+			index.Settings = map[string]any{}
+		}
+		if index.Aliases == nil {
+			index.Aliases = map[string]any{}
+		}
+		index.Settings[rolloverAliasSetting] = h.Spec.RolloverAlias
+		index.Aliases[h.Spec.RolloverAlias] = map[string]any{
+			"is_write_index": true,
+		}
+	}
+
+	return index, nil
+}
@@ -0,0 +1,76 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	"github.com/webcenter-fr/opensearch-operator/pkg/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetIndexName(t *testing.T) {
+	o := &OpensearchIndex{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "logs", o.GetIndexName())
+
+	// When name is provided
+	o.Spec.Name = "logs-000001"
+	assert.Equal(t, "logs-000001", o.GetIndexName())
+}
+
+func TestIsDeletionAllowed(t *testing.T) {
+	o := &OpensearchIndex{}
+
+	// With default value
+	assert.False(t, o.IsDeletionAllowed())
+
+	// When retain
+	o.Spec.DeletionPolicy = IndexDeletionPolicyRetain
+	assert.False(t, o.IsDeletionAllowed())
+
+	// When delete
+	o.Spec.DeletionPolicy = IndexDeletionPolicyDelete
+	assert.True(t, o.IsDeletionAllowed())
+}
+
+func TestGenerateIndex(t *testing.T) {
+	o := &OpensearchIndex{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs",
+		},
+		Spec: OpensearchIndexSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Name: "logs-000001",
+		},
+	}
+
+	// With default values
+	index, err := o.GenerateIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, &opensearch.Index{}, index)
+
+	// With rollover alias
+	o.Spec.RolloverAlias = "logs"
+	o.Spec.Settings = `{"number_of_shards": 1, "number_of_replicas": 1}`
+	o.Spec.Mappings = `{"properties": {"message": {"type": "text"}}}`
+	o.Spec.Aliases = `{"all-logs": {}}`
+	index, err = o.GenerateIndex()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-index.yml", index)
+
+	// When JSON is not valid
+	o.Spec.Settings = `{"number_of_shards":`
+	_, err = o.GenerateIndex()
+	assert.Error(t, err)
+}
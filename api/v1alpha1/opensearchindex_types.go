@@ -0,0 +1,98 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	IndexDeletionPolicyRetain = "Retain"
+	IndexDeletionPolicyDelete = "Delete"
+)
+
+// OpensearchIndexSpec defines the desired state of OpensearchIndex
+// +k8s:openapi-gen=true
+type OpensearchIndexSpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the index
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Name is the index name on Opensearch, like logs-000001
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// RolloverAlias is the write alias used by ISM rollover, like logs
+	// The alias is only set when create the index, because rollover move it on the next index
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	RolloverAlias string `json:"rolloverAlias,omitempty"`
+
+	// DeletionPolicy permit to delete the index and its data when the resource is deleted
+	// Default to Retain
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// TemplateSpec is the settings, mappings and aliases of the index
+	// Only the dynamic settings are updated on existing index
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TemplateSpec `json:",inline"`
+}
+
+// OpensearchIndexStatus defines the observed state of OpensearchIndex
+type OpensearchIndexStatus struct {
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchIndex is the Schema for the opensearchindices API
+// +operator-sdk:csv:customresourcedefinitions:resources={{None,None,None}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Rollover alias",type="string",JSONPath=".spec.rolloverAlias"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchIndex')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchIndex struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchIndexSpec   `json:"spec,omitempty"`
+	Status OpensearchIndexStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchIndexList contains a list of OpensearchIndex
+type OpensearchIndexList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchIndex `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchIndex{}, &OpensearchIndexList{})
+}
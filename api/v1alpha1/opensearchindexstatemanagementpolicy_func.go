@@ -0,0 +1,110 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchIndexStateManagementPolicy) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchIndexStateManagementPolicy) GetStatus() any {
+	return h.Status
+}
+
+// GetPolicyName permit to get the policy name on Opensearch
+// It use the resource name if not provided
+func (h *OpensearchIndexStateManagementPolicy) GetPolicyName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.Name
+}
+
+// GenerateIsmPolicy permit to generate the index state management policy
+// It check that default state and transitions target existing states
+func (h *OpensearchIndexStateManagementPolicy) GenerateIsmPolicy() (policy *opensearch.IsmPolicy, err error) {
+	if len(h.Spec.States) == 0 {
+		return nil, errors.New("States must be provided")
+	}
+
+	states := map[string]bool{}
+	for _, state := range h.Spec.States {
+		if state.Name == "" {
+			return nil, errors.New("Name must be provided on states")
+		}
+		if states[state.Name] {
+			return nil, errors.Errorf("State %s is declared many times", state.Name)
+		}
+		states[state.Name] = true
+	}
+
+	policy = &opensearch.IsmPolicy{
+		Description:  h.Spec.Description,
+		DefaultState: h.Spec.DefaultState,
+		States:       make([]opensearch.IsmState, 0, len(h.Spec.States)),
+	}
+	if policy.DefaultState == "" {
+		policy.DefaultState = h.Spec.States[0].Name
+	}
+	if !states[policy.DefaultState] {
+		return nil, errors.Errorf("Default state %s not exist", policy.DefaultState)
+	}
+
+	for _, state := range h.Spec.States {
+		ismState := opensearch.IsmState{
+			Name:        state.Name,
+			Actions:     make([]map[string]any, 0, len(state.Actions)),
+			Transitions: make([]opensearch.IsmTransition, 0, len(state.Transitions)),
+		}
+
+		for _, action := range state.Actions {
+			ismAction := map[string]any{}
+			if err = json.Unmarshal([]byte(action), &ismAction); err != nil {
+				return nil, errors.Wrapf(err, "Error when decode action on state %s", state.Name)
+			}
+			ismState.Actions = append(ismState.Actions, ismAction)
+		}
+
+		for _, transition := range state.Transitions {
+			if !states[transition.StateName] {
+				return nil, errors.Errorf("State %s used by transition on state %s not exist", transition.StateName, state.Name)
+			}
+			ismTransition := opensearch.IsmTransition{
+				StateName: transition.StateName,
+			}
+			if transition.MinIndexAge != "" || transition.MinRolloverAge != "" || transition.MinDocCount != nil || transition.MinSize != "" {
+				ismTransition.Conditions = &opensearch.IsmTransitionConditions{
+					MinIndexAge:    transition.MinIndexAge,
+					MinRolloverAge: transition.MinRolloverAge,
+					MinDocCount:    transition.MinDocCount,
+					MinSize:        transition.MinSize,
+				}
+			}
+			ismState.Transitions = append(ismState.Transitions, ismTransition)
+		}
+
+		policy.States = append(policy.States, ismState)
+	}
+
+	if h.Spec.IsmTemplate != nil {
+		if len(h.Spec.IsmTemplate.IndexPatterns) == 0 {
+			return nil, errors.New("IndexPatterns must be provided on ISM template")
+		}
+		policy.IsmTemplate = []opensearch.IsmTemplate{
+			{
+				IndexPatterns: h.Spec.IsmTemplate.IndexPatterns,
+				Priority:      h.Spec.IsmTemplate.Priority,
+			},
+		}
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,91 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetIsmPolicyName(t *testing.T) {
+	o := &OpensearchIndexStateManagementPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "logs", o.GetPolicyName())
+
+	// When name is provided
+	o.Spec.Name = "logs-retention"
+	assert.Equal(t, "logs-retention", o.GetPolicyName())
+}
+
+func TestGenerateIsmPolicy(t *testing.T) {
+	o := &OpensearchIndexStateManagementPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs",
+		},
+		Spec: OpensearchIndexStateManagementPolicySpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+		},
+	}
+
+	// When states is missing
+	_, err := o.GenerateIsmPolicy()
+	assert.Error(t, err)
+
+	// With all options
+	o.Spec.Description = "Logs retention"
+	o.Spec.States = []IsmStateSpec{
+		{
+			Name: "hot",
+			Actions: []string{
+				`{"rollover": {"min_size": "50gb"}}`,
+			},
+			Transitions: []IsmTransitionSpec{
+				{
+					StateName:   "delete",
+					MinIndexAge: "30d",
+				},
+			},
+		},
+		{
+			Name: "delete",
+			Actions: []string{
+				`{"delete": {}}`,
+			},
+		},
+	}
+	o.Spec.IsmTemplate = &IsmTemplateSpec{
+		IndexPatterns: []string{"logs-*"},
+		Priority:      100,
+	}
+	policy, err := o.GenerateIsmPolicy()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-ismpolicy.yml", policy)
+
+	// When default state not exist
+	o.Spec.DefaultState = "warm"
+	_, err = o.GenerateIsmPolicy()
+	assert.Error(t, err)
+
+	// When transition target state that not exist
+	o.Spec.DefaultState = ""
+	o.Spec.States[0].Transitions[0].StateName = "warm"
+	_, err = o.GenerateIsmPolicy()
+	assert.Error(t, err)
+
+	// When action is not valid JSON
+	o.Spec.States[0].Transitions[0].StateName = "delete"
+	o.Spec.States[0].Actions[0] = `{"rollover":`
+	_, err = o.GenerateIsmPolicy()
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchIndexStateManagementPolicySpec defines the desired state of OpensearchIndexStateManagementPolicy
+// +k8s:openapi-gen=true
+type OpensearchIndexStateManagementPolicySpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the ISM policy
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Name is the policy name on Opensearch
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description is the policy description
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// DefaultState is the state of new managed indices
+	// Default to the first state
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DefaultState string `json:"defaultState,omitempty"`
+
+	// States is the states of the policy
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	States []IsmStateSpec `json:"states"`
+
+	// IsmTemplate permit to apply automatically the policy on new indices
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IsmTemplate *IsmTemplateSpec `json:"ismTemplate,omitempty"`
+
+	// ReapplyOnChange permit to apply the new version of policy on indices already managed by it
+	// Default to false, so only new indices use the new version
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ReapplyOnChange bool `json:"reapplyOnChange,omitempty"`
+}
+
+type IsmStateSpec struct {
+
+	// Name is the state name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Name string `json:"name"`
+
+	// Actions is the actions to run on the state as JSON, like {"rollover": {"min_size": "50gb"}}
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Actions []string `json:"actions,omitempty"`
+
+	// Transitions is the transitions to other states
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Transitions []IsmTransitionSpec `json:"transitions,omitempty"`
+}
+
+type IsmTransitionSpec struct {
+
+	// StateName is the state to transition
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	StateName string `json:"stateName"`
+
+	// MinIndexAge is the minimum age of index to transition, like 30d
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MinIndexAge string `json:"minIndexAge,omitempty"`
+
+	// MinRolloverAge is the minimum age since the rollover to transition, like 1d
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MinRolloverAge string `json:"minRolloverAge,omitempty"`
+
+	// MinDocCount is the minimum number of documents to transition
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MinDocCount *int64 `json:"minDocCount,omitempty"`
+
+	// MinSize is the minimum size of primary shards to transition, like 50gb
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MinSize string `json:"minSize,omitempty"`
+}
+
+type IsmTemplateSpec struct {
+
+	// IndexPatterns is the index patterns where to apply the policy
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// Priority is the priority when many policies match the same index
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Priority int64 `json:"priority,omitempty"`
+}
+
+// OpensearchIndexStateManagementPolicyStatus defines the observed state of OpensearchIndexStateManagementPolicy
+type OpensearchIndexStateManagementPolicyStatus struct {
+
+	// ManagedIndices is the number of indices managed by the policy when it was last applied
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	ManagedIndices int64 `json:"managedIndices,omitempty"`
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchIndexStateManagementPolicy is the Schema for the opensearchindexstatemanagementpolicies API
+// +operator-sdk:csv:customresourcedefinitions:resources={{None,None,None}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchIndexStateManagementPolicy')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchIndexStateManagementPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchIndexStateManagementPolicySpec   `json:"spec,omitempty"`
+	Status OpensearchIndexStateManagementPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchIndexStateManagementPolicyList contains a list of OpensearchIndexStateManagementPolicy
+type OpensearchIndexStateManagementPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchIndexStateManagementPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchIndexStateManagementPolicy{}, &OpensearchIndexStateManagementPolicyList{})
+}
@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchIndexTemplate) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchIndexTemplate) GetStatus() any {
+	return h.Status
+}
+
+// GetTemplateName permit to get the index template name on Opensearch
+// It use the resource name if not provided
+func (h *OpensearchIndexTemplate) GetTemplateName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.Name
+}
+
+// GenerateIndexTemplate permit to generate the composable index template
+func (h *OpensearchIndexTemplate) GenerateIndexTemplate() (template *opensearch.IndexTemplate, err error) {
+	if len(h.Spec.IndexPatterns) == 0 {
+		return nil, errors.New("IndexPatterns must be provided")
+	}
+
+	t, err := h.Spec.TemplateSpec.generateTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &opensearch.IndexTemplate{
+		IndexPatterns: h.Spec.IndexPatterns,
+		Template:      t,
+		ComposedOf:    h.Spec.ComposedOf,
+		Priority:      h.Spec.Priority,
+		Version:       h.Spec.Version,
+	}, nil
+}
+
+// generateTemplate permit to decode settings, mappings and aliases
+// Settings are normalized to be compared with the template returned by Opensearch
+func (h TemplateSpec) generateTemplate() (template *opensearch.Template, err error) {
+	if h.Settings == "" && h.Mappings == "" && h.Aliases == "" {
+		return nil, nil
+	}
+
+	template = &opensearch.Template{}
+	for _, field := range []struct {
+		name  string
+		raw   string
+		value *map[string]any
+	}{
+		{name: "settings", raw: h.Settings, value: &template.Settings},
+		{name: "mappings", raw: h.Mappings, value: &template.Mappings},
+		{name: "aliases", raw: h.Aliases, value: &template.Aliases},
+	} {
+		if field.raw == "" {
+			continue
+		}
+		if err = json.Unmarshal([]byte(field.raw), field.value); err != nil {
+			return nil, errors.Wrapf(err, "Error when decode %s", field.name)
+		}
+	}
+	template.Settings = opensearch.NormalizeSettings(template.Settings)
+
+	return template, nil
+}
@@ -0,0 +1,67 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetIndexTemplateName(t *testing.T) {
+	o := &OpensearchIndexTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "logs", o.GetTemplateName())
+
+	// When name is provided
+	o.Spec.Name = "logs-template"
+	assert.Equal(t, "logs-template", o.GetTemplateName())
+}
+
+func TestGenerateIndexTemplate(t *testing.T) {
+	o := &OpensearchIndexTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs",
+		},
+		Spec: OpensearchIndexTemplateSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+		},
+	}
+
+	// When index patterns is missing
+	_, err := o.GenerateIndexTemplate()
+	assert.Error(t, err)
+
+	// Without template
+	o.Spec.IndexPatterns = []string{"logs-*"}
+	template, err := o.GenerateIndexTemplate()
+	assert.NoError(t, err)
+	assert.Nil(t, template.Template)
+
+	// With all options
+	o.Spec.ComposedOf = []string{"logs-mappings"}
+	o.Spec.Priority = pointer.Int64(100)
+	o.Spec.Version = pointer.Int64(2)
+	o.Spec.Settings = `{"index": {"number_of_shards": 1, "number_of_replicas": 1}, "refresh_interval": "5s"}`
+	o.Spec.Mappings = `{"properties": {"message": {"type": "text"}}}`
+	o.Spec.Aliases = `{"logs": {}}`
+	template, err = o.GenerateIndexTemplate()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-indextemplate.yml", template)
+
+	// When JSON is not valid
+	o.Spec.Mappings = `{"properties":`
+	_, err = o.GenerateIndexTemplate()
+	assert.Error(t, err)
+}
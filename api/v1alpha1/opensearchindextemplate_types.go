@@ -0,0 +1,116 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchIndexTemplateSpec defines the desired state of OpensearchIndexTemplate
+// +k8s:openapi-gen=true
+type OpensearchIndexTemplateSpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the index template
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Name is the index template name on Opensearch
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// IndexPatterns is the index patterns where to apply the template
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// ComposedOf is the component templates to use, in merge order
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ComposedOf []string `json:"composedOf,omitempty"`
+
+	// Priority is the template priority when many templates match the same index
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Priority *int64 `json:"priority,omitempty"`
+
+	// Version is the template version
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Version *int64 `json:"version,omitempty"`
+
+	// TemplateSpec is the settings, mappings and aliases of the template
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TemplateSpec `json:",inline"`
+}
+
+type TemplateSpec struct {
+
+	// Settings is the index settings as JSON
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Settings string `json:"settings,omitempty"`
+
+	// Mappings is the index mappings as JSON
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Mappings string `json:"mappings,omitempty"`
+
+	// Aliases is the index aliases as JSON
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Aliases string `json:"aliases,omitempty"`
+}
+
+// OpensearchIndexTemplateStatus defines the observed state of OpensearchIndexTemplate
+type OpensearchIndexTemplateStatus struct {
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchIndexTemplate is the Schema for the opensearchindextemplates API
+// +operator-sdk:csv:customresourcedefinitions:resources={{None,None,None}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchIndexTemplate')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchIndexTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchIndexTemplateSpec   `json:"spec,omitempty"`
+	Status OpensearchIndexTemplateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchIndexTemplateList contains a list of OpensearchIndexTemplate
+type OpensearchIndexTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchIndexTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchIndexTemplate{}, &OpensearchIndexTemplateList{})
+}
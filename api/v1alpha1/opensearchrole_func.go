@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchRole) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchRole) GetStatus() any {
+	return h.Status
+}
+
+// GetRoleName permit to get the role name on Opensearch
+// It use the resource name if not provided
+func (h *OpensearchRole) GetRoleName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.Name
+}
+
+// GenerateRole permit to generate the role of security plugin
+func (h *OpensearchRole) GenerateRole() (role *opensearch.Role, err error) {
+	role = &opensearch.Role{
+		Description:        h.Spec.Description,
+		ClusterPermissions: h.Spec.ClusterPermissions,
+	}
+
+	for _, indexPermission := range h.Spec.IndexPermissions {
+		if len(indexPermission.IndexPatterns) == 0 {
+			return nil, errors.New("IndexPatterns must be provided on index permissions")
+		}
+		role.IndexPermissions = append(role.IndexPermissions, opensearch.IndexPermission{
+			IndexPatterns:  indexPermission.IndexPatterns,
+			DLS:            indexPermission.DLS,
+			FLS:            indexPermission.FLS,
+			MaskedFields:   indexPermission.MaskedFields,
+			AllowedActions: indexPermission.AllowedActions,
+		})
+	}
+
+	for _, tenantPermission := range h.Spec.TenantPermissions {
+		if len(tenantPermission.TenantPatterns) == 0 {
+			return nil, errors.New("TenantPatterns must be provided on tenant permissions")
+		}
+		role.TenantPermissions = append(role.TenantPermissions, opensearch.TenantPermission{
+			TenantPatterns: tenantPermission.TenantPatterns,
+			AllowedActions: tenantPermission.AllowedActions,
+		})
+	}
+
+	return role, nil
+}
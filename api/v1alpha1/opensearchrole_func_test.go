@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetRoleName(t *testing.T) {
+	o := &OpensearchRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs-reader",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "logs-reader", o.GetRoleName())
+
+	// When name is provided
+	o.Spec.Name = "logs_reader"
+	assert.Equal(t, "logs_reader", o.GetRoleName())
+}
+
+func TestGenerateRole(t *testing.T) {
+	o := &OpensearchRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs-reader",
+		},
+		Spec: OpensearchRoleSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Description:        "Read logs",
+			ClusterPermissions: []string{"cluster_composite_ops_ro"},
+			IndexPermissions: []IndexPermissionSpec{
+				{
+					IndexPatterns:  []string{"logs-*"},
+					DLS:            `{"term": {"team": "ops"}}`,
+					FLS:            []string{"~secret"},
+					MaskedFields:   []string{"client.ip"},
+					AllowedActions: []string{"read"},
+				},
+			},
+			TenantPermissions: []TenantPermissionSpec{
+				{
+					TenantPatterns: []string{"ops"},
+					AllowedActions: []string{"kibana_all_read"},
+				},
+			},
+		},
+	}
+
+	role, err := o.GenerateRole()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-role.yml", role)
+
+	// When index patterns is missing
+	o.Spec.IndexPermissions[0].IndexPatterns = nil
+	_, err = o.GenerateRole()
+	assert.Error(t, err)
+}
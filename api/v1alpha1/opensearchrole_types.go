@@ -0,0 +1,133 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchRoleSpec defines the desired state of OpensearchRole
+// +k8s:openapi-gen=true
+type OpensearchRoleSpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the role
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Name is the role name on Opensearch
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description is the role description
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ClusterPermissions is the cluster permissions, like cluster_monitor
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ClusterPermissions []string `json:"clusterPermissions,omitempty"`
+
+	// IndexPermissions is the permissions on index patterns
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IndexPermissions []IndexPermissionSpec `json:"indexPermissions,omitempty"`
+
+	// TenantPermissions is the permissions on tenant patterns
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	TenantPermissions []TenantPermissionSpec `json:"tenantPermissions,omitempty"`
+}
+
+type IndexPermissionSpec struct {
+
+	// IndexPatterns is the index patterns where to apply permissions
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// DLS is the document level security query
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DLS string `json:"dls,omitempty"`
+
+	// FLS is the field level security, prefix field with ~ to exclude it
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	FLS []string `json:"fls,omitempty"`
+
+	// MaskedFields is the fields to mask
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MaskedFields []string `json:"maskedFields,omitempty"`
+
+	// AllowedActions is the allowed actions, like read or indices:data/read/search
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AllowedActions []string `json:"allowedActions,omitempty"`
+}
+
+type TenantPermissionSpec struct {
+
+	// TenantPatterns is the tenant patterns where to apply permissions
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	TenantPatterns []string `json:"tenantPatterns"`
+
+	// AllowedActions is the allowed actions, like kibana_all_read or kibana_all_write
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	AllowedActions []string `json:"allowedActions,omitempty"`
+}
+
+// OpensearchRoleStatus defines the observed state of OpensearchRole
+type OpensearchRoleStatus struct {
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchRole is the Schema for the opensearchroles API
+// +operator-sdk:csv:customresourcedefinitions:resources={{None,None,None}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchRole')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchRoleSpec   `json:"spec,omitempty"`
+	Status OpensearchRoleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchRoleList contains a list of OpensearchRole
+type OpensearchRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchRole{}, &OpensearchRoleList{})
+}
@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchRoleMapping) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchRoleMapping) GetStatus() any {
+	return h.Status
+}
+
+// GetRoleName permit to get the role name to map
+// It use the resource name if not provided
+func (h *OpensearchRoleMapping) GetRoleName() string {
+	if h.Spec.Role != "" {
+		return h.Spec.Role
+	}
+
+	return h.Name
+}
+
+// GenerateRoleMapping permit to generate the role mapping of security plugin
+func (h *OpensearchRoleMapping) GenerateRoleMapping() (roleMapping *opensearch.RoleMapping, err error) {
+	if len(h.Spec.Users) == 0 && len(h.Spec.BackendRoles) == 0 && len(h.Spec.Hosts) == 0 {
+		return nil, errors.New("Users, backendRoles or hosts must be provided")
+	}
+
+	return &opensearch.RoleMapping{
+		Description:  h.Spec.Description,
+		Users:        h.Spec.Users,
+		BackendRoles: h.Spec.BackendRoles,
+		Hosts:        h.Spec.Hosts,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetRoleMappingRoleName(t *testing.T) {
+	o := &OpensearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs-reader",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "logs-reader", o.GetRoleName())
+
+	// When role is provided
+	o.Spec.Role = "readall"
+	assert.Equal(t, "readall", o.GetRoleName())
+}
+
+func TestGenerateRoleMapping(t *testing.T) {
+	o := &OpensearchRoleMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "logs-reader",
+		},
+		Spec: OpensearchRoleMappingSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+		},
+	}
+
+	// When nothing to map
+	_, err := o.GenerateRoleMapping()
+	assert.Error(t, err)
+
+	// When all is right
+	o.Spec.Description = "Ops team"
+	o.Spec.Users = []string{"john"}
+	o.Spec.BackendRoles = []string{"ops"}
+	roleMapping, err := o.GenerateRoleMapping()
+	assert.NoError(t, err)
+	assert.Equal(t, &opensearch.RoleMapping{
+		Description:  "Ops team",
+		Users:        []string{"john"},
+		BackendRoles: []string{"ops"},
+	}, roleMapping)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchRoleMappingSpec defines the desired state of OpensearchRoleMapping
+// +k8s:openapi-gen=true
+type OpensearchRoleMappingSpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the role mapping
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Role is the role name to map
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Role string `json:"role,omitempty"`
+
+	// Description is the role mapping description
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Users is the users that get the role
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// BackendRoles is the backend roles that get the role
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	BackendRoles []string `json:"backendRoles,omitempty"`
+
+	// Hosts is the hosts that get the role
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// OpensearchRoleMappingStatus defines the observed state of OpensearchRoleMapping
+type OpensearchRoleMappingStatus struct {
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchRoleMapping is the Schema for the opensearchrolemappings API
+// +operator-sdk:csv:customresourcedefinitions:resources={{None,None,None}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".spec.role"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchRoleMapping')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchRoleMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchRoleMappingSpec   `json:"spec,omitempty"`
+	Status OpensearchRoleMappingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchRoleMappingList contains a list of OpensearchRoleMapping
+type OpensearchRoleMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchRoleMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchRoleMapping{}, &OpensearchRoleMappingList{})
+}
@@ -0,0 +1,111 @@
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultSnapshotTimezone = "UTC"
+	snapshotDateFormat      = "yyyy.MM.dd-HH.mm"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchSnapshotPolicy) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchSnapshotPolicy) GetStatus() any {
+	return h.Status
+}
+
+// GetPolicyName permit to get the policy name on Opensearch
+// It use the resource name if not provided
+func (h *OpensearchSnapshotPolicy) GetPolicyName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.Name
+}
+
+// GetTimezone permit to get the timezone of schedule
+func (h *OpensearchSnapshotPolicy) GetTimezone() string {
+	if h.Spec.Timezone != "" {
+		return h.Spec.Timezone
+	}
+
+	return defaultSnapshotTimezone
+}
+
+// GenerateSnapshotManagementPolicy permit to generate the snapshot management policy
+// Snapshots are named <policy name>-<date>, so they can be found on repository
+func (h *OpensearchSnapshotPolicy) GenerateSnapshotManagementPolicy() (policy *opensearch.SnapshotManagementPolicy, err error) {
+	if h.Spec.Repository == "" {
+		return nil, errors.New("Repository must be provided")
+	}
+	if h.Spec.Schedule == "" {
+		return nil, errors.New("Schedule must be provided")
+	}
+
+	enabled := true
+	if h.Spec.Enabled != nil {
+		enabled = *h.Spec.Enabled
+	}
+
+	indices := "*"
+	if len(h.Spec.Indices) > 0 {
+		indices = strings.Join(h.Spec.Indices, ",")
+	}
+
+	policy = &opensearch.SnapshotManagementPolicy{
+		Description: h.Spec.Description,
+		Enabled:     &enabled,
+		Creation: opensearch.SnapshotManagementCreation{
+			Schedule: opensearch.SnapshotManagementSchedule{
+				Cron: opensearch.SnapshotManagementCron{
+					Expression: h.Spec.Schedule,
+					Timezone:   h.GetTimezone(),
+				},
+			},
+		},
+		SnapshotConfig: opensearch.SnapshotManagementConfig{
+			Repository:         h.Spec.Repository,
+			Indices:            indices,
+			DateFormat:         snapshotDateFormat,
+			Timezone:           h.GetTimezone(),
+			IgnoreUnavailable:  true,
+			IncludeGlobalState: h.Spec.IncludeGlobalState,
+			Partial:            false,
+		},
+	}
+
+	if h.Spec.Retention != nil {
+		if h.Spec.Retention.MaxCount == nil && h.Spec.Retention.MaxAge == "" {
+			return nil, errors.New("Retention need maxCount or maxAge")
+		}
+		schedule := h.Spec.Retention.Schedule
+		if schedule == "" {
+			schedule = h.Spec.Schedule
+		}
+		policy.Deletion = &opensearch.SnapshotManagementDeletion{
+			Schedule: &opensearch.SnapshotManagementSchedule{
+				Cron: opensearch.SnapshotManagementCron{
+					Expression: schedule,
+					Timezone:   h.GetTimezone(),
+				},
+			},
+			Condition: opensearch.SnapshotManagementDeletionCondition{
+				MaxCount: h.Spec.Retention.MaxCount,
+				MinCount: h.Spec.Retention.MinCount,
+				MaxAge:   h.Spec.Retention.MaxAge,
+			},
+		}
+	}
+
+	return policy, nil
+}
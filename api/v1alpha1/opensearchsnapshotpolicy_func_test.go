@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestGetPolicyName(t *testing.T) {
+	o := &OpensearchSnapshotPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "daily",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "daily", o.GetPolicyName())
+
+	// When name is provided
+	o.Spec.Name = "daily-snapshot"
+	assert.Equal(t, "daily-snapshot", o.GetPolicyName())
+}
+
+func TestGenerateSnapshotManagementPolicy(t *testing.T) {
+	o := &OpensearchSnapshotPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "daily",
+		},
+		Spec: OpensearchSnapshotPolicySpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Repository: "backup",
+			Schedule:   "0 1 * * *",
+		},
+	}
+
+	// With default values
+	policy, err := o.GenerateSnapshotManagementPolicy()
+	assert.NoError(t, err)
+	assert.True(t, *policy.Enabled)
+	assert.Equal(t, "*", policy.SnapshotConfig.Indices)
+	assert.Equal(t, "UTC", policy.Creation.Schedule.Cron.Timezone)
+	assert.Nil(t, policy.Deletion)
+
+	// With all options
+	o.Spec.Description = "Daily snapshot"
+	o.Spec.Timezone = "Europe/Paris"
+	o.Spec.Indices = []string{"logs-*", "metrics-*"}
+	o.Spec.Retention = &SnapshotRetentionSpec{
+		MaxAge:   "7d",
+		MinCount: pointer.Int64(1),
+		Schedule: "0 2 * * *",
+	}
+	policy, err = o.GenerateSnapshotManagementPolicy()
+	assert.NoError(t, err)
+	test.EqualFromYamlFile(t, "../../fixture/api/os-snapshotpolicy.yml", policy)
+
+	// When retention have not max count or max age
+	o.Spec.Retention.MaxAge = ""
+	_, err = o.GenerateSnapshotManagementPolicy()
+	assert.Error(t, err)
+
+	// When repository is missing
+	o.Spec.Retention = nil
+	o.Spec.Repository = ""
+	_, err = o.GenerateSnapshotManagementPolicy()
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchSnapshotPolicySpec defines the desired state of OpensearchSnapshotPolicy
+// +k8s:openapi-gen=true
+type OpensearchSnapshotPolicySpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the snapshot policy
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Name is the policy name on Opensearch
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description is the policy description
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Enabled permit to enable or disable the policy
+	// Default to true
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Repository is the snapshot repository name where to store snapshots
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Repository string `json:"repository"`
+
+	// Schedule is the cron expression used to create snapshots
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Schedule string `json:"schedule"`
+
+	// Timezone is the timezone of schedule
+	// Default to UTC
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
+	// Indices is the index patterns to snapshot
+	// Default to all indices
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// IncludeGlobalState permit to include cluster state on snapshots
+	// Default to false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	IncludeGlobalState bool `json:"includeGlobalState,omitempty"`
+
+	// Retention permit to delete old snapshots
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Retention *SnapshotRetentionSpec `json:"retention,omitempty"`
+}
+
+type SnapshotRetentionSpec struct {
+
+	// MaxCount is the maximum number of snapshots to keep
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MaxCount *int64 `json:"maxCount,omitempty"`
+
+	// MinCount is the minimum number of snapshots to keep, even if they are older than max age
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MinCount *int64 `json:"minCount,omitempty"`
+
+	// MaxAge is the maximum age of snapshots to keep, like 7d
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	MaxAge string `json:"maxAge,omitempty"`
+
+	// Schedule is the cron expression used to delete snapshots
+	// Default to the creation schedule
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// OpensearchSnapshotPolicyStatus defines the observed state of OpensearchSnapshotPolicy
+type OpensearchSnapshotPolicyStatus struct {
+
+	// LastSuccessfulSnapshot is the name of last successful snapshot
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastSuccessfulSnapshot string `json:"lastSuccessfulSnapshot,omitempty"`
+
+	// LastSuccessfulTime is the end time of last successful snapshot
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// LastFailure is the error of last failed execution
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
+
+	// LastFailureTime is the time of last failed execution
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// NextRun is the time of next snapshot
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	NextRun *metav1.Time `json:"nextRun,omitempty"`
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchSnapshotPolicy is the Schema for the opensearchsnapshotpolicies API
+// +operator-sdk:csv:customresourcedefinitions:resources={{None,None,None}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Repository",type="string",JSONPath=".spec.repository"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Last snapshot",type="string",JSONPath=".status.lastSuccessfulSnapshot"
+// +kubebuilder:printcolumn:name="Next run",type="date",JSONPath=".status.nextRun"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchSnapshotPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchSnapshotPolicySpec   `json:"spec,omitempty"`
+	Status OpensearchSnapshotPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchSnapshotPolicyList contains a list of OpensearchSnapshotPolicy
+type OpensearchSnapshotPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchSnapshotPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchSnapshotPolicy{}, &OpensearchSnapshotPolicyList{})
+}
@@ -0,0 +1,95 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultUserPasswordKey = "password"
+)
+
+// GetObjectMeta permit to get the object meta
+func (h *OpensearchUser) GetObjectMeta() metav1.ObjectMeta {
+	return h.ObjectMeta
+}
+
+// GetStatus permit to get the status
+func (h *OpensearchUser) GetStatus() any {
+	return h.Status
+}
+
+// GetUsername permit to get the user name on Opensearch
+// It use the resource name if not provided
+func (h *OpensearchUser) GetUsername() string {
+	if h.Spec.Username != "" {
+		return h.Spec.Username
+	}
+
+	return h.Name
+}
+
+// IsGeneratedPassword return true if the password is generated by operator
+func (h *OpensearchUser) IsGeneratedPassword() bool {
+	return h.Spec.PasswordSecretRef == nil
+}
+
+// GetSecretNameForPassword permit to get the secret name that store the user password
+// It use the generated secret if secret is not provided
+func (h *OpensearchUser) GetSecretNameForPassword() string {
+	if h.Spec.PasswordSecretRef != nil {
+		return h.Spec.PasswordSecretRef.Name
+	}
+
+	return fmt.Sprintf("%s-os-user", h.Name)
+}
+
+// GetSecretKeyForPassword permit to get the secret key that store the user password
+func (h *OpensearchUser) GetSecretKeyForPassword() string {
+	if h.Spec.PasswordSecretRef != nil && h.Spec.PasswordSecretRef.Key != "" {
+		return h.Spec.PasswordSecretRef.Key
+	}
+
+	return defaultUserPasswordKey
+}
+
+// GeneratePasswordSecret permit to generate the secret that store the generated password
+// It store the username too, so application can use it as credentials
+func (h *OpensearchUser) GeneratePasswordSecret(password string) (secret *corev1.Secret) {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: h.Namespace,
+			Name:      h.GetSecretNameForPassword(),
+			Labels:    h.Labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"username":             []byte(h.GetUsername()),
+			defaultUserPasswordKey: []byte(password),
+		},
+	}
+}
+
+// GenerateInternalUser permit to generate the internal user
+// The password is read from secret
+func (h *OpensearchUser) GenerateInternalUser(secret *corev1.Secret) (user *opensearch.InternalUser, err error) {
+	if secret == nil {
+		return nil, errors.Errorf("Secret %s must be provided", h.GetSecretNameForPassword())
+	}
+
+	password := string(secret.Data[h.GetSecretKeyForPassword()])
+	if password == "" {
+		return nil, errors.Errorf("Key %s must be provided on secret %s", h.GetSecretKeyForPassword(), secret.Name)
+	}
+
+	return &opensearch.InternalUser{
+		Password:     password,
+		Description:  h.Spec.Description,
+		BackendRoles: h.Spec.BackendRoles,
+		Attributes:   h.Spec.Attributes,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetUsername(t *testing.T) {
+	o := &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "john",
+		},
+	}
+
+	// With default value
+	assert.Equal(t, "john", o.GetUsername())
+
+	// When username is provided
+	o.Spec.Username = "john.doe"
+	assert.Equal(t, "john.doe", o.GetUsername())
+}
+
+func TestGetSecretForPassword(t *testing.T) {
+	o := &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "john",
+		},
+	}
+
+	// When password is generated
+	assert.True(t, o.IsGeneratedPassword())
+	assert.Equal(t, "john-os-user", o.GetSecretNameForPassword())
+	assert.Equal(t, "password", o.GetSecretKeyForPassword())
+
+	// When secret is provided
+	o.Spec.PasswordSecretRef = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "john-credentials",
+		},
+		Key: "pass",
+	}
+	assert.False(t, o.IsGeneratedPassword())
+	assert.Equal(t, "john-credentials", o.GetSecretNameForPassword())
+	assert.Equal(t, "pass", o.GetSecretKeyForPassword())
+}
+
+func TestGeneratePasswordSecret(t *testing.T) {
+	o := &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "john",
+		},
+		Spec: OpensearchUserSpec{
+			Username: "john.doe",
+		},
+	}
+
+	secret := o.GeneratePasswordSecret("secret")
+	assert.Equal(t, "john-os-user", secret.Name)
+	assert.Equal(t, "default", secret.Namespace)
+	assert.Equal(t, map[string][]byte{
+		"username": []byte("john.doe"),
+		"password": []byte("secret"),
+	}, secret.Data)
+}
+
+func TestGenerateInternalUser(t *testing.T) {
+	o := &OpensearchUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "john",
+		},
+		Spec: OpensearchUserSpec{
+			OpensearchRef: shared.OpensearchRef{
+				Name: "test",
+			},
+			Description:  "John Doe",
+			BackendRoles: []string{"admin"},
+			Attributes: map[string]string{
+				"team": "ops",
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "john-os-user",
+		},
+		Data: map[string][]byte{
+			"password": []byte("secret"),
+		},
+	}
+
+	user, err := o.GenerateInternalUser(secret)
+	assert.NoError(t, err)
+	assert.Equal(t, &opensearch.InternalUser{
+		Password:     "secret",
+		Description:  "John Doe",
+		BackendRoles: []string{"admin"},
+		Attributes: map[string]string{
+			"team": "ops",
+		},
+	}, user)
+
+	// When secret is missing
+	_, err = o.GenerateInternalUser(nil)
+	assert.Error(t, err)
+
+	// When password key is missing
+	secret.Data = map[string][]byte{}
+	_, err = o.GenerateInternalUser(secret)
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpensearchUserSpec defines the desired state of OpensearchUser
+// +k8s:openapi-gen=true
+type OpensearchUserSpec struct {
+
+	// OpensearchRef is the Opensearch cluster where to create the user
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	OpensearchRef shared.OpensearchRef `json:"opensearchRef"`
+
+	// Username is the user name on Opensearch
+	// Default to the resource name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// PasswordSecretRef is the secret key that store the user password
+	// When not provided, a random password is generated on secret <name>-os-user
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// Description is the user description
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// BackendRoles is the backend roles of the user
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	BackendRoles []string `json:"backendRoles,omitempty"`
+
+	// Attributes is the custom attributes of the user
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// OpensearchUserStatus defines the observed state of OpensearchUser
+type OpensearchUserStatus struct {
+
+	// PasswordSecretVersion is the resource version of password secret applied on Opensearch
+	// It permit to update the password when the secret change
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+
+	// List of conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// OpensearchUser is the Schema for the opensearchusers API
+// +operator-sdk:csv:customresourcedefinitions:resources={{Secret,v1,""}}
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.opensearchRef.name"
+// +kubebuilder:printcolumn:name="Username",type="string",JSONPath=".spec.username"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='OpensearchUser')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type OpensearchUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpensearchUserSpec   `json:"spec,omitempty"`
+	Status OpensearchUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpensearchUserList contains a list of OpensearchUser
+type OpensearchUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpensearchUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpensearchUser{}, &OpensearchUserList{})
+}
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/disaster37/operator-sdk-extra/pkg/controller"
@@ -15,6 +17,7 @@ import (
 const (
 	opensearchAnnotationKey = "opensearch.k8s.webcenter.fr"
 	requeuedDuration = time.Minute * 1
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 )
 
 type Reconciler struct {
@@ -65,3 +68,18 @@ func (r *Reconciler) SetReconsiler(reconciler controller.Reconciler) {
 func GetNodeName(clusterName, groupName string, index int) string {
 	return fmt.Sprintf("%s-%s-%d", clusterName, groupName, index)
 }
+
+// getOperatorNamespace permit to get the namespace where the operator run
+// It read POD_NAMESPACE, or the namespace of service account when it run on cluster
+func getOperatorNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+
+	namespace, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(namespace))
+}
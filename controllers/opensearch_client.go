@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/webcenter-fr/opensearch-operator/api/shared"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newOpensearchClient permit to get client to call Opensearch API of cluster
+// It use the admin credentials and the CA of API certificate, or plain HTTP without credentials when security plugin is disabled
+func newOpensearchClient(ctx context.Context, c client.Client, o *opensearchapi.Opensearch) (osClient *opensearch.Client, err error) {
+	if o.Spec.DisableSecurityPlugin {
+		osClient, err = opensearch.NewClient(opensearch.Config{
+			URL: o.GetApiUrl(),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "Error when create Opensearch client")
+		}
+
+		return osClient, nil
+	}
+
+	// Read admin credentials
+	credentials := &corev1.Secret{}
+	if err = c.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: o.GetSecretNameForAdminCredentials()}, credentials); err != nil {
+		return nil, errors.Wrapf(err, "Error when read secret %s", o.GetSecretNameForAdminCredentials())
+	}
+
+	// Read CA of API certificate
+	tlsApi := &corev1.Secret{}
+	if err = c.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: o.GetSecretNameForTlsApi()}, tlsApi); err != nil {
+		return nil, errors.Wrapf(err, "Error when read secret %s", o.GetSecretNameForTlsApi())
+	}
+
+	osClient, err = opensearch.NewClient(opensearch.Config{
+		URL:      o.GetApiUrl(),
+		Username: string(credentials.Data["username"]),
+		Password: string(credentials.Data["password"]),
+		CACert:   tlsApi.Data["ca.crt"],
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error when create Opensearch client")
+	}
+
+	return osClient, nil
+}
+
+// opensearchMeta is the meta shared by reconcilers of resources created on Opensearch cluster
+type opensearchMeta struct {
+	opensearch *opensearchapi.Opensearch
+	client     *opensearch.Client
+}
+
+// getOpensearchMeta permit to read the referenced Opensearch cluster and get client to call its API
+// It return nil when the cluster not exist and the resource is being deleted, so the finalizer can be removed
+func getOpensearchMeta(ctx context.Context, c client.Client, namespace string, ref shared.OpensearchRef, isDeleting bool) (meta *opensearchMeta, err error) {
+	if ref.Name == "" {
+		return nil, errors.New("OpensearchRef must be provided")
+	}
+
+	o := &opensearchapi.Opensearch{}
+	if err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, o); err != nil {
+		if k8serrors.IsNotFound(err) && isDeleting {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when read Opensearch %s", ref.Name)
+	}
+
+	osClient, err := newOpensearchClient(ctx, c, o)
+	if err != nil {
+		if isDeleting && k8serrors.IsNotFound(errors.Cause(err)) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &opensearchMeta{
+		opensearch: o,
+		client:     osClient,
+	}, nil
+}
@@ -103,7 +103,20 @@ func (r *OpensearchApiTlsReconciler) Read(ctx context.Context, resource client.O
 		}
 	}
 
+	// Read load balancer to get the observed addresses
+	var lb *corev1.Service
+	if opensearch.IsLoadBalancerEnabled() {
+		lb = &corev1.Service{}
+		if err = r.Client.Get(ctx, types.NamespacedName{Namespace: opensearch.Namespace, Name: opensearch.GetLoadBalancerServiceName()}, lb); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return res, errors.Wrapf(err, "Error when read existing load balancer %s", opensearch.GetLoadBalancerServiceName())
+			}
+			lb = nil
+		}
+	}
+
 	data["currentSecret"] = s
+	data["loadBalancer"] = lb
 
 	return res, nil
 }
@@ -166,6 +179,12 @@ func (r *OpensearchApiTlsReconciler) Diff(resource client.Object, data map[strin
 	currentSecret := d.(*corev1.Secret)
 
 
+	d, err = helper.Get(data, "loadBalancer")
+	if err != nil {
+		return diff, err
+	}
+	loadBalancer := d.(*corev1.Service)
+
 	diff = controller.Diff{
 		NeedCreate: false,
 		NeedUpdate: false,
@@ -174,25 +193,18 @@ func (r *OpensearchApiTlsReconciler) Diff(resource client.Object, data map[strin
 	// Do somethink only if operator manage API certificate
 	if opensearch.IsSelfManagedSecretForTlsApi() {
 
-		// Load pki and current certificate
-		d, err = helper.Get(data, "rootCA")
+		// Compute expected alt names
+		altNames, altIPs, err := opensearch.GetApiCertificateAltNames(loadBalancer)
 		if err != nil {
-			return diff, err
+			return diff, errors.Wrap(err, "Error when compute alt names for Api certificate")
 		}
-		rootCA = d.(*goca.CA)
-
-		d, err = helper.Get(data, "apiCertificate")
-		if err != nil {
-			return diff, err
-		}
-		apiCrt = d.(*x509.Certificate)
 
 		// Create new secret if not yet exist
 		if currentSecret == nil {
 			diff.NeedCreate = true
 			diff.Diff = "Secret not exist"
 	
-			expectedSecret, err := r.generateSecret(opensearch)
+			expectedSecret, err := r.generateSecret(opensearch, altNames, altIPs)
 			if err != nil {
 				return diff, errors.Wrapf(err, "Error when generate secret %s for TLS Api", opensearch.GetSecretNameForTlsApi())
 			}
@@ -203,6 +215,19 @@ func (r *OpensearchApiTlsReconciler) Diff(resource client.Object, data map[strin
 			return diff, nil
 		}
 
+		// Load pki and current certificate
+		d, err = helper.Get(data, "rootCA")
+		if err != nil {
+			return diff, err
+		}
+		rootCA = d.(*goca.CA)
+
+		d, err = helper.Get(data, "apiCertificate")
+		if err != nil {
+			return diff, err
+		}
+		apiCrt = d.(*x509.Certificate)
+
 		// Check if CA need to be renewed or Api certificate
 		needRenewCA, err := pki.NeedRenewCertificate(rootCA.GoCertificate(), r.log)
 		if err != nil {
@@ -213,7 +238,7 @@ func (r *OpensearchApiTlsReconciler) Diff(resource client.Object, data map[strin
 			return diff, errors.Wrap(err, "Error when check if Api certificate need to be renewed")
 		}
 		if needRenewCA || needRenewApiCertificate {
-			expectedSecret, err := r.generateSecret(opensearch)
+			expectedSecret, err := r.generateSecret(opensearch, altNames, altIPs)
 			if err != nil {
 				return diff, errors.Wrapf(err, "Error when generate secret %s for TLS Api", opensearch.GetSecretNameForTlsApi())
 			}
@@ -225,6 +250,24 @@ func (r *OpensearchApiTlsReconciler) Diff(resource client.Object, data map[strin
 
 			return diff, nil
 		}
+
+		// Check if alt names changed. In this case, we only reissue Api certificate with the current CA
+		needReissueApiCertificate, err := pki.NeedReissueApiTls(apiCrt, opensearch.Name, altNames, altIPs, r.log)
+		if err != nil {
+			return diff, errors.Wrap(err, "Error when check if Api certificate need to be reissued")
+		}
+		if needReissueApiCertificate {
+			if err = r.issueApiCertificate(opensearch, currentSecret, rootCA, altNames, altIPs); err != nil {
+				return diff, errors.Wrapf(err, "Error when reissue Api certificate on secret %s", opensearch.GetSecretNameForTlsApi())
+			}
+			data["expectedSecret"] = currentSecret
+			diff.NeedUpdate = true
+			diff.Diff = fmt.Sprintf("Alt names changed. Reissue Api certificate with alt names %v and alt IPs %v", altNames, altIPs)
+
+			r.log.Info("Reissue Api certificate")
+
+			return diff, nil
+		}
 	}
 
 	return diff, nil
@@ -276,13 +319,14 @@ func (r *OpensearchApiTlsReconciler) OnSuccess(ctx context.Context, resource cli
 
 
 // generateSecret generate the secret with all certificate needed by Api layout
-func (r *OpensearchApiTlsReconciler) generateSecret(opensearch *opensearchapi.Opensearch) (secret *corev1.Secret, err error) {
+func (r *OpensearchApiTlsReconciler) generateSecret(opensearch *opensearchapi.Opensearch, altNames, altIPs []string) (secret *corev1.Secret, err error) {
 	secret = &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      opensearch.GetSecretNameForTlsApi(),
 			Namespace: opensearch.Namespace,
 		},
 		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
 	}
 
 	// Set owner
@@ -302,24 +346,27 @@ func (r *OpensearchApiTlsReconciler) generateSecret(opensearch *opensearchapi.Op
 	secret.Data["ca.crl"] = []byte(rootCA.GetCRL())
 
 	// Genereate Api cert
-	var altnames []string
-	var altips []string
-	if opensearch.IsLoadBalancerEnabled() && opensearch.Spec.Endpoint.LoadBalancer.Tls != nil && opensearch.Spec.Endpoint.LoadBalancer.Tls.SelfSignedCertificate != nil  {
-		altnames = opensearch.Spec.Endpoint.LoadBalancer.Tls.SelfSignedCertificate.AltNames
-		altips = opensearch.Spec.Endpoint.LoadBalancer.Tls.SelfSignedCertificate.AltIps
+	if err = r.issueApiCertificate(opensearch, secret, rootCA, altNames, altIPs); err != nil {
+		return nil, err
 	}
-	apiCrt, err := pki.NewApiTls(opensearch.Name, altnames, altips, rootCA, r.log)
+
+	return secret, nil
+}
+
+// issueApiCertificate generate the Api certificate from the provided CA and store it on secret
+func (r *OpensearchApiTlsReconciler) issueApiCertificate(opensearch *opensearchapi.Opensearch, secret *corev1.Secret, rootCA *goca.CA, altNames, altIPs []string) (err error) {
+	apiCrt, err := pki.NewApiTls(opensearch.Name, altNames, altIPs, rootCA, r.log)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error when generate Api certificate")
+		return errors.Wrap(err, "Error when generate Api certificate")
 	}
 	secret.Data["api.crt"] = []byte(apiCrt.Certificate)
 	secret.Data["api.key"] = []byte(apiCrt.PrivateKey)
 	secret.Data["api.csr"] = []byte(apiCrt.CSR)
 	pkcs12, err := goca.GeneratePkcs12(apiCrt, "")
 	if err != nil {
-		return nil, errors.Wrap(err, "Error when generate Pkcs12 for Api")
+		return errors.Wrap(err, "Error when generate Pkcs12 for Api")
 	}
 	secret.Data["api.pfx"] = pkcs12
 
-	return secret, nil
+	return nil
 }
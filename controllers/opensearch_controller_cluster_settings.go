@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchClusterSettingsCondition = "OpensearchClusterSettings"
+	OpensearchClusterSettingsPhase     = "Apply cluster settings"
+)
+
+type OpensearchClusterSettingsReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchClusterSettingsReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if isClusterSettingsManaged(o) && condition.FindStatusCondition(o.Status.Conditions, OpensearchClusterSettingsCondition) == nil {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:   OpensearchClusterSettingsCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read the current persistent cluster settings
+// The settings are only read when the cluster is healthy, so they are not applied during cluster creation or node failure
+func (r *OpensearchClusterSettingsReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	data["isHealthy"] = false
+
+	if !isClusterSettingsManaged(o) {
+		return res, nil
+	}
+
+	osClient, err := newOpensearchClient(ctx, r.Client, o)
+	if err != nil {
+		return res, err
+	}
+	data["client"] = osClient
+
+	health, err := osClient.GetClusterHealth(ctx)
+	if err != nil {
+		return res, err
+	}
+	if health.Status == clusterHealthRed {
+		r.log.Infof("Wait cluster is healthy to apply cluster settings, current status is %s", health.Status)
+		return res, nil
+	}
+	data["isHealthy"] = true
+
+	currentSettings, err := osClient.GetClusterSettings(ctx)
+	if err != nil {
+		return res, err
+	}
+	data["currentSettings"] = currentSettings.Persistent
+
+	return res, nil
+}
+
+// Create do nothing, cluster settings are always updated
+func (r *OpensearchClusterSettingsReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return res, nil
+}
+
+// Update permit to apply the cluster settings and to record the applied revision
+func (r *OpensearchClusterSettingsReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+	var d any
+
+	d, err = helper.Get(data, "client")
+	if err != nil {
+		return res, err
+	}
+	osClient := d.(*opensearch.Client)
+
+	d, err = helper.Get(data, "settingsToApply")
+	if err != nil {
+		return res, err
+	}
+	settingsToApply := d.(map[string]any)
+
+	if err = osClient.PutClusterSettings(ctx, settingsToApply); err != nil {
+		return res, err
+	}
+
+	now := metav1.Now()
+	if o.Status.ClusterSettings == nil {
+		o.Status.ClusterSettings = &opensearchapi.ClusterSettingsStatus{}
+	}
+	o.Status.ClusterSettings.Revision++
+	o.Status.ClusterSettings.Keys = getClusterSettingsKeys(o)
+	o.Status.ClusterSettings.LastAppliedTime = &now
+
+	return res, nil
+}
+
+// Delete do nothing
+func (r *OpensearchClusterSettingsReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compute the cluster settings to apply
+// Only the settings declared on spec or previously managed are compared
+func (r *OpensearchClusterSettingsReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+	var d any
+
+	d, err = helper.Get(data, "isHealthy")
+	if err != nil {
+		return diff, err
+	}
+	if !d.(bool) {
+		return diff, nil
+	}
+
+	d, err = helper.Get(data, "currentSettings")
+	if err != nil {
+		return diff, err
+	}
+	currentSettings := d.(map[string]any)
+
+	settingsToApply := o.ComputeClusterSettingsToApply(currentSettings)
+	data["settingsToApply"] = settingsToApply
+
+	if len(settingsToApply) > 0 {
+		currentManagedSettings := map[string]any{}
+		for key := range settingsToApply {
+			currentManagedSettings[key] = currentSettings[key]
+		}
+		diff.NeedUpdate = true
+		diff.Diff = localhelper.Diff(settingsToApply, currentManagedSettings)
+	}
+
+	// The managed keys change without need to apply settings, like when removed setting is already reset
+	if !diff.NeedUpdate && (o.Status.ClusterSettings == nil || localhelper.Diff(getClusterSettingsKeys(o), o.Status.ClusterSettings.Keys) != "") {
+		diff.NeedUpdate = true
+		diff.Diff = "Managed cluster settings keys change"
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchClusterSettingsReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+		Type:    OpensearchClusterSettingsCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchClusterSettingsReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if !isClusterSettingsManaged(o) {
+		condition.RemoveStatusCondition(&o.Status.Conditions, OpensearchClusterSettingsCondition)
+		return nil
+	}
+
+	if diff.NeedUpdate {
+		r.recorder.Event(resource, corev1.EventTypeNormal, "ClusterSettings", "Cluster settings successfully applied:\n"+diff.Diff)
+	}
+
+	d, err := helper.Get(data, "isHealthy")
+	if err != nil {
+		return err
+	}
+	if !d.(bool) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchClusterSettingsCondition,
+			Reason:  "WaitHealthy",
+			Status:  metav1.ConditionFalse,
+			Message: "Wait cluster is healthy to apply cluster settings",
+		})
+		return nil
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(o.Status.Conditions, OpensearchClusterSettingsCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchClusterSettingsCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Cluster settings up to date",
+		})
+	}
+
+	return nil
+}
+
+// isClusterSettingsManaged return true if cluster settings are declared or need to be reset
+func isClusterSettingsManaged(o *opensearchapi.Opensearch) bool {
+	return len(o.Spec.ClusterSettings) > 0 || (o.Status.ClusterSettings != nil && len(o.Status.ClusterSettings.Keys) > 0)
+}
+
+// getClusterSettingsKeys return the sorted keys of cluster settings declared on spec
+func getClusterSettingsKeys(o *opensearchapi.Opensearch) []string {
+	keys := make([]string, 0, len(o.Spec.ClusterSettings))
+	for key := range o.Spec.ClusterSettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
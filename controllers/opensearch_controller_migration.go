@@ -0,0 +1,413 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchMigrationCondition = "OpensearchMigration"
+	OpensearchMigrationPhase     = "Migrate node groups"
+	allocationExcludeSetting     = "cluster.routing.allocation.exclude._name"
+)
+
+type OpensearchMigrationReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// nodeGroupMigration is the action to do on node group migration
+type nodeGroupMigration struct {
+	oldNodeGroup *opensearchapi.NodeGroupSpec
+	successor    string
+	nodeNames    []string
+	phase        string
+}
+
+// Configure permit to init condition
+func (r *OpensearchMigrationReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if len(opensearch.GetSuccessorNodeGroups()) > 0 && condition.FindStatusCondition(opensearch.Status.Conditions, OpensearchMigrationCondition) == nil {
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:   OpensearchMigrationCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read existing statefullsets of migrated node groups and the remaining shards on old nodes
+func (r *OpensearchMigrationReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+	currentStatefullsets := map[string]*appv1.StatefulSet{}
+	remainingShards := map[string]int{}
+
+	for _, nodeGroup := range opensearch.GetSuccessorNodeGroups() {
+		for _, nodeGroupName := range []string{nodeGroup.Name, nodeGroup.SuccessorOf} {
+			name := opensearch.GetNodeGroupName(nodeGroupName)
+			sts := &appv1.StatefulSet{}
+			if err = r.Client.Get(ctx, types.NamespacedName{Namespace: opensearch.Namespace, Name: name}, sts); err != nil {
+				if !k8serrors.IsNotFound(err) {
+					return res, errors.Wrapf(err, "Error when read statefullset %s", name)
+				}
+				sts = nil
+			}
+			currentStatefullsets[nodeGroupName] = sts
+		}
+
+		// Count the shards that are not yet relocated from old nodes
+		migration := opensearch.GetNodeGroupMigration(nodeGroup.SuccessorOf)
+		oldSts := currentStatefullsets[nodeGroup.SuccessorOf]
+		if migration != nil && migration.Phase == opensearchapi.NodeGroupMigrationRelocating && oldSts != nil {
+			osClient, err := newOpensearchClient(ctx, r.Client, opensearch)
+			if err != nil {
+				return res, err
+			}
+			count, err := osClient.CountShardsOnNodes(ctx, getStatefullsetNodeNames(oldSts))
+			if err != nil {
+				return res, errors.Wrapf(err, "Error when count shards on node group %s", nodeGroup.SuccessorOf)
+			}
+			remainingShards[nodeGroup.SuccessorOf] = count
+		}
+	}
+
+	data["currentStatefullsets"] = currentStatefullsets
+	data["remainingShards"] = remainingShards
+
+	return res, nil
+}
+
+// Create start node groups migrations
+func (r *OpensearchMigrationReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return r.Update(ctx, resource, data, meta)
+}
+
+// Update permit to relocate shards from old node groups, and to remove them when they are empty
+func (r *OpensearchMigrationReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	d, err := helper.Get(data, "migrations")
+	if err != nil {
+		return res, err
+	}
+	migrations := d.([]nodeGroupMigration)
+
+	nodesToExclude := make([]string, 0)
+	nodesToRelease := make([]string, 0)
+	isCompleted := false
+
+	for _, migration := range migrations {
+		switch migration.phase {
+		case opensearchapi.NodeGroupMigrationPending:
+			// Nothing to do on cluster, wait successor
+		case opensearchapi.NodeGroupMigrationRelocating:
+			nodesToExclude = append(nodesToExclude, migration.nodeNames...)
+			if opensearch.IsMasterRole(migration.oldNodeGroup) {
+				osClient, err := newOpensearchClient(ctx, r.Client, opensearch)
+				if err != nil {
+					return res, err
+				}
+				if err = osClient.AddVotingConfigExclusions(ctx, migration.nodeNames); err != nil {
+					return res, errors.Wrapf(err, "Error when exclude master nodes of node group %s", migration.oldNodeGroup.Name)
+				}
+			}
+		case opensearchapi.NodeGroupMigrationCompleted:
+			if err = r.deleteNodeGroup(ctx, opensearch, migration.oldNodeGroup.Name); err != nil {
+				return res, err
+			}
+			nodesToRelease = append(nodesToRelease, migration.nodeNames...)
+			isCompleted = true
+		default:
+			return res, errors.Errorf("Unknown migration phase %s", migration.phase)
+		}
+
+		setNodeGroupMigration(opensearch, opensearchapi.NodeGroupMigrationStatus{
+			NodeGroup: migration.oldNodeGroup.Name,
+			Successor: migration.successor,
+			Phase:     migration.phase,
+		})
+	}
+
+	// The allocation exclusion is shared by all migrations, and can also be set by user
+	if len(nodesToExclude) > 0 || len(nodesToRelease) > 0 {
+		osClient, err := newOpensearchClient(ctx, r.Client, opensearch)
+		if err != nil {
+			return res, err
+		}
+		currentSettings, err := osClient.GetClusterSettings(ctx)
+		if err != nil {
+			return res, err
+		}
+		current := currentSettings.Persistent[allocationExcludeSetting]
+		expected := opensearchapi.ComputeAllocationExclusion(current, nodesToExclude, nodesToRelease)
+		if current != expected {
+			if err = osClient.PutClusterSettings(ctx, map[string]any{allocationExcludeSetting: expected}); err != nil {
+				return res, errors.Wrap(err, "Error when update nodes exclusion")
+			}
+		}
+	}
+
+	// Voting exclusions can only be cleared for whole cluster, so wait all migrations are completed
+	if isCompleted && !isNodeGroupMigrationInProgress(opensearch) {
+		osClient, err := newOpensearchClient(ctx, r.Client, opensearch)
+		if err != nil {
+			return res, err
+		}
+		if err = osClient.ClearVotingConfigExclusions(ctx); err != nil {
+			return res, errors.Wrap(err, "Error when clear master nodes exclusion")
+		}
+	}
+
+	// Shards relocation can be long, so check it later
+	res.RequeueAfter = requeuedDuration
+
+	return res, nil
+}
+
+// Delete do nothing
+// We add parent link, so k8s auto delete children
+func (r *OpensearchMigrationReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compute the next phase of node groups migrations
+func (r *OpensearchMigrationReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+	var d any
+	var sb strings.Builder
+
+	d, err = helper.Get(data, "currentStatefullsets")
+	if err != nil {
+		return diff, err
+	}
+	currentStatefullsets := d.(map[string]*appv1.StatefulSet)
+
+	d, err = helper.Get(data, "remainingShards")
+	if err != nil {
+		return diff, err
+	}
+	remainingShards := d.(map[string]int)
+
+	migrations := make([]nodeGroupMigration, 0)
+
+	for _, nodeGroup := range opensearch.GetSuccessorNodeGroups() {
+		migration := opensearch.GetNodeGroupMigration(nodeGroup.SuccessorOf)
+		if migration != nil && migration.Phase == opensearchapi.NodeGroupMigrationCompleted {
+			continue
+		}
+
+		// The old node group must be kept on spec until the migration is completed
+		var oldNodeGroup *opensearchapi.NodeGroupSpec
+		for i, ng := range opensearch.Spec.NodeGroups {
+			if ng.Name == nodeGroup.SuccessorOf {
+				oldNodeGroup = &opensearch.Spec.NodeGroups[i]
+				break
+			}
+		}
+		if oldNodeGroup == nil {
+			return diff, errors.Errorf("Node group %s must be kept until it's migrated on node group %s", nodeGroup.SuccessorOf, nodeGroup.Name)
+		}
+
+		oldSts := currentStatefullsets[oldNodeGroup.Name]
+		successorSts := currentStatefullsets[nodeGroup.Name]
+		next := nodeGroupMigration{
+			oldNodeGroup: oldNodeGroup,
+			successor:    nodeGroup.Name,
+		}
+
+		switch {
+		case migration == nil:
+			next.phase = opensearchapi.NodeGroupMigrationPending
+			diff.NeedCreate = true
+			sb.WriteString(fmt.Sprintf("Start migration of node group %s on %s\n", oldNodeGroup.Name, nodeGroup.Name))
+		case migration.Phase == opensearchapi.NodeGroupMigrationPending:
+			if oldSts == nil {
+				// Nothing to relocate
+				next.phase = opensearchapi.NodeGroupMigrationCompleted
+			} else if isStatefullsetReady(successorSts) {
+				next.phase = opensearchapi.NodeGroupMigrationRelocating
+				next.nodeNames = getStatefullsetNodeNames(oldSts)
+			} else {
+				continue
+			}
+			diff.NeedUpdate = true
+			sb.WriteString(fmt.Sprintf("Node group %s is now on phase %s\n", oldNodeGroup.Name, next.phase))
+		case migration.Phase == opensearchapi.NodeGroupMigrationRelocating:
+			if remainingShards[oldNodeGroup.Name] > 0 {
+				r.log.Infof("Wait relocation of %d shards from node group %s", remainingShards[oldNodeGroup.Name], oldNodeGroup.Name)
+				continue
+			}
+			next.phase = opensearchapi.NodeGroupMigrationCompleted
+			if oldSts != nil {
+				next.nodeNames = getStatefullsetNodeNames(oldSts)
+			}
+			diff.NeedUpdate = true
+			sb.WriteString(fmt.Sprintf("Node group %s is now on phase %s\n", oldNodeGroup.Name, next.phase))
+		default:
+			continue
+		}
+
+		migrations = append(migrations, next)
+	}
+
+	// Keep migration on progress until shards are relocated
+	if !diff.NeedCreate && !diff.NeedUpdate && isNodeGroupMigrationInProgress(opensearch) {
+		diff.NeedUpdate = true
+	}
+
+	data["migrations"] = migrations
+	diff.Diff = sb.String()
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchMigrationReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+		Type:    OpensearchMigrationCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition on the right state is everithink is good
+func (r *OpensearchMigrationReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	if len(opensearch.GetSuccessorNodeGroups()) == 0 {
+		condition.RemoveStatusCondition(&opensearch.Status.Conditions, OpensearchMigrationCondition)
+		return nil
+	}
+
+	if diff.Diff != "" {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Migration", "Node groups migration:\n%s", diff.Diff)
+	}
+
+	for _, migration := range opensearch.Status.NodeGroupMigrations {
+		if migration.Phase != opensearchapi.NodeGroupMigrationCompleted {
+			condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+				Type:    OpensearchMigrationCondition,
+				Reason:  migration.Phase,
+				Status:  metav1.ConditionFalse,
+				Message: fmt.Sprintf("Node group %s is migrating on %s", migration.NodeGroup, migration.Successor),
+			})
+			return nil
+		}
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(opensearch.Status.Conditions, OpensearchMigrationCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:    OpensearchMigrationCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Node groups migrations completed",
+		})
+	}
+
+	return nil
+}
+
+// deleteNodeGroup permit to remove the resources of migrated node group
+func (r *OpensearchMigrationReconciler) deleteNodeGroup(ctx context.Context, opensearch *opensearchapi.Opensearch, nodeGroupName string) (err error) {
+	objects := []client.Object{
+		&appv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: opensearch.Namespace, Name: opensearch.GetNodeGroupName(nodeGroupName)}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: opensearch.Namespace, Name: opensearch.GetNodeGroupServiceName(nodeGroupName)}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: opensearch.Namespace, Name: opensearch.GetNodeGroupServiceNameHeadless(nodeGroupName)}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: opensearch.Namespace, Name: opensearch.GetNodeGroupConfigMapName(nodeGroupName)}},
+		&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: opensearch.Namespace, Name: opensearch.GetNodeGroupPDBName(nodeGroupName)}},
+		&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: opensearch.Namespace, Name: opensearch.GetNodeGroupNetworkPolicyName(nodeGroupName)}},
+	}
+
+	for _, o := range objects {
+		if err = r.Client.Delete(ctx, o); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Error when delete %s of node group %s", o.GetName(), nodeGroupName)
+		}
+	}
+
+	return nil
+}
+
+// setNodeGroupMigration permit to add or update the migration status
+func setNodeGroupMigration(opensearch *opensearchapi.Opensearch, migration opensearchapi.NodeGroupMigrationStatus) {
+	if current := opensearch.GetNodeGroupMigration(migration.NodeGroup); current != nil {
+		*current = migration
+		return
+	}
+
+	opensearch.Status.NodeGroupMigrations = append(opensearch.Status.NodeGroupMigrations, migration)
+}
+
+// isNodeGroupMigrationInProgress return true if some node groups migrations are not yet completed
+func isNodeGroupMigrationInProgress(opensearch *opensearchapi.Opensearch) bool {
+	for _, migration := range opensearch.Status.NodeGroupMigrations {
+		if migration.Phase != opensearchapi.NodeGroupMigrationCompleted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getStatefullsetNodeNames permit to get the node names of existing statefullset
+func getStatefullsetNodeNames(sts *appv1.StatefulSet) (nodeNames []string) {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	nodeNames = make([]string, 0, replicas)
+	for i := 0; i < int(replicas); i++ {
+		nodeNames = append(nodeNames, fmt.Sprintf("%s-%d", sts.Name, i))
+	}
+
+	return nodeNames
+}
+
+// isStatefullsetReady return true if all replicas of statefullset are ready
+func isStatefullsetReady(sts *appv1.StatefulSet) bool {
+	if sts == nil {
+		return false
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	return sts.Status.ReadyReplicas == replicas && sts.Status.UpdatedReplicas == replicas
+}
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchMonitoringCondition = "OpensearchMonitoring"
+	OpensearchMonitoringPhase     = "Configure monitoring"
+)
+
+type OpensearchMonitoringReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchMonitoringReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if o.IsMonitoringEnabled() && condition.FindStatusCondition(o.Status.Conditions, OpensearchMonitoringCondition) == nil {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:   OpensearchMonitoringCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read the current monitoring user, role, role mapping, metrics service and monitors
+// They are read even if monitoring is disabled, to remove them
+func (r *OpensearchMonitoringReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if data["currentUser"], err = getResource(ctx, r.Client, o.Namespace, o.GetMonitoringUserName(), &opensearchapi.OpensearchUser{}); err != nil {
+		return res, err
+	}
+	if data["currentRole"], err = getResource(ctx, r.Client, o.Namespace, o.GetMonitoringUserName(), &opensearchapi.OpensearchRole{}); err != nil {
+		return res, err
+	}
+	if data["currentRoleMapping"], err = getResource(ctx, r.Client, o.Namespace, o.GetMonitoringUserName(), &opensearchapi.OpensearchRoleMapping{}); err != nil {
+		return res, err
+	}
+	if data["currentService"], err = getResource(ctx, r.Client, o.Namespace, o.GetMonitoringServiceName(), &corev1.Service{}); err != nil {
+		return res, err
+	}
+	if data["currentServiceMonitor"], err = r.getMonitor(ctx, o, opensearchapi.MonitorTypeServiceMonitor); err != nil {
+		return res, err
+	}
+	if data["currentPodMonitor"], err = r.getMonitor(ctx, o, opensearchapi.MonitorTypePodMonitor); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Create do nothing, monitoring resources are always updated
+func (r *OpensearchMonitoringReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return res, nil
+}
+
+// Update permit to create, update or remove the monitoring resources
+func (r *OpensearchMonitoringReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	d, err := helper.Get(data, "compareResources")
+	if err != nil {
+		return res, err
+	}
+	if err = applyResources(ctx, r.Client, d.([]CompareResource)); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Delete do nothing
+// The resources are removed by garbage collector
+func (r *OpensearchMonitoringReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compare the expected monitoring resources with the current resources
+// The monitor of the other type is removed, in case of monitor type change
+func (r *OpensearchMonitoringReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	expectedResources := map[string]client.Object{
+		"currentUser":           nil,
+		"currentRole":           nil,
+		"currentRoleMapping":    nil,
+		"currentService":        nil,
+		"currentServiceMonitor": nil,
+		"currentPodMonitor":     nil,
+	}
+
+	if expectedUser := o.GenerateMonitoringUser(); expectedUser != nil {
+		expectedResources["currentUser"] = expectedUser
+	}
+	if expectedRole := o.GenerateMonitoringRole(); expectedRole != nil {
+		expectedResources["currentRole"] = expectedRole
+	}
+	if expectedRoleMapping := o.GenerateMonitoringRoleMapping(); expectedRoleMapping != nil {
+		expectedResources["currentRoleMapping"] = expectedRoleMapping
+	}
+	expectedService, err := o.GenerateMonitoringService()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate metrics service")
+	}
+	if expectedService != nil {
+		expectedResources["currentService"] = expectedService
+	}
+	expectedMonitor, err := o.GenerateMonitor()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate monitor")
+	}
+	if expectedMonitor != nil {
+		expectedResources[fmt.Sprintf("current%s", expectedMonitor.GetKind())] = expectedMonitor
+	}
+
+	compares, diff, err := compareResources(o, r.Scheme, data, expectedResources)
+	if err != nil {
+		return diff, err
+	}
+	data["compareResources"] = compares
+
+	if len(compares) > 0 {
+		diff.NeedUpdate = true
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchMonitoringReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+		Type:    OpensearchMonitoringCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition on the right state
+func (r *OpensearchMonitoringReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if diff.NeedUpdate {
+		r.recorder.Event(resource, corev1.EventTypeNormal, "Monitoring", "Monitoring successfully updated:\n"+diff.Diff)
+	}
+
+	if !o.IsMonitoringEnabled() {
+		condition.RemoveStatusCondition(&o.Status.Conditions, OpensearchMonitoringCondition)
+		return nil
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(o.Status.Conditions, OpensearchMonitoringCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchMonitoringCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Monitoring up to date",
+		})
+	}
+
+	return nil
+}
+
+// getMonitor permit to read the ServiceMonitor or the PodMonitor of cluster
+// It return nil if it not exist or if the Prometheus operator CRD is not installed
+func (r *OpensearchMonitoringReconciler) getMonitor(ctx context.Context, o *opensearchapi.Opensearch, kind string) (monitor client.Object, err error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1",
+		Kind:    kind,
+	})
+
+	if err = r.Client.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: o.GetGlobalServiceName()}, current); err != nil {
+		if k8serrors.IsNotFound(err) || condition.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when read %s %s", kind, o.GetGlobalServiceName())
+	}
+
+	return current, nil
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchNetworkPolicyCondition = "OpensearchNetworkPolicy"
+	OpensearchNetworkPolicyPhase     = "Configure network policies"
+)
+
+type OpensearchNetworkPolicyReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchNetworkPolicyReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if o.IsNetworkPolicyEnabled() && condition.FindStatusCondition(o.Status.Conditions, OpensearchNetworkPolicyCondition) == nil {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:   OpensearchNetworkPolicyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read the current network policy of each node group
+// They are read even if network policies are disabled, to remove them
+func (r *OpensearchNetworkPolicyReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	for _, nodeGroup := range o.Spec.NodeGroups {
+		networkPolicyName := o.GetNodeGroupNetworkPolicyName(nodeGroup.Name)
+		if data[fmt.Sprintf("current%s", networkPolicyName)], err = getResource(ctx, r.Client, o.Namespace, networkPolicyName, &networkingv1.NetworkPolicy{}); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// Create do nothing, network policies are always updated
+func (r *OpensearchNetworkPolicyReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return res, nil
+}
+
+// Update permit to create, update or remove the network policies
+func (r *OpensearchNetworkPolicyReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	d, err := helper.Get(data, "compareResources")
+	if err != nil {
+		return res, err
+	}
+	if err = applyResources(ctx, r.Client, d.([]CompareResource)); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Delete do nothing
+// The network policies are removed by garbage collector
+func (r *OpensearchNetworkPolicyReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compare the expected network policies with the current network policies
+// The operator pods are selected on the namespace where the operator run
+func (r *OpensearchNetworkPolicyReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	expectedResources := make(map[string]client.Object, len(o.Spec.NodeGroups))
+	for _, nodeGroup := range o.Spec.NodeGroups {
+		expectedResources[fmt.Sprintf("current%s", o.GetNodeGroupNetworkPolicyName(nodeGroup.Name))] = nil
+	}
+
+	expectedNetworkPolicies, err := o.GenerateNetworkPolicies(getOperatorNamespace())
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate network policies")
+	}
+	for _, expectedNetworkPolicy := range expectedNetworkPolicies {
+		expectedResources[fmt.Sprintf("current%s", expectedNetworkPolicy.Name)] = expectedNetworkPolicy
+	}
+
+	compares, diff, err := compareResources(o, r.Scheme, data, expectedResources)
+	if err != nil {
+		return diff, err
+	}
+	data["compareResources"] = compares
+
+	if len(compares) > 0 {
+		diff.NeedUpdate = true
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchNetworkPolicyReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+		Type:    OpensearchNetworkPolicyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition on the right state
+func (r *OpensearchNetworkPolicyReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if diff.NeedUpdate {
+		r.recorder.Event(resource, corev1.EventTypeNormal, "NetworkPolicy", "Network policies successfully updated:\n"+diff.Diff)
+	}
+
+	if !o.IsNetworkPolicyEnabled() {
+		condition.RemoveStatusCondition(&o.Status.Conditions, OpensearchNetworkPolicyCondition)
+		return nil
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(o.Status.Conditions, OpensearchNetworkPolicyCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchNetworkPolicyCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Network policies up to date",
+		})
+	}
+
+	return nil
+}
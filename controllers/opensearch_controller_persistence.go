@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchPersistenceCondition = "OpensearchPersistence"
+	OpensearchPersistencePhase     = "Expand volumes"
+)
+
+type OpensearchPersistenceReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchPersistenceReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(opensearch.Status.Conditions, OpensearchPersistenceCondition) == nil {
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:   OpensearchPersistenceCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read existing statefullsets, persistent volume claims and storage classes of node groups with volume claim, and the snapshot repositories credentials
+func (r *OpensearchPersistenceReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+	currentStatefullsets := map[string]*appv1.StatefulSet{}
+	currentPvcs := map[string][]corev1.PersistentVolumeClaim{}
+	storageClasses := map[string]*storagev1.StorageClass{}
+
+	for _, nodeGroup := range opensearch.Spec.NodeGroups {
+		if nodeGroup.Persistence == nil || nodeGroup.Persistence.VolumeClaimSpec == nil {
+			continue
+		}
+		name := opensearch.GetNodeGroupName(nodeGroup.Name)
+
+		// Read existing statefullset
+		sts := &appv1.StatefulSet{}
+		if err = r.Client.Get(ctx, types.NamespacedName{Namespace: opensearch.Namespace, Name: name}, sts); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return res, errors.Wrapf(err, "Error when read statefullset %s", name)
+			}
+			sts = nil
+		}
+		currentStatefullsets[name] = sts
+
+		// Read existing persistent volume claims
+		// Statefullset set the selector labels on persistent volume claims
+		pvcList := &corev1.PersistentVolumeClaimList{}
+		if err = r.Client.List(ctx, pvcList, client.InNamespace(opensearch.Namespace), client.MatchingLabels{"cluster": opensearch.Name, "nodeGroup": nodeGroup.Name}); err != nil {
+			return res, errors.Wrapf(err, "Error when read persistent volume claims of statefullset %s", name)
+		}
+		currentPvcs[name] = pvcList.Items
+
+		// Read storage class
+		for _, pvc := range pvcList.Items {
+			if pvc.Spec.StorageClassName == nil {
+				continue
+			}
+			sc := &storagev1.StorageClass{}
+			if err = r.Client.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, sc); err != nil {
+				if !k8serrors.IsNotFound(err) {
+					return res, errors.Wrapf(err, "Error when read storage class %s", *pvc.Spec.StorageClassName)
+				}
+				sc = nil
+			}
+			storageClasses[name] = sc
+			break
+		}
+	}
+
+	// Read snapshot repositories credentials, to generate the same statefullsets than cluster
+	if data["keystoreSecrets"], err = getKeystoreSecrets(ctx, r.Client, opensearch); err != nil {
+		return res, err
+	}
+
+	data["currentStatefullsets"] = currentStatefullsets
+	data["currentPvcs"] = currentPvcs
+	data["storageClasses"] = storageClasses
+
+	return res, nil
+}
+
+// Create recreate the statefullsets deleted with orphan semantic
+func (r *OpensearchPersistenceReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return r.Update(ctx, resource, data, meta)
+}
+
+// Update permit to expand persistent volume claims and to recreate statefullsets with the new volume claim templates
+func (r *OpensearchPersistenceReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+	var d any
+
+	d, err = helper.Get(data, "pvcsToExpand")
+	if err != nil {
+		return res, err
+	}
+	pvcsToExpand := d.([]corev1.PersistentVolumeClaim)
+
+	d, err = helper.Get(data, "statefullsetsToRecreate")
+	if err != nil {
+		return res, err
+	}
+	statefullsetsToRecreate := d.([]*appv1.StatefulSet)
+
+	d, err = helper.Get(data, "statefullsetsToCreate")
+	if err != nil {
+		return res, err
+	}
+	statefullsetsToCreate := d.([]*appv1.StatefulSet)
+
+	// Expand persistent volume claims
+	for _, pvc := range pvcsToExpand {
+		if err = r.Client.Update(ctx, &pvc); err != nil {
+			return res, errors.Wrapf(err, "Error when expand persistent volume claim %s", pvc.Name)
+		}
+	}
+
+	// Delete statefullsets and keep pods and persistent volume claims
+	for _, sts := range statefullsetsToRecreate {
+		if err = r.Client.Delete(ctx, sts, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !k8serrors.IsNotFound(err) {
+			return res, errors.Wrapf(err, "Error when delete statefullset %s with orphan semantic", sts.Name)
+		}
+		statefullsetsToCreate = append(statefullsetsToCreate, sts)
+	}
+
+	// Create statefullsets with the new volume claim templates
+	// The old one can be not yet deleted, so it retry later
+	for _, sts := range statefullsetsToCreate {
+		expectedSts := &appv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   sts.Namespace,
+				Name:        sts.Name,
+				Labels:      sts.Labels,
+				Annotations: sts.Annotations,
+			},
+			Spec: sts.Spec,
+		}
+		if err = ctrl.SetControllerReference(opensearch, expectedSts, r.Scheme); err != nil {
+			return res, errors.Wrapf(err, "Error when set owner reference on statefullset %s", expectedSts.Name)
+		}
+		if err = r.Client.Create(ctx, expectedSts); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				r.log.Infof("Statefullset %s is not yet deleted, retry later", expectedSts.Name)
+				res.RequeueAfter = 5 * time.Second
+				continue
+			}
+			return res, errors.Wrapf(err, "Error when recreate statefullset %s", expectedSts.Name)
+		}
+	}
+
+	return res, nil
+}
+
+// Delete do nothing
+// We add parent link, so k8s auto delete children
+func (r *OpensearchPersistenceReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to check if persistent volume claims need to be expanded
+func (r *OpensearchPersistenceReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+	var d any
+	var sb strings.Builder
+
+	d, err = helper.Get(data, "currentStatefullsets")
+	if err != nil {
+		return diff, err
+	}
+	currentStatefullsets := d.(map[string]*appv1.StatefulSet)
+
+	d, err = helper.Get(data, "currentPvcs")
+	if err != nil {
+		return diff, err
+	}
+	currentPvcs := d.(map[string][]corev1.PersistentVolumeClaim)
+
+	d, err = helper.Get(data, "storageClasses")
+	if err != nil {
+		return diff, err
+	}
+	storageClasses := d.(map[string]*storagev1.StorageClass)
+
+	d, err = helper.Get(data, "keystoreSecrets")
+	if err != nil {
+		return diff, err
+	}
+	keystoreSecrets := d.(map[string]*corev1.Secret)
+
+	expectedStatefullsets, err := opensearch.GenerateStatefullsets(keystoreSecrets)
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate statefullsets")
+	}
+
+	pvcsToExpand := make([]corev1.PersistentVolumeClaim, 0)
+	statefullsetsToRecreate := make([]*appv1.StatefulSet, 0)
+	statefullsetsToCreate := make([]*appv1.StatefulSet, 0)
+
+	for _, expectedSts := range expectedStatefullsets {
+		currentSts, isManaged := currentStatefullsets[expectedSts.Name]
+		if !isManaged {
+			continue
+		}
+
+		// Statefullset deleted with orphan semantic and not yet recreated
+		if currentSts == nil {
+			if len(currentPvcs[expectedSts.Name]) > 0 {
+				statefullsetsToCreate = append(statefullsetsToCreate, expectedSts)
+				diff.NeedCreate = true
+				sb.WriteString(fmt.Sprintf("Recreate statefullset %s\n", expectedSts.Name))
+			}
+			continue
+		}
+
+		pvcs, needRecreate, err := opensearchapi.ComputeVolumeExpansion(currentSts, expectedSts, currentPvcs[expectedSts.Name], storageClasses[expectedSts.Name])
+		if err != nil {
+			return diff, err
+		}
+		for _, pvc := range pvcs {
+			sb.WriteString(fmt.Sprintf("Expand persistent volume claim %s\n", pvc.Name))
+		}
+		pvcsToExpand = append(pvcsToExpand, pvcs...)
+		if needRecreate {
+			sb.WriteString(fmt.Sprintf("Recreate statefullset %s with orphan semantic\n", expectedSts.Name))
+			statefullsetsToRecreate = append(statefullsetsToRecreate, expectedSts)
+		}
+	}
+
+	if len(pvcsToExpand) > 0 || len(statefullsetsToRecreate) > 0 {
+		diff.NeedUpdate = true
+	}
+
+	data["pvcsToExpand"] = pvcsToExpand
+	data["statefullsetsToRecreate"] = statefullsetsToRecreate
+	data["statefullsetsToCreate"] = statefullsetsToCreate
+	diff.Diff = sb.String()
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchPersistenceReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+		Type:    OpensearchPersistenceCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition on the right state is everithink is good
+func (r *OpensearchPersistenceReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Persistence successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(opensearch.Status.Conditions, OpensearchPersistenceCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:    OpensearchPersistenceCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Persistent volume claims up to date",
+		})
+	}
+
+	return nil
+}
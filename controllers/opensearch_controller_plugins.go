@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchPluginsCondition = "OpensearchPlugins"
+	OpensearchPluginsPhase     = "Check plugins"
+)
+
+type OpensearchPluginsReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchPluginsReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if opensearch.IsPrebuiltPlugins() && condition.FindStatusCondition(opensearch.Status.Conditions, OpensearchPluginsCondition) == nil {
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:   OpensearchPluginsCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read the pods of cluster
+func (r *OpensearchPluginsReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+	podList := &corev1.PodList{}
+
+	if opensearch.IsPrebuiltPlugins() {
+		if err = r.Client.List(ctx, podList, client.InNamespace(opensearch.Namespace), client.MatchingLabels{"cluster": opensearch.Name}); err != nil {
+			return res, errors.Wrapf(err, "Error when read pods of cluster %s", opensearch.Name)
+		}
+	}
+
+	data["pods"] = podList.Items
+
+	return res, nil
+}
+
+// Create do nothing, plugins are baked on image
+func (r *OpensearchPluginsReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return res, nil
+}
+
+// Update do nothing, plugins are baked on image
+func (r *OpensearchPluginsReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return res, nil
+}
+
+// Delete do nothing
+func (r *OpensearchPluginsReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to collect the missing plugins reported by the check plugins init container
+// It never need to create or update something, missing plugins are only reported on status
+func (r *OpensearchPluginsReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	var d any
+
+	d, err = helper.Get(data, "pods")
+	if err != nil {
+		return diff, err
+	}
+	pods := d.([]corev1.Pod)
+
+	missingPlugins := make([]string, 0)
+	for _, pod := range pods {
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != opensearchapi.PluginsCheckContainerName {
+				continue
+			}
+			if status.LastTerminationState.Terminated != nil && status.LastTerminationState.Terminated.ExitCode != 0 {
+				missingPlugins = append(missingPlugins, fmt.Sprintf("%s: %s", pod.Name, strings.TrimSpace(status.LastTerminationState.Terminated.Message)))
+			} else if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+				missingPlugins = append(missingPlugins, fmt.Sprintf("%s: %s", pod.Name, strings.TrimSpace(status.State.Terminated.Message)))
+			}
+		}
+	}
+	sort.Strings(missingPlugins)
+
+	data["missingPlugins"] = missingPlugins
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchPluginsReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+		Type:    OpensearchPluginsCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition with the missing plugins
+func (r *OpensearchPluginsReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	if !opensearch.IsPrebuiltPlugins() {
+		condition.RemoveStatusCondition(&opensearch.Status.Conditions, OpensearchPluginsCondition)
+		return nil
+	}
+
+	d, err := helper.Get(data, "missingPlugins")
+	if err != nil {
+		return err
+	}
+	missingPlugins := d.([]string)
+
+	if len(missingPlugins) > 0 {
+		message := strings.Join(missingPlugins, "\n")
+		if !condition.IsStatusConditionPresentAndEqual(opensearch.Status.Conditions, OpensearchPluginsCondition, metav1.ConditionFalse) || condition.FindStatusCondition(opensearch.Status.Conditions, OpensearchPluginsCondition).Message != message {
+			r.recorder.Event(resource, corev1.EventTypeWarning, "MissingPlugins", message)
+		}
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:    OpensearchPluginsCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "MissingPlugins",
+			Message: message,
+		})
+
+		return nil
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(opensearch.Status.Conditions, OpensearchPluginsCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:    OpensearchPluginsCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "All plugins are baked on image",
+		})
+	}
+
+	return nil
+}
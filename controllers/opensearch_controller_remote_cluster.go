@@ -0,0 +1,449 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/thoas/go-funk"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchRemoteClusterCondition = "OpensearchRemoteCluster"
+	OpensearchRemoteClusterPhase     = "Configure remote clusters"
+)
+
+type OpensearchRemoteClusterReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchRemoteClusterReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if isRemoteClustersManaged(o) && condition.FindStatusCondition(o.Status.Conditions, OpensearchRemoteClusterCondition) == nil {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:   OpensearchRemoteClusterCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read the seeds of remote clusters, the current persistent settings, the remote clusters connection state and the auto follow rules
+// They are only read when the cluster is healthy, like cluster settings
+func (r *OpensearchRemoteClusterReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	data["isHealthy"] = false
+
+	if !isRemoteClustersManaged(o) {
+		return res, nil
+	}
+
+	osClient, err := newOpensearchClient(ctx, r.Client, o)
+	if err != nil {
+		return res, err
+	}
+	data["client"] = osClient
+
+	health, err := osClient.GetClusterHealth(ctx)
+	if err != nil {
+		return res, err
+	}
+	if health.Status == clusterHealthRed {
+		r.log.Infof("Wait cluster is healthy to configure remote clusters, current status is %s", health.Status)
+		return res, nil
+	}
+	data["isHealthy"] = true
+
+	// Compute seeds
+	seeds := map[string][]string{}
+	for _, remoteCluster := range o.Spec.RemoteClusters {
+		if remoteCluster.OpensearchRef == nil {
+			seeds[remoteCluster.Name] = remoteCluster.Seeds
+			continue
+		}
+		remoteOpensearch, err := getResource(ctx, r.Client, o.GetRemoteOpensearchNamespace(&remoteCluster), remoteCluster.OpensearchRef.Name, &opensearchapi.Opensearch{})
+		if err != nil {
+			return res, err
+		}
+		if remoteOpensearch == nil {
+			r.log.Infof("Wait Opensearch %s/%s is created to configure remote cluster %s", o.GetRemoteOpensearchNamespace(&remoteCluster), remoteCluster.OpensearchRef.Name, remoteCluster.Name)
+			continue
+		}
+		seeds[remoteCluster.Name] = remoteOpensearch.(*opensearchapi.Opensearch).GetTransportSeeds()
+	}
+	data["seeds"] = seeds
+
+	currentSettings, err := osClient.GetClusterSettings(ctx)
+	if err != nil {
+		return res, err
+	}
+	data["currentSettings"] = currentSettings.Persistent
+
+	remoteClusters, err := osClient.GetRemoteClusters(ctx)
+	if err != nil {
+		return res, err
+	}
+	data["remoteClusters"] = remoteClusters
+
+	// The replication plugin is only needed when auto follow rules are managed
+	currentRules := map[string]opensearch.AutoFollowRuleStats{}
+	if isAutoFollowRulesManaged(o) {
+		currentRules, err = osClient.GetAutoFollowRules(ctx)
+		if err != nil {
+			return res, err
+		}
+	}
+	data["currentRules"] = currentRules
+
+	return res, nil
+}
+
+// Create do nothing, remote clusters are always updated
+func (r *OpensearchRemoteClusterReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return res, nil
+}
+
+// Update permit to apply the remote cluster settings, then to delete and create the auto follow rules
+func (r *OpensearchRemoteClusterReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+	var d any
+
+	d, err = helper.Get(data, "client")
+	if err != nil {
+		return res, err
+	}
+	osClient := d.(*opensearch.Client)
+
+	d, err = helper.Get(data, "settingsToApply")
+	if err != nil {
+		return res, err
+	}
+	settingsToApply := d.(map[string]any)
+
+	d, err = helper.Get(data, "rulesToDelete")
+	if err != nil {
+		return res, err
+	}
+	rulesToDelete := d.([]opensearch.AutoFollowRule)
+
+	d, err = helper.Get(data, "rulesToCreate")
+	if err != nil {
+		return res, err
+	}
+	rulesToCreate := d.([]opensearch.AutoFollowRule)
+
+	// Rules need to be removed before the remote cluster
+	for _, rule := range rulesToDelete {
+		if err = osClient.DeleteAutoFollowRule(ctx, rule.LeaderAlias, rule.Name); err != nil {
+			return res, err
+		}
+		if status := getRemoteClusterStatus(o, rule.LeaderAlias); status != nil {
+			status.AutoFollowRules = funk.SubtractString(status.AutoFollowRules, []string{rule.Name})
+		}
+	}
+
+	if len(settingsToApply) > 0 {
+		if err = osClient.PutClusterSettings(ctx, settingsToApply); err != nil {
+			return res, err
+		}
+	}
+
+	for _, rule := range rulesToCreate {
+		if err = osClient.CreateAutoFollowRule(ctx, &rule); err != nil {
+			return res, err
+		}
+		status := getRemoteClusterStatus(o, rule.LeaderAlias)
+		if status == nil {
+			setRemoteClusterStatus(o, opensearchapi.RemoteClusterStatus{Name: rule.LeaderAlias})
+			status = getRemoteClusterStatus(o, rule.LeaderAlias)
+		}
+		if !funk.ContainsString(status.AutoFollowRules, rule.Name) {
+			status.AutoFollowRules = append(status.AutoFollowRules, rule.Name)
+		}
+	}
+
+	return res, nil
+}
+
+// Delete do nothing
+// Remote clusters settings are removed with the cluster
+func (r *OpensearchRemoteClusterReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compute the remote cluster settings to apply and the auto follow rules to create or delete
+// The auto follow rules are only created when the remote cluster is connected
+func (r *OpensearchRemoteClusterReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+	var d any
+	var sb strings.Builder
+
+	d, err = helper.Get(data, "isHealthy")
+	if err != nil {
+		return diff, err
+	}
+	if !d.(bool) {
+		return diff, nil
+	}
+
+	d, err = helper.Get(data, "seeds")
+	if err != nil {
+		return diff, err
+	}
+	seeds := d.(map[string][]string)
+
+	d, err = helper.Get(data, "currentSettings")
+	if err != nil {
+		return diff, err
+	}
+	currentSettings := d.(map[string]any)
+
+	d, err = helper.Get(data, "remoteClusters")
+	if err != nil {
+		return diff, err
+	}
+	remoteClusters := d.(map[string]opensearch.RemoteClusterInfo)
+
+	d, err = helper.Get(data, "currentRules")
+	if err != nil {
+		return diff, err
+	}
+	currentRules := d.(map[string]opensearch.AutoFollowRuleStats)
+
+	settingsToApply := o.ComputeRemoteClusterSettingsToApply(seeds, currentSettings)
+	if len(settingsToApply) > 0 {
+		currentManagedSettings := map[string]any{}
+		for key := range settingsToApply {
+			currentManagedSettings[key] = currentSettings[key]
+		}
+		diff.NeedUpdate = true
+		sb.WriteString(localhelper.Diff(settingsToApply, currentManagedSettings))
+	}
+
+	rulesToCreate := make([]opensearch.AutoFollowRule, 0)
+	rulesToDelete := make([]opensearch.AutoFollowRule, 0)
+	expectedRules := map[string][]string{}
+
+	for _, remoteCluster := range o.Spec.RemoteClusters {
+		if remoteCluster.Replication == nil {
+			continue
+		}
+		for _, rule := range remoteCluster.Replication.AutoFollowRules {
+			expectedRules[remoteCluster.Name] = append(expectedRules[remoteCluster.Name], rule.Name)
+
+			currentRule, isExist := currentRules[rule.Name]
+			if isExist && currentRule.Pattern == rule.Pattern {
+				continue
+			}
+			if !remoteClusters[remoteCluster.Name].Connected {
+				r.log.Infof("Wait remote cluster %s is connected to create auto follow rule %s", remoteCluster.Name, rule.Name)
+				continue
+			}
+
+			// The pattern can't be updated
+			if isExist {
+				rulesToDelete = append(rulesToDelete, opensearch.AutoFollowRule{LeaderAlias: remoteCluster.Name, Name: rule.Name})
+				sb.WriteString(fmt.Sprintf("Recreate auto follow rule %s\n", rule.Name))
+			} else {
+				sb.WriteString(fmt.Sprintf("Create auto follow rule %s\n", rule.Name))
+			}
+			expectedRule := opensearch.AutoFollowRule{
+				LeaderAlias: remoteCluster.Name,
+				Name:        rule.Name,
+				Pattern:     rule.Pattern,
+			}
+			if rule.LeaderClusterRole != "" || rule.FollowerClusterRole != "" {
+				expectedRule.UseRoles = &opensearch.ReplicationRoles{
+					LeaderClusterRole:   rule.LeaderClusterRole,
+					FollowerClusterRole: rule.FollowerClusterRole,
+				}
+			}
+			rulesToCreate = append(rulesToCreate, expectedRule)
+			diff.NeedUpdate = true
+		}
+	}
+
+	// Only the rules created by operator are removed
+	for _, status := range o.Status.RemoteClusters {
+		for _, name := range status.AutoFollowRules {
+			if funk.ContainsString(expectedRules[status.Name], name) {
+				continue
+			}
+			rulesToDelete = append(rulesToDelete, opensearch.AutoFollowRule{LeaderAlias: status.Name, Name: name})
+			sb.WriteString(fmt.Sprintf("Delete auto follow rule %s\n", name))
+			diff.NeedUpdate = true
+		}
+	}
+
+	data["settingsToApply"] = settingsToApply
+	data["rulesToCreate"] = rulesToCreate
+	data["rulesToDelete"] = rulesToDelete
+	diff.Diff = sb.String()
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchRemoteClusterReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+		Type:    OpensearchRemoteClusterCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set the remote clusters status and the status condition from the connection state
+func (r *OpensearchRemoteClusterReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if !isRemoteClustersManaged(o) {
+		condition.RemoveStatusCondition(&o.Status.Conditions, OpensearchRemoteClusterCondition)
+		return nil
+	}
+
+	if diff.NeedUpdate {
+		r.recorder.Event(resource, corev1.EventTypeNormal, "RemoteClusters", "Remote clusters successfully updated:\n"+diff.Diff)
+	}
+
+	d, err := helper.Get(data, "isHealthy")
+	if err != nil {
+		return err
+	}
+	if !d.(bool) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRemoteClusterCondition,
+			Reason:  "WaitHealthy",
+			Status:  metav1.ConditionFalse,
+			Message: "Wait cluster is healthy to configure remote clusters",
+		})
+		return nil
+	}
+
+	d, err = helper.Get(data, "remoteClusters")
+	if err != nil {
+		return err
+	}
+	remoteClusters := d.(map[string]opensearch.RemoteClusterInfo)
+
+	// The remote clusters removed from spec are already reset
+	names := make([]string, 0, len(o.Spec.RemoteClusters))
+	notConnectedRemoteClusters := make([]string, 0)
+	for _, remoteCluster := range o.Spec.RemoteClusters {
+		names = append(names, remoteCluster.Name)
+		status := opensearchapi.RemoteClusterStatus{
+			Name:      remoteCluster.Name,
+			Connected: remoteClusters[remoteCluster.Name].Connected,
+		}
+		if currentStatus := getRemoteClusterStatus(o, remoteCluster.Name); currentStatus != nil {
+			status.AutoFollowRules = currentStatus.AutoFollowRules
+		}
+		setRemoteClusterStatus(o, status)
+		if !status.Connected {
+			notConnectedRemoteClusters = append(notConnectedRemoteClusters, remoteCluster.Name)
+		}
+	}
+	statuses := make([]opensearchapi.RemoteClusterStatus, 0, len(names))
+	for _, status := range o.Status.RemoteClusters {
+		if funk.ContainsString(names, status.Name) {
+			statuses = append(statuses, status)
+		}
+	}
+	o.Status.RemoteClusters = statuses
+
+	if len(notConnectedRemoteClusters) > 0 {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRemoteClusterCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NotConnected",
+			Message: fmt.Sprintf("Remote clusters not connected: %s", strings.Join(notConnectedRemoteClusters, ", ")),
+		})
+		return nil
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(o.Status.Conditions, OpensearchRemoteClusterCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRemoteClusterCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Remote clusters connected",
+		})
+	}
+
+	return nil
+}
+
+// isRemoteClustersManaged return true if remote clusters are declared or need to be reset
+func isRemoteClustersManaged(o *opensearchapi.Opensearch) bool {
+	return len(o.Spec.RemoteClusters) > 0 || len(o.Status.RemoteClusters) > 0
+}
+
+// isAutoFollowRulesManaged return true if auto follow rules are declared or need to be removed
+func isAutoFollowRulesManaged(o *opensearchapi.Opensearch) bool {
+	for _, remoteCluster := range o.Spec.RemoteClusters {
+		if remoteCluster.Replication != nil && len(remoteCluster.Replication.AutoFollowRules) > 0 {
+			return true
+		}
+	}
+	for _, status := range o.Status.RemoteClusters {
+		if len(status.AutoFollowRules) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getRemoteClusterStatus permit to get the status of remote cluster
+func getRemoteClusterStatus(o *opensearchapi.Opensearch, name string) *opensearchapi.RemoteClusterStatus {
+	for i, status := range o.Status.RemoteClusters {
+		if status.Name == name {
+			return &o.Status.RemoteClusters[i]
+		}
+	}
+
+	return nil
+}
+
+// setRemoteClusterStatus permit to add or update the status of remote cluster
+func setRemoteClusterStatus(o *opensearchapi.Opensearch, status opensearchapi.RemoteClusterStatus) {
+	if current := getRemoteClusterStatus(o, status.Name); current != nil {
+		*current = status
+		return
+	}
+
+	o.Status.RemoteClusters = append(o.Status.RemoteClusters, status)
+}
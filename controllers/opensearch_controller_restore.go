@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchRestoreCondition = "OpensearchRestore"
+	OpensearchRestorePhase     = "Restore snapshot"
+	clusterHealthRed           = "red"
+)
+
+type OpensearchRestoreReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchRestoreReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if o.Spec.Restore != nil && condition.FindStatusCondition(o.Status.Conditions, OpensearchRestoreCondition) == nil {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:   OpensearchRestoreCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read the existing statefullsets when restore is requested, and the restore progress from the cluster
+// The restore is completed when there are no more shards recovered from snapshot and the cluster is not red
+func (r *OpensearchRestoreReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	data["isRestored"] = false
+
+	// The statefullsets say if the cluster is already created
+	if o.Spec.Restore != nil && o.Status.Restore == nil {
+		stsList := &appv1.StatefulSetList{}
+		if err = r.Client.List(ctx, stsList, client.InNamespace(o.Namespace), client.MatchingLabels{"cluster": o.Name}); err != nil {
+			return res, errors.Wrapf(err, "Error when read statefullsets of cluster %s", o.Name)
+		}
+		data["currentStatefullsets"] = stsList.Items
+		return res, nil
+	}
+
+	if !o.IsRestoreInProgress() {
+		return res, nil
+	}
+
+	// Client is needed to start restore or to follow it
+	osClient, err := newOpensearchClient(ctx, r.Client, o)
+	if err != nil {
+		return res, err
+	}
+	data["client"] = osClient
+
+	if o.Status.Restore.Phase == opensearchapi.RestoreRunning {
+		count, err := osClient.CountActiveSnapshotRecoveries(ctx)
+		if err != nil {
+			return res, err
+		}
+		health, err := osClient.GetClusterHealth(ctx)
+		if err != nil {
+			return res, err
+		}
+		r.log.Debugf("Restore in progress with %d shards to recover, cluster health is %s", count, health.Status)
+		data["isRestored"] = count == 0 && health.Status != clusterHealthRed
+	}
+
+	return res, nil
+}
+
+// Create init the restore status, it's pending on new cluster and ignored on existing cluster
+func (r *OpensearchRestoreReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	d, err := helper.Get(data, "currentStatefullsets")
+	if err != nil {
+		return res, err
+	}
+	o.Status.Restore = o.GenerateRestoreStatus(d.([]appv1.StatefulSet))
+
+	return res, nil
+}
+
+// Update permit to start restore when the snapshot repository is verified, and to complete it when all shards are restored
+func (r *OpensearchRestoreReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	d, err := helper.Get(data, "nextPhase")
+	if err != nil {
+		return res, err
+	}
+
+	switch d.(string) {
+	case opensearchapi.RestoreRunning:
+		d, err := helper.Get(data, "client")
+		if err != nil {
+			return res, err
+		}
+		osClient := d.(*opensearch.Client)
+
+		if err = osClient.RestoreSnapshot(ctx, o.Spec.Restore.Repository, o.Spec.Restore.Snapshot, &opensearch.RestoreRequest{
+			Indices:            strings.Join(o.Spec.Restore.Indices, ","),
+			RenamePattern:      o.Spec.Restore.RenamePattern,
+			RenameReplacement:  o.Spec.Restore.RenameReplacement,
+			IncludeGlobalState: o.Spec.Restore.IncludeGlobalState,
+		}); err != nil {
+			o.Status.Restore.Message = err.Error()
+			return res, err
+		}
+
+		now := metav1.Now()
+		o.Status.Restore.Phase = opensearchapi.RestoreRunning
+		o.Status.Restore.Message = ""
+		o.Status.Restore.StartTime = &now
+	case opensearchapi.RestoreCompleted:
+		now := metav1.Now()
+		o.Status.Restore.Phase = opensearchapi.RestoreCompleted
+		o.Status.Restore.CompletionTime = &now
+	}
+
+	// Restore can be long, so check it later
+	if o.IsRestoreInProgress() {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, nil
+}
+
+// Delete do nothing
+func (r *OpensearchRestoreReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compute the next restore phase
+func (r *OpensearchRestoreReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if o.Spec.Restore != nil && o.Status.Restore == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("Init restore of snapshot %s from repository %s", o.Spec.Restore.Snapshot, o.Spec.Restore.Repository)
+		return diff, nil
+	}
+
+	if !o.IsRestoreInProgress() {
+		return diff, nil
+	}
+
+	d, err := helper.Get(data, "isRestored")
+	if err != nil {
+		return diff, err
+	}
+	isRestored := d.(bool)
+
+	nextPhase := ""
+	switch o.Status.Restore.Phase {
+	case opensearchapi.RestorePending:
+		if status := getSnapshotRepositoryStatus(o, o.Spec.Restore.Repository); status != nil && status.Status == opensearchapi.SnapshotRepositoryVerified {
+			nextPhase = opensearchapi.RestoreRunning
+			diff.Diff = fmt.Sprintf("Start restore of snapshot %s from repository %s", o.Spec.Restore.Snapshot, o.Spec.Restore.Repository)
+		}
+	case opensearchapi.RestoreRunning:
+		if isRestored {
+			nextPhase = opensearchapi.RestoreCompleted
+			diff.Diff = fmt.Sprintf("Snapshot %s is restored", o.Spec.Restore.Snapshot)
+		}
+	}
+	data["nextPhase"] = nextPhase
+
+	// Keep restore on progress to follow it
+	diff.NeedUpdate = true
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchRestoreReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+		Type:    OpensearchRestoreCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition and cluster phase from the restore phase
+// The cluster is only ready once restore is completed
+func (r *OpensearchRestoreReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if o.Spec.Restore == nil {
+		condition.RemoveStatusCondition(&o.Status.Conditions, OpensearchRestoreCondition)
+		return nil
+	}
+
+	if o.IsRestoreIgnored() {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRestoreCondition,
+			Reason:  "Ignored",
+			Status:  metav1.ConditionFalse,
+			Message: o.Status.Restore.Message,
+		})
+		return nil
+	}
+
+	if diff.Diff != "" {
+		r.recorder.Event(resource, corev1.EventTypeNormal, "Restore", diff.Diff)
+	}
+
+	if o.IsRestoreInProgress() {
+		o.Status.Phase = opensearchapi.OpensearchPhaseRestoring
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRestoreCondition,
+			Reason:  o.Status.Restore.Phase,
+			Status:  metav1.ConditionFalse,
+			Message: fmt.Sprintf("Restore of snapshot %s is in progress", o.Spec.Restore.Snapshot),
+		})
+		return nil
+	}
+
+	if o.Status.Phase == opensearchapi.OpensearchPhaseRestoring {
+		o.Status.Phase = opensearchapi.OpensearchPhaseReady
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(o.Status.Conditions, OpensearchRestoreCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRestoreCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: fmt.Sprintf("Snapshot %s restored", o.Spec.Restore.Snapshot),
+		})
+	}
+
+	return nil
+}
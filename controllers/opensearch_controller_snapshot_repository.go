@@ -0,0 +1,344 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/thoas/go-funk"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OpensearchSnapshotRepositoryCondition = "OpensearchSnapshotRepository"
+	OpensearchSnapshotRepositoryPhase     = "Register snapshot repositories"
+)
+
+type OpensearchSnapshotRepositoryReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+// Configure permit to init condition
+func (r *OpensearchSnapshotRepositoryReconciler) Configure(ctx context.Context, req ctrl.Request, resource client.Object) (meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	// Init condition status if not exist
+	if len(opensearch.Spec.SnapshotRepositories) > 0 && condition.FindStatusCondition(opensearch.Status.Conditions, OpensearchSnapshotRepositoryCondition) == nil {
+		condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+			Type:   OpensearchSnapshotRepositoryCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return nil, nil
+}
+
+// Read the snapshot repositories registered on cluster
+func (r *OpensearchSnapshotRepositoryReconciler) Read(ctx context.Context, resource client.Object, data map[string]any, meta any) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+	currentRepositories := map[string]opensearch.SnapshotRepository{}
+
+	if len(o.Spec.SnapshotRepositories) > 0 || len(o.Status.SnapshotRepositories) > 0 {
+		osClient, err := newOpensearchClient(ctx, r.Client, o)
+		if err != nil {
+			return res, err
+		}
+		currentRepositories, err = osClient.GetSnapshotRepositories(ctx)
+		if err != nil {
+			return res, err
+		}
+		data["client"] = osClient
+	}
+
+	data["currentRepositories"] = currentRepositories
+
+	return res, nil
+}
+
+// Create register the new snapshot repositories
+func (r *OpensearchSnapshotRepositoryReconciler) Create(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	return r.Update(ctx, resource, data, meta)
+}
+
+// Update permit to register, unregister and verify snapshot repositories
+func (r *OpensearchSnapshotRepositoryReconciler) Update(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (res ctrl.Result, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+	var d any
+
+	d, err = helper.Get(data, "client")
+	if err != nil {
+		return res, err
+	}
+	osClient := d.(*opensearch.Client)
+
+	d, err = helper.Get(data, "repositoriesToPut")
+	if err != nil {
+		return res, err
+	}
+	repositoriesToPut := d.([]opensearchapi.SnapshotRepositorySpec)
+
+	d, err = helper.Get(data, "repositoriesToDelete")
+	if err != nil {
+		return res, err
+	}
+	repositoriesToDelete := d.([]string)
+
+	d, err = helper.Get(data, "repositoriesToVerify")
+	if err != nil {
+		return res, err
+	}
+	repositoriesToVerify := d.([]string)
+
+	for _, repository := range repositoriesToPut {
+		if err = osClient.PutSnapshotRepository(ctx, repository.Name, &opensearch.SnapshotRepository{
+			Type:     repository.Type,
+			Settings: repository.Settings,
+		}); err != nil {
+			setSnapshotRepositoryStatus(o, opensearchapi.SnapshotRepositoryStatus{
+				Name:    repository.Name,
+				Status:  opensearchapi.SnapshotRepositoryFailed,
+				Message: err.Error(),
+			})
+			return res, err
+		}
+		setSnapshotRepositoryStatus(o, opensearchapi.SnapshotRepositoryStatus{
+			Name:   repository.Name,
+			Status: opensearchapi.SnapshotRepositoryRegistered,
+		})
+	}
+
+	for _, name := range repositoriesToDelete {
+		if err = osClient.DeleteSnapshotRepository(ctx, name); err != nil {
+			return res, err
+		}
+		removeSnapshotRepositoryStatus(o, name)
+	}
+
+	// A repository that can't be verified not block the other repositories
+	for _, name := range repositoriesToVerify {
+		if _, err = osClient.VerifySnapshotRepository(ctx, name); err != nil {
+			r.log.Warnf("Snapshot repository %s can't be verified: %s", name, err.Error())
+			r.recorder.Eventf(resource, corev1.EventTypeWarning, "RepositoryFailed", "Snapshot repository %s can't be verified: %s", name, err.Error())
+			setSnapshotRepositoryStatus(o, opensearchapi.SnapshotRepositoryStatus{
+				Name:    name,
+				Status:  opensearchapi.SnapshotRepositoryFailed,
+				Message: err.Error(),
+			})
+			continue
+		}
+		setSnapshotRepositoryStatus(o, opensearchapi.SnapshotRepositoryStatus{
+			Name:   name,
+			Status: opensearchapi.SnapshotRepositoryVerified,
+		})
+	}
+
+	return res, nil
+}
+
+// Delete do nothing
+// Repositories are removed with the cluster
+func (r *OpensearchSnapshotRepositoryReconciler) Delete(ctx context.Context, resource client.Object, data map[string]interface{}, meta interface{}) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compare the expected snapshot repositories with the registered ones
+// Only the repositories managed by the operator (listed on status) are unregistered
+func (r *OpensearchSnapshotRepositoryReconciler) Diff(resource client.Object, data map[string]interface{}, meta interface{}) (diff controller.Diff, err error) {
+	o := resource.(*opensearchapi.Opensearch)
+	var sb strings.Builder
+
+	d, err := helper.Get(data, "currentRepositories")
+	if err != nil {
+		return diff, err
+	}
+	currentRepositories := d.(map[string]opensearch.SnapshotRepository)
+
+	repositoriesToPut := make([]opensearchapi.SnapshotRepositorySpec, 0)
+	repositoriesToDelete := make([]string, 0)
+	repositoriesToVerify := make([]string, 0)
+	expectedNames := make([]string, 0, len(o.Spec.SnapshotRepositories))
+
+	for _, repository := range o.Spec.SnapshotRepositories {
+		expectedNames = append(expectedNames, repository.Name)
+
+		currentRepository, isExist := currentRepositories[repository.Name]
+		if !isExist {
+			diff.NeedCreate = true
+			sb.WriteString(fmt.Sprintf("Register snapshot repository %s\n", repository.Name))
+			repositoriesToPut = append(repositoriesToPut, repository)
+			repositoriesToVerify = append(repositoriesToVerify, repository.Name)
+			continue
+		}
+
+		if currentRepository.Type != repository.Type || !isSameSettings(currentRepository.Settings, repository.Settings) {
+			diff.NeedUpdate = true
+			sb.WriteString(fmt.Sprintf("Update snapshot repository %s\n", repository.Name))
+			repositoriesToPut = append(repositoriesToPut, repository)
+			repositoriesToVerify = append(repositoriesToVerify, repository.Name)
+			continue
+		}
+
+		// Retry to verify repository until it's ok
+		if status := getSnapshotRepositoryStatus(o, repository.Name); status == nil || status.Status != opensearchapi.SnapshotRepositoryVerified {
+			diff.NeedUpdate = true
+			repositoriesToVerify = append(repositoriesToVerify, repository.Name)
+		}
+	}
+
+	for _, status := range o.Status.SnapshotRepositories {
+		if funk.ContainsString(expectedNames, status.Name) {
+			continue
+		}
+		diff.NeedUpdate = true
+		sb.WriteString(fmt.Sprintf("Unregister snapshot repository %s\n", status.Name))
+		repositoriesToDelete = append(repositoriesToDelete, status.Name)
+	}
+
+	data["repositoriesToPut"] = repositoriesToPut
+	data["repositoriesToDelete"] = repositoriesToDelete
+	data["repositoriesToVerify"] = repositoriesToVerify
+	diff.Diff = sb.String()
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchSnapshotRepositoryReconciler) OnError(ctx context.Context, resource client.Object, data map[string]any, meta any, err error) {
+	opensearch := resource.(*opensearchapi.Opensearch)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&opensearch.Status.Conditions, metav1.Condition{
+		Type:    OpensearchSnapshotRepositoryCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition from the repositories state
+func (r *OpensearchSnapshotRepositoryReconciler) OnSuccess(ctx context.Context, resource client.Object, data map[string]any, meta any, diff controller.Diff) (err error) {
+	o := resource.(*opensearchapi.Opensearch)
+
+	if len(o.Spec.SnapshotRepositories) == 0 {
+		condition.RemoveStatusCondition(&o.Status.Conditions, OpensearchSnapshotRepositoryCondition)
+		return nil
+	}
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Snapshot repositories successfully updated:\n%s", diff.Diff)
+	}
+
+	failedRepositories := make([]string, 0)
+	for _, status := range o.Status.SnapshotRepositories {
+		if status.Status != opensearchapi.SnapshotRepositoryVerified {
+			failedRepositories = append(failedRepositories, status.Name)
+		}
+	}
+	if len(failedRepositories) > 0 {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchSnapshotRepositoryCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "RepositoryFailed",
+			Message: fmt.Sprintf("Snapshot repositories not verified: %s", strings.Join(failedRepositories, ", ")),
+		})
+		return nil
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(o.Status.Conditions, OpensearchSnapshotRepositoryCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&o.Status.Conditions, metav1.Condition{
+			Type:    OpensearchSnapshotRepositoryCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Snapshot repositories verified",
+		})
+	}
+
+	return nil
+}
+
+// getSnapshotRepositoryStatus permit to get the status of snapshot repository
+func getSnapshotRepositoryStatus(o *opensearchapi.Opensearch, name string) *opensearchapi.SnapshotRepositoryStatus {
+	for i, status := range o.Status.SnapshotRepositories {
+		if status.Name == name {
+			return &o.Status.SnapshotRepositories[i]
+		}
+	}
+
+	return nil
+}
+
+// setSnapshotRepositoryStatus permit to add or update the status of snapshot repository
+func setSnapshotRepositoryStatus(o *opensearchapi.Opensearch, status opensearchapi.SnapshotRepositoryStatus) {
+	if current := getSnapshotRepositoryStatus(o, status.Name); current != nil {
+		*current = status
+		return
+	}
+
+	o.Status.SnapshotRepositories = append(o.Status.SnapshotRepositories, status)
+}
+
+// removeSnapshotRepositoryStatus permit to remove the status of unregistered snapshot repository
+func removeSnapshotRepositoryStatus(o *opensearchapi.Opensearch, name string) {
+	statuses := make([]opensearchapi.SnapshotRepositoryStatus, 0, len(o.Status.SnapshotRepositories))
+	for _, status := range o.Status.SnapshotRepositories {
+		if status.Name != name {
+			statuses = append(statuses, status)
+		}
+	}
+
+	o.Status.SnapshotRepositories = statuses
+}
+
+// isSameSettings return true if settings are the same
+// Nil and empty settings are the same
+func isSameSettings(current, expected map[string]string) bool {
+	if len(current) == 0 && len(expected) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(current, expected)
+}
+
+// getKeystoreSecrets permit to read the credentials secrets of snapshot repositories
+// A missing secret is skipped, the keystore init container fail until it is created
+func getKeystoreSecrets(ctx context.Context, c client.Client, o *opensearchapi.Opensearch) (secrets map[string]*corev1.Secret, err error) {
+	secrets = map[string]*corev1.Secret{}
+	for _, repository := range o.Spec.SnapshotRepositories {
+		if repository.CredentialsSecretRef == "" {
+			continue
+		}
+		secret, err := getResource(ctx, c, o.Namespace, repository.CredentialsSecretRef, &corev1.Secret{})
+		if err != nil {
+			return nil, err
+		}
+		if secret != nil {
+			secrets[repository.CredentialsSecretRef] = secret.(*corev1.Secret)
+		}
+	}
+
+	return secrets, nil
+}
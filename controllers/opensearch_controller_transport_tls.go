@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/disaster37/operator-sdk-extra/pkg/controller"
 	"github.com/disaster37/operator-sdk-extra/pkg/helper"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
 	"github.com/webcenter-fr/opensearch-operator/pkg/pki"
 	corev1 "k8s.io/api/core/v1"
@@ -22,6 +27,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"software.sslmate.com/src/go-pkcs12"
 )
 
@@ -97,9 +104,16 @@ func (r *OpensearchTransportTlsReconciler) Read(ctx context.Context, resource cl
 		}
 	}
 
+	// Read the transport CA of remote clusters
+	remoteCAs, err := r.readRemoteCAs(ctx, opensearch)
+	if err != nil {
+		return res, err
+	}
+
 	data["rootCA"] = rootCA
 	data["adminCertificate"] = adminCrt
 	data["nodeCertificates"] = nodeCertificates
+	data["remoteCAs"] = remoteCAs
 	data["currentSecret"] = s
 
 	return res, nil
@@ -180,6 +194,12 @@ func (r *OpensearchTransportTlsReconciler) Diff(resource client.Object, data map
 	}
 	nodeCertificates := d.(map[string]*x509.Certificate)
 
+	d, err = helper.Get(data, "remoteCAs")
+	if err != nil {
+		return diff, err
+	}
+	remoteCAs := d.([]byte)
+
 	diff = controller.Diff{
 		NeedCreate: false,
 		NeedUpdate: false,
@@ -190,7 +210,7 @@ func (r *OpensearchTransportTlsReconciler) Diff(resource client.Object, data map
 		diff.NeedCreate = true
 		diff.Diff = "Secret not exist"
 
-		expectedSecret, err := r.generateSecret(opensearch)
+		expectedSecret, err := r.generateSecret(opensearch, remoteCAs)
 		if err != nil {
 			return diff, errors.Wrapf(err, "Error when generate secret %s for TLS transport", secretName)
 		}
@@ -208,7 +228,7 @@ func (r *OpensearchTransportTlsReconciler) Diff(resource client.Object, data map
 		return diff, errors.Wrap(err, "Error when check is CA need to be renewed")
 	}
 	if needRenew {
-		expectedSecret, err := r.renewSecret(opensearch, currentSecret.Data["admin.pfx"])
+		expectedSecret, err := r.renewSecret(opensearch, currentSecret.Data["admin.pfx"], remoteCAs)
 		if err != nil {
 			return diff, errors.Wrapf(err, "Error when generate secret %s for TLS transport", secretName)
 		}
@@ -243,7 +263,7 @@ func (r *OpensearchTransportTlsReconciler) Diff(resource client.Object, data map
 		}
 		if needRenew {
 			// If one expired, we regenerate all certificates to be simple
-			expectedSecret, err := r.renewSecret(opensearch, currentSecret.Data["admin.pfx"])
+			expectedSecret, err := r.renewSecret(opensearch, currentSecret.Data["admin.pfx"], remoteCAs)
 			if err != nil {
 				return diff, errors.Wrapf(err, "Error when generate secret %s for TLS transport", secretName)
 			}
@@ -279,6 +299,15 @@ func (r *OpensearchTransportTlsReconciler) Diff(resource client.Object, data map
 		sb.WriteString("Renew admin certificate\n")
 	}
 
+	// Check if truststore need to be updated with the CA of remote clusters
+	if currentSecret.Data["truststore.pfx"] == nil || !bytes.Equal(currentSecret.Data["remote-ca.crt"], remoteCAs) {
+		if err = setTruststore(currentSecret, remoteCAs); err != nil {
+			return diff, err
+		}
+		diff.NeedUpdate = true
+		sb.WriteString("Update truststore with the CA of remote clusters\n")
+	}
+
 	if diff.NeedUpdate {
 		data["expectedSecret"] = currentSecret
 		diff.Diff = sb.String()
@@ -332,13 +361,14 @@ func (r *OpensearchTransportTlsReconciler) OnSuccess(ctx context.Context, resour
 
 
 // generateSecret generate the secret with all certificate needed by transport layout
-func (r *OpensearchTransportTlsReconciler) generateSecret(opensearch *opensearchapi.Opensearch) (secret *corev1.Secret, err error) {
+func (r *OpensearchTransportTlsReconciler) generateSecret(opensearch *opensearchapi.Opensearch, remoteCAs []byte) (secret *corev1.Secret, err error) {
 	secret = &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      opensearch.GetSecretNameForTlsTransport(),
 			Namespace: opensearch.Namespace,
 		},
 		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
 	}
 
 	// Set owner
@@ -387,18 +417,23 @@ func (r *OpensearchTransportTlsReconciler) generateSecret(opensearch *opensearch
 			secret.Data[fmt.Sprintf("%s.pfx", nodeName)] = pkcs12
 	}
 
+	if err = setTruststore(secret, remoteCAs); err != nil {
+		return nil, err
+	}
+
 	return secret, nil
 }
 
 // renewSecret regenerate all certificate needed for transport layout
 // It add all valid ca certificate previously used to rolling upgrade node
-func (r *OpensearchTransportTlsReconciler) renewSecret(opensearch *opensearchapi.Opensearch, oldPfx []byte) (secret *corev1.Secret, err error) {
+func (r *OpensearchTransportTlsReconciler) renewSecret(opensearch *opensearchapi.Opensearch, oldPfx []byte, remoteCAs []byte) (secret *corev1.Secret, err error) {
 	secret = &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      opensearch.GetSecretNameForTlsTransport(),
 			Namespace: opensearch.Namespace,
 		},
 		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
 	}
 
 	// Set owner
@@ -458,5 +493,148 @@ func (r *OpensearchTransportTlsReconciler) renewSecret(opensearch *opensearchapi
 			secret.Data[fmt.Sprintf("%s.pfx", nodeName)] = pkcs12
 	}
 
+	if err = setTruststore(secret, remoteCAs); err != nil {
+		return nil, err
+	}
+
 	return secret, nil
 }
+
+// readRemoteCAs permit to read the transport CA of remote clusters, and of the clusters managed by operator that use this cluster as remote cluster
+// It return the CA as PEM bundle. The CA of remote cluster not yet created is skipped
+func (r *OpensearchTransportTlsReconciler) readRemoteCAs(ctx context.Context, o *opensearchapi.Opensearch) (remoteCAs []byte, err error) {
+	var buf bytes.Buffer
+	var s client.Object
+
+	for _, remoteCluster := range o.Spec.RemoteClusters {
+		switch {
+		case remoteCluster.OpensearchRef != nil:
+			remoteOpensearch := &opensearchapi.Opensearch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      remoteCluster.OpensearchRef.Name,
+					Namespace: o.GetRemoteOpensearchNamespace(&remoteCluster),
+				},
+			}
+			s, err = getResource(ctx, r.Client, remoteOpensearch.Namespace, remoteOpensearch.GetSecretNameForTlsTransport(), &corev1.Secret{})
+			if err != nil {
+				return nil, err
+			}
+			if s == nil {
+				r.log.Infof("Wait transport CA of remote cluster %s", remoteCluster.Name)
+				continue
+			}
+		case remoteCluster.CaSecretRef != "":
+			s, err = getResource(ctx, r.Client, o.Namespace, remoteCluster.CaSecretRef, &corev1.Secret{})
+			if err != nil {
+				return nil, err
+			}
+			if s == nil || len(s.(*corev1.Secret).Data["ca.crt"]) == 0 {
+				return nil, errors.Errorf("Secret %s with key ca.crt not found for remote cluster %s", remoteCluster.CaSecretRef, remoteCluster.Name)
+			}
+		default:
+			continue
+		}
+		buf.Write(bytes.TrimSpace(s.(*corev1.Secret).Data["ca.crt"]))
+		buf.WriteString("\n")
+	}
+
+	// The clusters that use this cluster as remote cluster need to be trusted too
+	opensearchList := &opensearchapi.OpensearchList{}
+	if err = r.Client.List(ctx, opensearchList); err != nil {
+		return nil, errors.Wrap(err, "Error when list Opensearch clusters")
+	}
+	sort.Slice(opensearchList.Items, func(i, j int) bool {
+		return opensearchList.Items[i].Namespace+"/"+opensearchList.Items[i].Name < opensearchList.Items[j].Namespace+"/"+opensearchList.Items[j].Name
+	})
+	for _, item := range opensearchList.Items {
+		if !o.IsRemoteClusterOf(&item) {
+			continue
+		}
+		s, err = getResource(ctx, r.Client, item.Namespace, item.GetSecretNameForTlsTransport(), &corev1.Secret{})
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			continue
+		}
+		buf.Write(bytes.TrimSpace(s.(*corev1.Secret).Data["ca.crt"]))
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// watchRemoteTransportSecret permit to reconcile the clusters that trust the transport CA stored on secret
+// It's the clusters linked as remote cluster with the owner of transport secret, and the clusters that use the secret as CA of external remote cluster
+func watchRemoteTransportSecret(c client.Client) handler.MapFunc {
+	return func(a client.Object) []reconcile.Request {
+		requests := make([]reconcile.Request, 0)
+
+		opensearchList := &opensearchapi.OpensearchList{}
+		if err := c.List(context.Background(), opensearchList); err != nil {
+			logrus.Errorf("Error when list Opensearch clusters to watch secret %s: %s", a.GetName(), err.Error())
+			return requests
+		}
+
+		var owner *opensearchapi.Opensearch
+		for i, item := range opensearchList.Items {
+			if item.Namespace == a.GetNamespace() && item.GetSecretNameForTlsTransport() == a.GetName() {
+				owner = &opensearchList.Items[i]
+				break
+			}
+		}
+
+		for _, item := range opensearchList.Items {
+			isLinked := owner != nil && (owner.IsRemoteClusterOf(&item) || item.IsRemoteClusterOf(owner))
+			if !isLinked && item.Namespace == a.GetNamespace() {
+				for _, remoteCluster := range item.Spec.RemoteClusters {
+					if remoteCluster.OpensearchRef == nil && remoteCluster.CaSecretRef == a.GetName() {
+						isLinked = true
+						break
+					}
+				}
+			}
+			if isLinked {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: item.Namespace, Name: item.Name}})
+			}
+		}
+
+		return requests
+	}
+}
+
+// setTruststore permit to generate the transport truststore with the CA of cluster and the CA of remote clusters
+// The CA of remote clusters are kept on key remote-ca.crt to detect change
+func setTruststore(secret *corev1.Secret, remoteCAs []byte) (err error) {
+	certificates := make([]*x509.Certificate, 0, 1)
+	rest := append(append(append([]byte{}, secret.Data["ca.crt"]...), '\n'), remoteCAs...)
+	var block *pem.Block
+	for {
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		crt, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.Wrap(err, "Error when parse CA certificate")
+		}
+		certificates = append(certificates, crt)
+	}
+
+	truststore, err := pkcs12.EncodeTrustStore(rand.Reader, certificates, "")
+	if err != nil {
+		return errors.Wrap(err, "Error when generate Pkcs12 truststore")
+	}
+	secret.Data["truststore.pfx"] = truststore
+
+	if len(remoteCAs) > 0 {
+		secret.Data["remote-ca.crt"] = remoteCAs
+	} else {
+		delete(secret.Data, "remote-ca.crt")
+	}
+
+	return nil
+}
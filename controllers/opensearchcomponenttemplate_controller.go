@@ -0,0 +1,257 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchComponentTemplateFinalizer = "componenttemplate.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchComponentTemplateCondition = "OpensearchComponentTemplate"
+)
+
+// OpensearchComponentTemplateReconciler reconciles a OpensearchComponentTemplate object
+type OpensearchComponentTemplateReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchComponentTemplateReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchComponentTemplateReconciler {
+
+	r := &OpensearchComponentTemplateReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchComponentTemplate",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchcomponenttemplates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchcomponenttemplates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchcomponenttemplates/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create, update and delete the component template on Opensearch
+// It requeue periodically to detect drift on Opensearch
+func (r *OpensearchComponentTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchComponentTemplateFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	componentTemplate := &opensearchapi.OpensearchComponentTemplate{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, componentTemplate, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchComponentTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchComponentTemplate{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchComponentTemplateReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(componentTemplate.Status.Conditions, OpensearchComponentTemplateCondition) == nil {
+		condition.SetStatusCondition(&componentTemplate.Status.Conditions, metav1.Condition{
+			Type:   OpensearchComponentTemplateCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, componentTemplate.Namespace, componentTemplate.Spec.OpensearchRef, !componentTemplate.DeletionTimestamp.IsZero())
+}
+
+// Read the current component template
+func (r *OpensearchComponentTemplateReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentComponentTemplate, err := m.client.GetComponentTemplate(ctx, componentTemplate.GetTemplateName())
+	if err != nil {
+		return res, err
+	}
+	data["currentComponentTemplate"] = currentComponentTemplate
+
+	return res, nil
+}
+
+// Create permit to create the component template
+func (r *OpensearchComponentTemplateReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedComponentTemplate")
+	if err != nil {
+		return res, err
+	}
+	expectedComponentTemplate := d.(*opensearch.ComponentTemplate)
+
+	if err = m.client.PutComponentTemplate(ctx, componentTemplate.GetTemplateName(), expectedComponentTemplate); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "ComponentTemplate", "Component template %s created", componentTemplate.GetTemplateName())
+
+	return res, nil
+}
+
+// Update permit to update the component template
+func (r *OpensearchComponentTemplateReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedComponentTemplate")
+	if err != nil {
+		return res, err
+	}
+	expectedComponentTemplate := d.(*opensearch.ComponentTemplate)
+
+	if err = m.client.PutComponentTemplate(ctx, componentTemplate.GetTemplateName(), expectedComponentTemplate); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "ComponentTemplate", "Component template %s updated", componentTemplate.GetTemplateName())
+
+	return res, nil
+}
+
+// Delete permit to delete the component template
+func (r *OpensearchComponentTemplateReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if err = m.client.DeleteComponentTemplate(ctx, componentTemplate.GetTemplateName()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "ComponentTemplate", "Component template %s deleted", componentTemplate.GetTemplateName())
+
+	return nil
+}
+
+// Diff permit to compare the expected component template with the current component template
+func (r *OpensearchComponentTemplateReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+
+	d, err := helper.Get(data, "currentComponentTemplate")
+	if err != nil {
+		return diff, err
+	}
+	currentComponentTemplate := d.(*opensearch.ComponentTemplate)
+
+	expectedComponentTemplate, err := componentTemplate.GenerateComponentTemplate()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate component template")
+	}
+	data["expectedComponentTemplate"] = expectedComponentTemplate
+
+	if currentComponentTemplate == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("Component template %s not exist", componentTemplate.GetTemplateName())
+		return diff, nil
+	}
+
+	if d := localhelper.Diff(*expectedComponentTemplate, *currentComponentTemplate); d != "" {
+		diff.NeedUpdate = true
+		diff.Diff = d
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchComponentTemplateReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&componentTemplate.Status.Conditions, metav1.Condition{
+		Type:    OpensearchComponentTemplateCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchComponentTemplateReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	componentTemplate := resource.(*opensearchapi.OpensearchComponentTemplate)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Component template successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(componentTemplate.Status.Conditions, OpensearchComponentTemplateCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&componentTemplate.Status.Conditions, metav1.Condition{
+			Type:    OpensearchComponentTemplateCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Component template up to date",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchDashboardsFinalizer = "dashboards.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchDashboardsCondition = "OpensearchDashboards"
+)
+
+// OpensearchDashboardsReconciler reconciles a OpensearchDashboards object
+type OpensearchDashboardsReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchDashboardsReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchDashboardsReconciler {
+
+	r := &OpensearchDashboardsReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchDashboards",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchdashboards,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchdashboards/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchdashboards/finalizers,verbs=update
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchrolemappings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to deploy Dashboards connected on Opensearch cluster
+// It requeue periodically to detect drift on deployed resources
+func (r *OpensearchDashboardsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchDashboardsFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	dashboards := &opensearchapi.OpensearchDashboards{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, dashboards, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchDashboardsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchDashboards{}).
+		Owns(&opensearchapi.OpensearchUser{}).
+		Owns(&opensearchapi.OpensearchRoleMapping{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&appv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&networkingv1.Ingress{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to read the Opensearch cluster
+func (r *OpensearchDashboardsReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	dashboards := resource.(*opensearchapi.OpensearchDashboards)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(dashboards.Status.Conditions, OpensearchDashboardsCondition) == nil {
+		condition.SetStatusCondition(&dashboards.Status.Conditions, metav1.Condition{
+			Type:   OpensearchDashboardsCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, dashboards.Namespace, dashboards.Spec.OpensearchRef, !dashboards.DeletionTimestamp.IsZero())
+}
+
+// Read the current resources deployed for Dashboards
+func (r *OpensearchDashboardsReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	dashboards := resource.(*opensearchapi.OpensearchDashboards)
+	m := meta.(*opensearchMeta)
+
+	// The resources are removed by garbage collector
+	if m == nil || !dashboards.DeletionTimestamp.IsZero() {
+		return res, nil
+	}
+
+	if data["currentUser"], err = getResource(ctx, r.Client, dashboards.Namespace, dashboards.GetServerUserName(), &opensearchapi.OpensearchUser{}); err != nil {
+		return res, err
+	}
+	// The role mapping of kibana_server is shared by all Dashboards of the cluster
+	dashboardsList := &opensearchapi.OpensearchDashboardsList{}
+	if err = r.Client.List(ctx, dashboardsList, client.InNamespace(dashboards.Namespace)); err != nil {
+		return res, errors.Wrapf(err, "Error when list Dashboards on namespace %s", dashboards.Namespace)
+	}
+	data["dashboardsList"] = dashboardsList.Items
+	if data["currentRoleMapping"], err = getResource(ctx, r.Client, dashboards.Namespace, dashboards.GetServerUserName(), &opensearchapi.OpensearchRoleMapping{}); err != nil {
+		return res, err
+	}
+	if data["currentConfigMap"], err = getResource(ctx, r.Client, dashboards.Namespace, dashboards.GetConfigMapName(), &corev1.ConfigMap{}); err != nil {
+		return res, err
+	}
+	if data["currentDeployment"], err = getResource(ctx, r.Client, dashboards.Namespace, dashboards.GetDeploymentName(), &appv1.Deployment{}); err != nil {
+		return res, err
+	}
+	if data["currentService"], err = getResource(ctx, r.Client, dashboards.Namespace, dashboards.GetServiceName(), &corev1.Service{}); err != nil {
+		return res, err
+	}
+	if data["currentIngress"], err = getResource(ctx, r.Client, dashboards.Namespace, dashboards.GetIngressName(), &networkingv1.Ingress{}); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Create permit to deploy Dashboards
+func (r *OpensearchDashboardsReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	dashboards := resource.(*opensearchapi.OpensearchDashboards)
+
+	d, err := helper.Get(data, "compareResources")
+	if err != nil {
+		return res, err
+	}
+	if err = applyResources(ctx, r.Client, d.([]CompareResource)); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(meta.(*opensearchMeta).opensearch, corev1.EventTypeNormal, "Dashboards", "Dashboards %s created", dashboards.Name)
+
+	return res, nil
+}
+
+// Update permit to update Dashboards
+func (r *OpensearchDashboardsReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	dashboards := resource.(*opensearchapi.OpensearchDashboards)
+
+	d, err := helper.Get(data, "compareResources")
+	if err != nil {
+		return res, err
+	}
+	if err = applyResources(ctx, r.Client, d.([]CompareResource)); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(meta.(*opensearchMeta).opensearch, corev1.EventTypeNormal, "Dashboards", "Dashboards %s updated", dashboards.Name)
+
+	return res, nil
+}
+
+// Delete do nothing
+// The resources are removed by garbage collector, and the server user and its role mapping by their controllers
+func (r *OpensearchDashboardsReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compare the expected resources with the current resources
+func (r *OpensearchDashboardsReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	dashboards := resource.(*opensearchapi.OpensearchDashboards)
+	m := meta.(*opensearchMeta)
+
+	// Generate expected resources
+	expectedUser := dashboards.GenerateServerUser()
+	d, err := helper.Get(data, "dashboardsList")
+	if err != nil {
+		return diff, err
+	}
+	expectedRoleMapping := dashboards.GenerateServerRoleMapping(d.([]opensearchapi.OpensearchDashboards))
+	expectedConfigMap, err := dashboards.GenerateConfigMap(m.opensearch)
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate configMap")
+	}
+	expectedDeployment, err := dashboards.GenerateDeployment(m.opensearch)
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate deployment")
+	}
+	expectedService, err := dashboards.GenerateService()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate service")
+	}
+	expectedIngress, err := dashboards.GenerateIngress()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate ingress")
+	}
+
+	expectedResources := map[string]client.Object{
+		"currentUser":        expectedUser,
+		"currentRoleMapping": expectedRoleMapping,
+		"currentConfigMap":   expectedConfigMap,
+		"currentDeployment":  expectedDeployment,
+		"currentService":     expectedService,
+		"currentIngress":     nil,
+	}
+	if expectedIngress != nil {
+		expectedResources["currentIngress"] = expectedIngress
+	}
+
+	compares, diff, err := compareResources(dashboards, r.Scheme, data, expectedResources)
+	if err != nil {
+		return diff, err
+	}
+	data["compareResources"] = compares
+
+	if len(compares) > 0 {
+		if data["currentDeployment"] == nil {
+			diff.NeedCreate = true
+		} else {
+			diff.NeedUpdate = true
+		}
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchDashboardsReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	dashboards := resource.(*opensearchapi.OpensearchDashboards)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&dashboards.Status.Conditions, metav1.Condition{
+		Type:    OpensearchDashboardsCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition and the url to access on Dashboards
+func (r *OpensearchDashboardsReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	dashboards := resource.(*opensearchapi.OpensearchDashboards)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Dashboards successfully updated:\n%s", diff.Diff)
+	}
+
+	dashboards.Status.Url = dashboards.GetUrl()
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(dashboards.Status.Conditions, OpensearchDashboardsCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&dashboards.Status.Conditions, metav1.Condition{
+			Type:    OpensearchDashboardsCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: fmt.Sprintf("Dashboards %s up to date", dashboards.GetDeploymentName()),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchDataPrepperFinalizer = "dataprepper.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchDataPrepperCondition = "OpensearchDataPrepper"
+)
+
+// OpensearchDataPrepperReconciler reconciles a OpensearchDataPrepper object
+type OpensearchDataPrepperReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchDataPrepperReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchDataPrepperReconciler {
+
+	r := &OpensearchDataPrepperReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchDataPrepper",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchdatapreppers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchdatapreppers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchdatapreppers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchroles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchrolemappings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to deploy Data Prepper connected on Opensearch cluster
+// It requeue periodically to detect drift on deployed resources
+func (r *OpensearchDataPrepperReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchDataPrepperFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	dataPrepper := &opensearchapi.OpensearchDataPrepper{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, dataPrepper, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchDataPrepperReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchDataPrepper{}).
+		Owns(&opensearchapi.OpensearchUser{}).
+		Owns(&opensearchapi.OpensearchRole{}).
+		Owns(&opensearchapi.OpensearchRoleMapping{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Owns(&appv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to read the Opensearch cluster
+func (r *OpensearchDataPrepperReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	dataPrepper := resource.(*opensearchapi.OpensearchDataPrepper)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(dataPrepper.Status.Conditions, OpensearchDataPrepperCondition) == nil {
+		condition.SetStatusCondition(&dataPrepper.Status.Conditions, metav1.Condition{
+			Type:   OpensearchDataPrepperCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, dataPrepper.Namespace, dataPrepper.Spec.OpensearchRef, !dataPrepper.DeletionTimestamp.IsZero())
+}
+
+// Read the current resources deployed for Data Prepper and the credentials of its user
+func (r *OpensearchDataPrepperReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	dataPrepper := resource.(*opensearchapi.OpensearchDataPrepper)
+	m := meta.(*opensearchMeta)
+
+	// The resources are removed by garbage collector
+	if m == nil || !dataPrepper.DeletionTimestamp.IsZero() {
+		return res, nil
+	}
+
+	if data["currentUser"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetUserName(), &opensearchapi.OpensearchUser{}); err != nil {
+		return res, err
+	}
+	if data["currentRole"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetUserName(), &opensearchapi.OpensearchRole{}); err != nil {
+		return res, err
+	}
+	if data["currentRoleMapping"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetUserName(), &opensearchapi.OpensearchRoleMapping{}); err != nil {
+		return res, err
+	}
+	if data["currentConfigMap"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetConfigMapName(), &corev1.ConfigMap{}); err != nil {
+		return res, err
+	}
+	if data["currentPipelines"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetSecretNameForPipelines(), &corev1.Secret{}); err != nil {
+		return res, err
+	}
+	if data["currentDeployment"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetDeploymentName(), &appv1.Deployment{}); err != nil {
+		return res, err
+	}
+	if data["currentService"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetServiceName(), &corev1.Service{}); err != nil {
+		return res, err
+	}
+
+	// The credentials are generated by the OpensearchUser controller
+	if data["credentials"], err = getResource(ctx, r.Client, dataPrepper.Namespace, dataPrepper.GetSecretNameForUser(), &corev1.Secret{}); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Create permit to deploy Data Prepper
+func (r *OpensearchDataPrepperReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	dataPrepper := resource.(*opensearchapi.OpensearchDataPrepper)
+
+	if res, err = r.apply(ctx, data); err != nil || res.RequeueAfter > 0 {
+		return res, err
+	}
+
+	r.recorder.Eventf(meta.(*opensearchMeta).opensearch, corev1.EventTypeNormal, "DataPrepper", "Data Prepper %s created", dataPrepper.Name)
+
+	return res, nil
+}
+
+// Update permit to update Data Prepper
+func (r *OpensearchDataPrepperReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	dataPrepper := resource.(*opensearchapi.OpensearchDataPrepper)
+
+	if res, err = r.apply(ctx, data); err != nil || res.RequeueAfter > 0 {
+		return res, err
+	}
+
+	r.recorder.Eventf(meta.(*opensearchMeta).opensearch, corev1.EventTypeNormal, "DataPrepper", "Data Prepper %s updated", dataPrepper.Name)
+
+	return res, nil
+}
+
+// Delete do nothing
+// The resources are removed by garbage collector, and the user and its role by their controllers
+func (r *OpensearchDataPrepperReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	return nil
+}
+
+// Diff permit to compare the expected resources with the current resources
+// The pipelines and the deployment need the credentials, so they are only compared once the user secret is generated
+func (r *OpensearchDataPrepperReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	dataPrepper := resource.(*opensearchapi.OpensearchDataPrepper)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "credentials")
+	if err != nil {
+		return diff, err
+	}
+	var credentials *corev1.Secret
+	if d != nil {
+		credentials = d.(*corev1.Secret)
+	}
+
+	// Generate expected resources
+	expectedRole, err := dataPrepper.GenerateRole()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate role")
+	}
+	expectedConfigMap, err := dataPrepper.GenerateConfigMap()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate configMap")
+	}
+	expectedService, err := dataPrepper.GenerateService()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate service")
+	}
+	expectedResources := map[string]client.Object{
+		"currentUser":        dataPrepper.GenerateUser(),
+		"currentRole":        expectedRole,
+		"currentRoleMapping": dataPrepper.GenerateRoleMapping(),
+		"currentConfigMap":   expectedConfigMap,
+		"currentService":     expectedService,
+	}
+
+	if credentials != nil {
+		expectedPipelines, err := dataPrepper.GeneratePipelinesSecret(m.opensearch, credentials)
+		if err != nil {
+			return diff, errors.Wrap(err, "Error when generate pipelines secret")
+		}
+		expectedDeployment, err := dataPrepper.GenerateDeployment(m.opensearch, credentials)
+		if err != nil {
+			return diff, errors.Wrap(err, "Error when generate deployment")
+		}
+		expectedResources["currentPipelines"] = expectedPipelines
+		expectedResources["currentDeployment"] = expectedDeployment
+	}
+
+	compares, diff, err := compareResources(dataPrepper, r.Scheme, data, expectedResources)
+	if err != nil {
+		return diff, err
+	}
+	data["compareResources"] = compares
+
+	if credentials == nil {
+		diff.Diff += fmt.Sprintf("Wait secret %s with credentials of user %s\n", dataPrepper.GetSecretNameForUser(), dataPrepper.GetUserName())
+	}
+
+	if len(compares) > 0 || credentials == nil {
+		if data["currentDeployment"] == nil {
+			diff.NeedCreate = true
+		} else {
+			diff.NeedUpdate = true
+		}
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchDataPrepperReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	dataPrepper := resource.(*opensearchapi.OpensearchDataPrepper)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&dataPrepper.Status.Conditions, metav1.Condition{
+		Type:    OpensearchDataPrepperCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchDataPrepperReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	dataPrepper := resource.(*opensearchapi.OpensearchDataPrepper)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Data Prepper successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(dataPrepper.Status.Conditions, OpensearchDataPrepperCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&dataPrepper.Status.Conditions, metav1.Condition{
+			Type:    OpensearchDataPrepperCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: fmt.Sprintf("Data Prepper %s up to date", dataPrepper.GetDeploymentName()),
+		})
+	}
+
+	return nil
+}
+
+// apply permit to create, update or delete the resources that differ
+// It requeue when the credentials are not yet generated, so the pipelines and the deployment can be created
+func (r *OpensearchDataPrepperReconciler) apply(ctx context.Context, data map[string]any) (res ctrl.Result, err error) {
+	d, err := helper.Get(data, "compareResources")
+	if err != nil {
+		return res, err
+	}
+	if err = applyResources(ctx, r.Client, d.([]CompareResource)); err != nil {
+		return res, err
+	}
+
+	if data["credentials"] == nil {
+		r.log.Infof("Wait credentials of Data Prepper user")
+		res.RequeueAfter = 10 * time.Second
+	}
+
+	return res, nil
+}
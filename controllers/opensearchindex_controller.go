@@ -0,0 +1,280 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchIndexFinalizer = "index.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchIndexCondition = "OpensearchIndex"
+)
+
+// OpensearchIndexReconciler reconciles a OpensearchIndex object
+type OpensearchIndexReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchIndexReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchIndexReconciler {
+
+	r := &OpensearchIndexReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchIndex",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindices,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindices/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindices/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create the index and to update its dynamic settings on Opensearch
+// It requeue periodically to detect drift on Opensearch
+func (r *OpensearchIndexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchIndexFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	index := &opensearchapi.OpensearchIndex{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, index, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchIndexReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchIndex{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchIndexReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(index.Status.Conditions, OpensearchIndexCondition) == nil {
+		condition.SetStatusCondition(&index.Status.Conditions, metav1.Condition{
+			Type:   OpensearchIndexCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, index.Namespace, index.Spec.OpensearchRef, !index.DeletionTimestamp.IsZero())
+}
+
+// Read the current index
+func (r *OpensearchIndexReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentIndex, err := m.client.GetIndex(ctx, index.GetIndexName())
+	if err != nil {
+		return res, err
+	}
+	data["currentIndex"] = currentIndex
+
+	return res, nil
+}
+
+// Create permit to create the index with its settings, mappings and aliases
+func (r *OpensearchIndexReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedIndex")
+	if err != nil {
+		return res, err
+	}
+	expectedIndex := d.(*opensearch.Index)
+
+	if err = m.client.CreateIndex(ctx, index.GetIndexName(), expectedIndex); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "Index", "Index %s created", index.GetIndexName())
+
+	return res, nil
+}
+
+// Update permit to update the dynamic settings of index
+func (r *OpensearchIndexReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "settingsToUpdate")
+	if err != nil {
+		return res, err
+	}
+	settingsToUpdate := d.(map[string]any)
+
+	if err = m.client.UpdateIndexSettings(ctx, index.GetIndexName(), settingsToUpdate); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "Index", "Settings of index %s updated", index.GetIndexName())
+
+	return res, nil
+}
+
+// Delete permit to delete the index only if deletion policy is Delete
+// By default, the index and its data are kept on Opensearch
+func (r *OpensearchIndexReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if !index.IsDeletionAllowed() {
+		r.log.Infof("Keep index %s on Opensearch because of deletion policy", index.GetIndexName())
+		return nil
+	}
+
+	if err = m.client.DeleteIndex(ctx, index.GetIndexName()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "Index", "Index %s deleted", index.GetIndexName())
+
+	return nil
+}
+
+// Diff permit to compare the expected settings with the current settings of index
+// Only the settings provided on resource are compared, and static settings can't be changed
+// The mappings and aliases are only set when create index, because rollover move the write alias
+func (r *OpensearchIndexReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+
+	d, err := helper.Get(data, "currentIndex")
+	if err != nil {
+		return diff, err
+	}
+	currentIndex := d.(*opensearch.Index)
+
+	expectedIndex, err := index.GenerateIndex()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate index")
+	}
+	data["expectedIndex"] = expectedIndex
+
+	if currentIndex == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("Index %s not exist", index.GetIndexName())
+		return diff, nil
+	}
+
+	currentSettings := map[string]any{}
+	settingsToUpdate := map[string]any{}
+	for key, value := range expectedIndex.Settings {
+		if reflect.DeepEqual(value, currentIndex.Settings[key]) {
+			continue
+		}
+		if opensearch.IsStaticSetting(key) {
+			return diff, errors.Errorf("Setting %s is static and can't be changed on index %s", key, index.GetIndexName())
+		}
+		settingsToUpdate[key] = value
+		currentSettings[key] = currentIndex.Settings[key]
+	}
+	data["settingsToUpdate"] = settingsToUpdate
+
+	if len(settingsToUpdate) > 0 {
+		diff.NeedUpdate = true
+		diff.Diff = localhelper.Diff(settingsToUpdate, currentSettings)
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchIndexReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&index.Status.Conditions, metav1.Condition{
+		Type:    OpensearchIndexCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchIndexReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	index := resource.(*opensearchapi.OpensearchIndex)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Index successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(index.Status.Conditions, OpensearchIndexCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&index.Status.Conditions, metav1.Condition{
+			Type:    OpensearchIndexCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Index up to date",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchIsmPolicyFinalizer                  = "ismpolicy.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchIndexStateManagementPolicyCondition = "OpensearchIndexStateManagementPolicy"
+)
+
+// OpensearchIndexStateManagementPolicyReconciler reconciles a OpensearchIndexStateManagementPolicy object
+type OpensearchIndexStateManagementPolicyReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchIndexStateManagementPolicyReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchIndexStateManagementPolicyReconciler {
+
+	r := &OpensearchIndexStateManagementPolicyReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchIndexStateManagementPolicy",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindexstatemanagementpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindexstatemanagementpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindexstatemanagementpolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create, update and delete the ISM policy on Opensearch
+// It requeue periodically to detect drift on Opensearch
+func (r *OpensearchIndexStateManagementPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchIsmPolicyFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	ismPolicy := &opensearchapi.OpensearchIndexStateManagementPolicy{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, ismPolicy, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchIndexStateManagementPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchIndexStateManagementPolicy{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchIndexStateManagementPolicyReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(ismPolicy.Status.Conditions, OpensearchIndexStateManagementPolicyCondition) == nil {
+		condition.SetStatusCondition(&ismPolicy.Status.Conditions, metav1.Condition{
+			Type:   OpensearchIndexStateManagementPolicyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, ismPolicy.Namespace, ismPolicy.Spec.OpensearchRef, !ismPolicy.DeletionTimestamp.IsZero())
+}
+
+// Read the current ISM policy
+func (r *OpensearchIndexStateManagementPolicyReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentPolicy, err := m.client.GetIsmPolicy(ctx, ismPolicy.GetPolicyName())
+	if err != nil {
+		return res, err
+	}
+	data["currentPolicy"] = currentPolicy
+
+	return res, nil
+}
+
+// Create permit to create the ISM policy
+func (r *OpensearchIndexStateManagementPolicyReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedPolicy")
+	if err != nil {
+		return res, err
+	}
+	expectedPolicy := d.(*opensearch.IsmPolicy)
+
+	if err = m.client.CreateIsmPolicy(ctx, ismPolicy.GetPolicyName(), expectedPolicy); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "IsmPolicy", "ISM policy %s created", ismPolicy.GetPolicyName())
+
+	return res, nil
+}
+
+// Update permit to update the ISM policy
+// When reapplyOnChange is enabled, the new version is applied on indices already managed by the policy
+func (r *OpensearchIndexStateManagementPolicyReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+	m := meta.(*opensearchMeta)
+	var d any
+
+	d, err = helper.Get(data, "expectedPolicy")
+	if err != nil {
+		return res, err
+	}
+	expectedPolicy := d.(*opensearch.IsmPolicy)
+
+	d, err = helper.Get(data, "currentPolicy")
+	if err != nil {
+		return res, err
+	}
+	currentPolicy := d.(*opensearch.IsmPolicyResponse)
+
+	if err = m.client.UpdateIsmPolicy(ctx, ismPolicy.GetPolicyName(), expectedPolicy, currentPolicy.SeqNo, currentPolicy.PrimaryTerm); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "IsmPolicy", "ISM policy %s updated", ismPolicy.GetPolicyName())
+
+	if ismPolicy.Spec.ReapplyOnChange {
+		indices, err := m.client.GetIsmManagedIndices(ctx, ismPolicy.GetPolicyName())
+		if err != nil {
+			return res, err
+		}
+		if err = m.client.ChangeIsmPolicy(ctx, ismPolicy.GetPolicyName(), indices); err != nil {
+			return res, err
+		}
+		ismPolicy.Status.ManagedIndices = int64(len(indices))
+		r.log.Infof("ISM policy %s reapplied on %d indices", ismPolicy.GetPolicyName(), len(indices))
+	}
+
+	return res, nil
+}
+
+// Delete permit to delete the ISM policy
+func (r *OpensearchIndexStateManagementPolicyReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if err = m.client.DeleteIsmPolicy(ctx, ismPolicy.GetPolicyName()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "IsmPolicy", "ISM policy %s deleted", ismPolicy.GetPolicyName())
+
+	return nil
+}
+
+// Diff permit to compare the expected ISM policy with the current ISM policy
+func (r *OpensearchIndexStateManagementPolicyReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+
+	d, err := helper.Get(data, "currentPolicy")
+	if err != nil {
+		return diff, err
+	}
+	currentPolicy := d.(*opensearch.IsmPolicyResponse)
+
+	expectedPolicy, err := ismPolicy.GenerateIsmPolicy()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate ISM policy")
+	}
+	data["expectedPolicy"] = expectedPolicy
+
+	if currentPolicy == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("ISM policy %s not exist", ismPolicy.GetPolicyName())
+		return diff, nil
+	}
+
+	if d := localhelper.Diff(*expectedPolicy, currentPolicy.Policy); d != "" {
+		diff.NeedUpdate = true
+		diff.Diff = d
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchIndexStateManagementPolicyReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&ismPolicy.Status.Conditions, metav1.Condition{
+		Type:    OpensearchIndexStateManagementPolicyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchIndexStateManagementPolicyReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	ismPolicy := resource.(*opensearchapi.OpensearchIndexStateManagementPolicy)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "ISM policy successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(ismPolicy.Status.Conditions, OpensearchIndexStateManagementPolicyCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&ismPolicy.Status.Conditions, metav1.Condition{
+			Type:    OpensearchIndexStateManagementPolicyCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "ISM policy up to date",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchIndexTemplateFinalizer = "indextemplate.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchIndexTemplateCondition = "OpensearchIndexTemplate"
+	PriorityConflictCondition        = "PriorityConflict"
+)
+
+// OpensearchIndexTemplateReconciler reconciles a OpensearchIndexTemplate object
+type OpensearchIndexTemplateReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchIndexTemplateReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchIndexTemplateReconciler {
+
+	r := &OpensearchIndexTemplateReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchIndexTemplate",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindextemplates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindextemplates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchindextemplates/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create, update and delete the index template on Opensearch
+// It requeue periodically to detect drift on Opensearch
+func (r *OpensearchIndexTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchIndexTemplateFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	indexTemplate := &opensearchapi.OpensearchIndexTemplate{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, indexTemplate, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchIndexTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchIndexTemplate{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchIndexTemplateReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(indexTemplate.Status.Conditions, OpensearchIndexTemplateCondition) == nil {
+		condition.SetStatusCondition(&indexTemplate.Status.Conditions, metav1.Condition{
+			Type:   OpensearchIndexTemplateCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, indexTemplate.Namespace, indexTemplate.Spec.OpensearchRef, !indexTemplate.DeletionTimestamp.IsZero())
+}
+
+// Read the current index template
+func (r *OpensearchIndexTemplateReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentIndexTemplate, err := m.client.GetIndexTemplate(ctx, indexTemplate.GetTemplateName())
+	if err != nil {
+		return res, err
+	}
+	data["currentIndexTemplate"] = currentIndexTemplate
+
+	return res, nil
+}
+
+// Create permit to create the index template
+func (r *OpensearchIndexTemplateReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedIndexTemplate")
+	if err != nil {
+		return res, err
+	}
+	expectedIndexTemplate := d.(*opensearch.IndexTemplate)
+
+	if err = m.client.PutIndexTemplate(ctx, indexTemplate.GetTemplateName(), expectedIndexTemplate); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "IndexTemplate", "Index template %s created", indexTemplate.GetTemplateName())
+
+	return res, nil
+}
+
+// Update permit to update the index template
+func (r *OpensearchIndexTemplateReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedIndexTemplate")
+	if err != nil {
+		return res, err
+	}
+	expectedIndexTemplate := d.(*opensearch.IndexTemplate)
+
+	if err = m.client.PutIndexTemplate(ctx, indexTemplate.GetTemplateName(), expectedIndexTemplate); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "IndexTemplate", "Index template %s updated", indexTemplate.GetTemplateName())
+
+	return res, nil
+}
+
+// Delete permit to delete the index template
+func (r *OpensearchIndexTemplateReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if err = m.client.DeleteIndexTemplate(ctx, indexTemplate.GetTemplateName()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "IndexTemplate", "Index template %s deleted", indexTemplate.GetTemplateName())
+
+	return nil
+}
+
+// Diff permit to compare the expected index template with the current index template
+func (r *OpensearchIndexTemplateReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+
+	d, err := helper.Get(data, "currentIndexTemplate")
+	if err != nil {
+		return diff, err
+	}
+	currentIndexTemplate := d.(*opensearch.IndexTemplate)
+
+	expectedIndexTemplate, err := indexTemplate.GenerateIndexTemplate()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate index template")
+	}
+	data["expectedIndexTemplate"] = expectedIndexTemplate
+
+	if currentIndexTemplate == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("Index template %s not exist", indexTemplate.GetTemplateName())
+		return diff, nil
+	}
+
+	// Opensearch API return empty composed_of instead of nil
+	if d := localhelper.Diff(*expectedIndexTemplate, *currentIndexTemplate, cmpopts.EquateEmpty()); d != "" {
+		diff.NeedUpdate = true
+		diff.Diff = d
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchIndexTemplateReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&indexTemplate.Status.Conditions, metav1.Condition{
+		Type:    OpensearchIndexTemplateCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Another template match the same index patterns with the same priority
+	if opensearch.IsPriorityConflict(err) {
+		condition.SetStatusCondition(&indexTemplate.Status.Conditions, metav1.Condition{
+			Type:    PriorityConflictCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Conflict",
+			Message: err.Error(),
+		})
+	}
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+// The priority conflict is resolved when the template is applied
+func (r *OpensearchIndexTemplateReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	indexTemplate := resource.(*opensearchapi.OpensearchIndexTemplate)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Index template successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(indexTemplate.Status.Conditions, OpensearchIndexTemplateCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&indexTemplate.Status.Conditions, metav1.Condition{
+			Type:    OpensearchIndexTemplateCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Index template up to date",
+		})
+	}
+
+	if !condition.IsStatusConditionPresentAndEqual(indexTemplate.Status.Conditions, PriorityConflictCondition, metav1.ConditionFalse) {
+		condition.SetStatusCondition(&indexTemplate.Status.Conditions, metav1.Condition{
+			Type:    PriorityConflictCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionFalse,
+			Message: "No priority conflict with other index templates",
+		})
+	}
+
+	return nil
+}
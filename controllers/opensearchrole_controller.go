@@ -0,0 +1,264 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchRoleFinalizer = "role.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchRoleCondition = "OpensearchRole"
+)
+
+// OpensearchRoleReconciler reconciles a OpensearchRole object
+type OpensearchRoleReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchRoleReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchRoleReconciler {
+
+	r := &OpensearchRoleReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchRole",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchroles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchroles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchroles/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create, update and delete the role on Opensearch
+// It requeue periodically to detect drift on Opensearch
+func (r *OpensearchRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchRoleFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	role := &opensearchapi.OpensearchRole{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, role, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchRole{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchRoleReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(role.Status.Conditions, OpensearchRoleCondition) == nil {
+		condition.SetStatusCondition(&role.Status.Conditions, metav1.Condition{
+			Type:   OpensearchRoleCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, role.Namespace, role.Spec.OpensearchRef, !role.DeletionTimestamp.IsZero())
+}
+
+// Read the current role
+func (r *OpensearchRoleReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentRole, err := m.client.GetRole(ctx, role.GetRoleName())
+	if err != nil {
+		return res, err
+	}
+	data["currentRole"] = currentRole
+
+	return res, nil
+}
+
+// Create permit to create the role
+func (r *OpensearchRoleReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedRole")
+	if err != nil {
+		return res, err
+	}
+	expectedRole := d.(*opensearch.Role)
+
+	if err = m.client.PutRole(ctx, role.GetRoleName(), expectedRole); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "Role", "Role %s created", role.GetRoleName())
+
+	return res, nil
+}
+
+// Update permit to update the role
+func (r *OpensearchRoleReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedRole")
+	if err != nil {
+		return res, err
+	}
+	expectedRole := d.(*opensearch.Role)
+
+	if err = m.client.PutRole(ctx, role.GetRoleName(), expectedRole); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "Role", "Role %s updated", role.GetRoleName())
+
+	return res, nil
+}
+
+// Delete permit to delete the role
+func (r *OpensearchRoleReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if err = m.client.DeleteRole(ctx, role.GetRoleName()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "Role", "Role %s deleted", role.GetRoleName())
+
+	return nil
+}
+
+// Diff permit to compare the expected role with the current role
+// Reserved role can't be managed by operator
+func (r *OpensearchRoleReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+
+	d, err := helper.Get(data, "currentRole")
+	if err != nil {
+		return diff, err
+	}
+	currentRole := d.(*opensearch.Role)
+
+	expectedRole, err := role.GenerateRole()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate role")
+	}
+	data["expectedRole"] = expectedRole
+
+	if currentRole == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("Role %s not exist", role.GetRoleName())
+		return diff, nil
+	}
+
+	if currentRole.Reserved || currentRole.Hidden {
+		return diff, errors.Errorf("Role %s is reserved and can't be managed", role.GetRoleName())
+	}
+
+	// Security API return empty permissions instead of nil
+	if d := localhelper.Diff(*expectedRole, *currentRole, cmpopts.EquateEmpty()); d != "" {
+		diff.NeedUpdate = true
+		diff.Diff = d
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchRoleReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&role.Status.Conditions, metav1.Condition{
+		Type:    OpensearchRoleCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchRoleReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	role := resource.(*opensearchapi.OpensearchRole)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Role successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(role.Status.Conditions, OpensearchRoleCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&role.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRoleCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Role up to date",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchRoleMappingFinalizer = "rolemapping.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchRoleMappingCondition = "OpensearchRoleMapping"
+)
+
+// OpensearchRoleMappingReconciler reconciles a OpensearchRoleMapping object
+type OpensearchRoleMappingReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchRoleMappingReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchRoleMappingReconciler {
+
+	r := &OpensearchRoleMappingReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchRoleMapping",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchrolemappings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchrolemappings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchrolemappings/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create, update and delete the role mapping on Opensearch
+// It requeue periodically to detect drift on Opensearch
+func (r *OpensearchRoleMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchRoleMappingFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	roleMapping := &opensearchapi.OpensearchRoleMapping{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, roleMapping, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchRoleMappingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchRoleMapping{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchRoleMappingReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(roleMapping.Status.Conditions, OpensearchRoleMappingCondition) == nil {
+		condition.SetStatusCondition(&roleMapping.Status.Conditions, metav1.Condition{
+			Type:   OpensearchRoleMappingCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, roleMapping.Namespace, roleMapping.Spec.OpensearchRef, !roleMapping.DeletionTimestamp.IsZero())
+}
+
+// Read the current role mapping
+func (r *OpensearchRoleMappingReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentRoleMapping, err := m.client.GetRoleMapping(ctx, roleMapping.GetRoleName())
+	if err != nil {
+		return res, err
+	}
+	data["currentRoleMapping"] = currentRoleMapping
+
+	return res, nil
+}
+
+// Create permit to create the role mapping
+func (r *OpensearchRoleMappingReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedRoleMapping")
+	if err != nil {
+		return res, err
+	}
+	expectedRoleMapping := d.(*opensearch.RoleMapping)
+
+	if err = m.client.PutRoleMapping(ctx, roleMapping.GetRoleName(), expectedRoleMapping); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "RoleMapping", "Role mapping %s created", roleMapping.GetRoleName())
+
+	return res, nil
+}
+
+// Update permit to update the role mapping
+func (r *OpensearchRoleMappingReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedRoleMapping")
+	if err != nil {
+		return res, err
+	}
+	expectedRoleMapping := d.(*opensearch.RoleMapping)
+
+	if err = m.client.PutRoleMapping(ctx, roleMapping.GetRoleName(), expectedRoleMapping); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "RoleMapping", "Role mapping %s updated", roleMapping.GetRoleName())
+
+	return res, nil
+}
+
+// Delete permit to delete the role mapping
+func (r *OpensearchRoleMappingReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if err = m.client.DeleteRoleMapping(ctx, roleMapping.GetRoleName()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "RoleMapping", "Role mapping %s deleted", roleMapping.GetRoleName())
+
+	return nil
+}
+
+// Diff permit to compare the expected role mapping with the current role mapping
+// Reserved role mapping can't be managed by operator
+func (r *OpensearchRoleMappingReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+
+	d, err := helper.Get(data, "currentRoleMapping")
+	if err != nil {
+		return diff, err
+	}
+	currentRoleMapping := d.(*opensearch.RoleMapping)
+
+	expectedRoleMapping, err := roleMapping.GenerateRoleMapping()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate role mapping")
+	}
+	data["expectedRoleMapping"] = expectedRoleMapping
+
+	if currentRoleMapping == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("Role mapping %s not exist", roleMapping.GetRoleName())
+		return diff, nil
+	}
+
+	if currentRoleMapping.Reserved || currentRoleMapping.Hidden {
+		return diff, errors.Errorf("Role mapping %s is reserved and can't be managed", roleMapping.GetRoleName())
+	}
+
+	// Security API return empty users, backend roles and hosts instead of nil
+	if d := localhelper.Diff(*expectedRoleMapping, *currentRoleMapping, cmpopts.EquateEmpty()); d != "" {
+		diff.NeedUpdate = true
+		diff.Diff = d
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchRoleMappingReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&roleMapping.Status.Conditions, metav1.Condition{
+		Type:    OpensearchRoleMappingCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchRoleMappingReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	roleMapping := resource.(*opensearchapi.OpensearchRoleMapping)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Role mapping successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(roleMapping.Status.Conditions, OpensearchRoleMappingCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&roleMapping.Status.Conditions, metav1.Condition{
+			Type:    OpensearchRoleMappingCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Role mapping up to date",
+		})
+	}
+
+	return nil
+}
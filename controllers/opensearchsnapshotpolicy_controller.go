@@ -0,0 +1,336 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchSnapshotPolicyFinalizer = "snapshotpolicy.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchSnapshotPolicyCondition = "OpensearchSnapshotPolicy"
+	snapshotExecutionFailed           = "FAILED"
+	snapshotSuccess                   = "SUCCESS"
+)
+
+// OpensearchSnapshotPolicyReconciler reconciles a OpensearchSnapshotPolicy object
+type OpensearchSnapshotPolicyReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchSnapshotPolicyReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchSnapshotPolicyReconciler {
+
+	r := &OpensearchSnapshotPolicyReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchSnapshotPolicy",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchsnapshotpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchsnapshotpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchsnapshotpolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create, update and delete the snapshot management policy on Opensearch
+// It requeue periodically to refresh the last snapshots and the next run on status
+func (r *OpensearchSnapshotPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchSnapshotPolicyFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	snapshotPolicy := &opensearchapi.OpensearchSnapshotPolicy{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, snapshotPolicy, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchSnapshotPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchSnapshotPolicy{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchSnapshotPolicyReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(snapshotPolicy.Status.Conditions, OpensearchSnapshotPolicyCondition) == nil {
+		condition.SetStatusCondition(&snapshotPolicy.Status.Conditions, metav1.Condition{
+			Type:   OpensearchSnapshotPolicyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, snapshotPolicy.Namespace, snapshotPolicy.Spec.OpensearchRef, !snapshotPolicy.DeletionTimestamp.IsZero())
+}
+
+// Read the current policy, its state and the snapshots created by the policy
+func (r *OpensearchSnapshotPolicyReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentPolicy, err := m.client.GetSnapshotManagementPolicy(ctx, snapshotPolicy.GetPolicyName())
+	if err != nil {
+		return res, err
+	}
+	data["currentPolicy"] = currentPolicy
+
+	explain, err := m.client.ExplainSnapshotManagementPolicy(ctx, snapshotPolicy.GetPolicyName())
+	if err != nil {
+		return res, err
+	}
+	data["explain"] = explain
+
+	snapshots := []opensearch.Snapshot{}
+	if currentPolicy != nil {
+		snapshots, err = m.client.GetSnapshots(ctx, snapshotPolicy.Spec.Repository, fmt.Sprintf("%s-*", snapshotPolicy.GetPolicyName()))
+		if err != nil {
+			return res, err
+		}
+	}
+	data["snapshots"] = snapshots
+
+	return res, nil
+}
+
+// Create permit to create the policy
+func (r *OpensearchSnapshotPolicyReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+	m := meta.(*opensearchMeta)
+
+	d, err := helper.Get(data, "expectedPolicy")
+	if err != nil {
+		return res, err
+	}
+	expectedPolicy := d.(*opensearch.SnapshotManagementPolicy)
+
+	if err = m.client.CreateSnapshotManagementPolicy(ctx, snapshotPolicy.GetPolicyName(), expectedPolicy); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "SnapshotPolicy", "Snapshot policy %s created", snapshotPolicy.GetPolicyName())
+
+	return res, nil
+}
+
+// Update permit to update the policy
+func (r *OpensearchSnapshotPolicyReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+	m := meta.(*opensearchMeta)
+	var d any
+
+	d, err = helper.Get(data, "expectedPolicy")
+	if err != nil {
+		return res, err
+	}
+	expectedPolicy := d.(*opensearch.SnapshotManagementPolicy)
+
+	d, err = helper.Get(data, "currentPolicy")
+	if err != nil {
+		return res, err
+	}
+	currentPolicy := d.(*opensearch.SnapshotManagementPolicyResponse)
+
+	if err = m.client.UpdateSnapshotManagementPolicy(ctx, snapshotPolicy.GetPolicyName(), expectedPolicy, currentPolicy.SeqNo, currentPolicy.PrimaryTerm); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "SnapshotPolicy", "Snapshot policy %s updated", snapshotPolicy.GetPolicyName())
+
+	return res, nil
+}
+
+// Delete permit to delete the policy
+// Snapshots are kept on repository
+func (r *OpensearchSnapshotPolicyReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if err = m.client.DeleteSnapshotManagementPolicy(ctx, snapshotPolicy.GetPolicyName()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "SnapshotPolicy", "Snapshot policy %s deleted", snapshotPolicy.GetPolicyName())
+
+	return nil
+}
+
+// Diff permit to compare the expected policy with the current policy
+func (r *OpensearchSnapshotPolicyReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+
+	d, err := helper.Get(data, "currentPolicy")
+	if err != nil {
+		return diff, err
+	}
+	currentPolicy := d.(*opensearch.SnapshotManagementPolicyResponse)
+
+	expectedPolicy, err := snapshotPolicy.GenerateSnapshotManagementPolicy()
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate snapshot policy")
+	}
+	data["expectedPolicy"] = expectedPolicy
+
+	if currentPolicy == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("Snapshot policy %s not exist", snapshotPolicy.GetPolicyName())
+		return diff, nil
+	}
+
+	if d := localhelper.Diff(*expectedPolicy, currentPolicy.Policy); d != "" {
+		diff.NeedUpdate = true
+		diff.Diff = d
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchSnapshotPolicyReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&snapshotPolicy.Status.Conditions, metav1.Condition{
+		Type:    OpensearchSnapshotPolicyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status from the policy state
+// Events are recorded on policy and on cluster when new snapshot succeed or failed
+func (r *OpensearchSnapshotPolicyReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	snapshotPolicy := resource.(*opensearchapi.OpensearchSnapshotPolicy)
+	m := meta.(*opensearchMeta)
+	var d any
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "Snapshot policy successfully updated:\n%s", diff.Diff)
+	}
+
+	d, err = helper.Get(data, "explain")
+	if err != nil {
+		return err
+	}
+	explain := d.(*opensearch.SnapshotManagementExplain)
+
+	d, err = helper.Get(data, "snapshots")
+	if err != nil {
+		return err
+	}
+	snapshots := d.([]opensearch.Snapshot)
+
+	// Next run
+	if explain != nil && explain.Creation != nil && explain.Creation.Trigger.Time > 0 {
+		nextRun := metav1.NewTime(time.UnixMilli(explain.Creation.Trigger.Time))
+		snapshotPolicy.Status.NextRun = &nextRun
+	}
+
+	// Last successful snapshot
+	var lastSnapshot *opensearch.Snapshot
+	for i, snapshot := range snapshots {
+		if snapshot.State == snapshotSuccess && (lastSnapshot == nil || snapshot.EndTimeInMilis > lastSnapshot.EndTimeInMilis) {
+			lastSnapshot = &snapshots[i]
+		}
+	}
+	if lastSnapshot != nil && lastSnapshot.Snapshot != snapshotPolicy.Status.LastSuccessfulSnapshot {
+		lastSuccessfulTime := metav1.NewTime(time.UnixMilli(lastSnapshot.EndTimeInMilis))
+		snapshotPolicy.Status.LastSuccessfulSnapshot = lastSnapshot.Snapshot
+		snapshotPolicy.Status.LastSuccessfulTime = &lastSuccessfulTime
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "SnapshotCompleted", "Snapshot %s completed", lastSnapshot.Snapshot)
+		r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "SnapshotCompleted", "Snapshot %s completed", lastSnapshot.Snapshot)
+	}
+
+	// Last failure of creation or deletion
+	if explain != nil {
+		for _, state := range []*opensearch.SnapshotManagementExplainState{explain.Creation, explain.Deletion} {
+			if state == nil || state.LatestExecution == nil || state.LatestExecution.Status != snapshotExecutionFailed {
+				continue
+			}
+			failureTime := metav1.NewTime(time.UnixMilli(state.LatestExecution.EndTime))
+			if snapshotPolicy.Status.LastFailureTime != nil && !snapshotPolicy.Status.LastFailureTime.Before(&failureTime) {
+				continue
+			}
+			snapshotPolicy.Status.LastFailure = fmt.Sprintf("%s %s", state.LatestExecution.Info.Message, state.LatestExecution.Info.Cause)
+			snapshotPolicy.Status.LastFailureTime = &failureTime
+			r.recorder.Eventf(resource, corev1.EventTypeWarning, "SnapshotFailed", "Snapshot policy %s failed: %s", snapshotPolicy.GetPolicyName(), snapshotPolicy.Status.LastFailure)
+			r.recorder.Eventf(m.opensearch, corev1.EventTypeWarning, "SnapshotFailed", "Snapshot policy %s failed: %s", snapshotPolicy.GetPolicyName(), snapshotPolicy.Status.LastFailure)
+		}
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(snapshotPolicy.Status.Conditions, OpensearchSnapshotPolicyCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&snapshotPolicy.Status.Conditions, metav1.Condition{
+			Type:    OpensearchSnapshotPolicyCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "Snapshot policy up to date",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/disaster37/operator-sdk-extra/pkg/resource"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	opensearchapi "github.com/webcenter-fr/opensearch-operator/api/v1alpha1"
+	localhelper "github.com/webcenter-fr/opensearch-operator/pkg/helper"
+	"github.com/webcenter-fr/opensearch-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	condition "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	opensearchUserFinalizer = "user.opensearch.k8s.webcenter.fr/finalizer"
+	OpensearchUserCondition = "OpensearchUser"
+	generatedPasswordLength = 32
+)
+
+// OpensearchUserReconciler reconciles a OpensearchUser object
+type OpensearchUserReconciler struct {
+	Reconciler
+	client.Client
+	Scheme *runtime.Scheme
+	name   string
+}
+
+func NewOpensearchUserReconciler(client client.Client, scheme *runtime.Scheme) *OpensearchUserReconciler {
+
+	r := &OpensearchUserReconciler{
+		Client: client,
+		Scheme: scheme,
+		name:   "opensearchUser",
+	}
+
+	controllerMetrics.WithLabelValues(r.name).Add(0)
+
+	return r
+}
+
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchusers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=opensearch.k8s.webcenter.fr,resources=opensearchusers/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile permit to create, update and delete the internal user on Opensearch
+// It requeue periodically to detect change on password secret and drift on Opensearch
+func (r *OpensearchUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	reconciler, err := controller.NewStdReconciler(r.Client, opensearchUserFinalizer, r.reconciler, r.log, r.recorder, requeuedDuration)
+	if err != nil {
+		return res, err
+	}
+
+	user := &opensearchapi.OpensearchUser{}
+	data := map[string]any{}
+
+	res, err = reconciler.Reconcile(ctx, req, user, data)
+	if err == nil && res == (ctrl.Result{}) {
+		res.RequeueAfter = requeuedDuration
+	}
+
+	return res, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpensearchUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&opensearchapi.OpensearchUser{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
+
+// Configure permit to init condition and to get Opensearch client
+func (r *OpensearchUserReconciler) Configure(ctx context.Context, req ctrl.Request, resource resource.Resource) (meta any, err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+
+	// Init condition status if not exist
+	if condition.FindStatusCondition(user.Status.Conditions, OpensearchUserCondition) == nil {
+		condition.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+			Type:   OpensearchUserCondition,
+			Status: metav1.ConditionFalse,
+			Reason: "Initialize",
+		})
+	}
+
+	return getOpensearchMeta(ctx, r.Client, user.Namespace, user.Spec.OpensearchRef, !user.DeletionTimestamp.IsZero())
+}
+
+// Read the current user and the secret that store its password
+// The secret is generated with random password if needed
+func (r *OpensearchUserReconciler) Read(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+	m := meta.(*opensearchMeta)
+	if m == nil {
+		return res, nil
+	}
+
+	currentUser, err := m.client.GetInternalUser(ctx, user.GetUsername())
+	if err != nil {
+		return res, err
+	}
+	data["currentUser"] = currentUser
+
+	// The password is not needed to delete user
+	if !user.DeletionTimestamp.IsZero() {
+		return res, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err = r.Client.Get(ctx, types.NamespacedName{Namespace: user.Namespace, Name: user.GetSecretNameForPassword()}, secret); err != nil {
+		if !k8serrors.IsNotFound(err) || !user.IsGeneratedPassword() {
+			return res, errors.Wrapf(err, "Error when read secret %s", user.GetSecretNameForPassword())
+		}
+
+		password, err := localhelper.GeneratePassword(generatedPasswordLength)
+		if err != nil {
+			return res, err
+		}
+		secret = user.GeneratePasswordSecret(password)
+		if err = ctrl.SetControllerReference(user, secret, r.Scheme); err != nil {
+			return res, errors.Wrapf(err, "Error when set owner reference on secret %s", secret.Name)
+		}
+		if err = r.Client.Create(ctx, secret); err != nil {
+			return res, errors.Wrapf(err, "Error when create secret %s", secret.Name)
+		}
+		r.log.Infof("Generate password on secret %s", secret.Name)
+	}
+	data["secret"] = secret
+
+	return res, nil
+}
+
+// Create permit to create the user
+func (r *OpensearchUserReconciler) Create(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+
+	if err = r.putUser(ctx, user, data, meta.(*opensearchMeta)); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(meta.(*opensearchMeta).opensearch, corev1.EventTypeNormal, "User", "User %s created", user.GetUsername())
+
+	return res, nil
+}
+
+// Update permit to update the user
+func (r *OpensearchUserReconciler) Update(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (res ctrl.Result, err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+
+	if err = r.putUser(ctx, user, data, meta.(*opensearchMeta)); err != nil {
+		return res, err
+	}
+
+	r.recorder.Eventf(meta.(*opensearchMeta).opensearch, corev1.EventTypeNormal, "User", "User %s updated", user.GetUsername())
+
+	return res, nil
+}
+
+// Delete permit to delete the user
+// The generated secret is removed by garbage collector
+func (r *OpensearchUserReconciler) Delete(ctx context.Context, resource resource.Resource, data map[string]any, meta any) (err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+	m := meta.(*opensearchMeta)
+
+	// Update metrics
+	controllerMetrics.WithLabelValues(r.name).Dec()
+
+	// The cluster is already deleted
+	if m == nil {
+		return nil
+	}
+
+	if err = m.client.DeleteInternalUser(ctx, user.GetUsername()); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(m.opensearch, corev1.EventTypeNormal, "User", "User %s deleted", user.GetUsername())
+
+	return nil
+}
+
+// Diff permit to compare the expected user with the current user
+// The password can't be read from Opensearch, so it is updated when the secret change
+func (r *OpensearchUserReconciler) Diff(resource resource.Resource, data map[string]any, meta any) (diff controller.Diff, err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+	var d any
+
+	d, err = helper.Get(data, "currentUser")
+	if err != nil {
+		return diff, err
+	}
+	currentUser := d.(*opensearch.InternalUser)
+
+	d, err = helper.Get(data, "secret")
+	if err != nil {
+		return diff, err
+	}
+	secret := d.(*corev1.Secret)
+
+	expectedUser, err := user.GenerateInternalUser(secret)
+	if err != nil {
+		return diff, errors.Wrap(err, "Error when generate internal user")
+	}
+	data["expectedUser"] = expectedUser
+
+	if currentUser == nil {
+		diff.NeedCreate = true
+		diff.Diff = fmt.Sprintf("User %s not exist", user.GetUsername())
+		return diff, nil
+	}
+
+	if currentUser.Reserved {
+		return diff, errors.Errorf("User %s is reserved and can't be managed", user.GetUsername())
+	}
+
+	// Security API return empty backend roles and attributes instead of nil
+	if d := localhelper.Diff(opensearch.InternalUser{
+		Description:  expectedUser.Description,
+		BackendRoles: expectedUser.BackendRoles,
+		Attributes:   expectedUser.Attributes,
+	}, opensearch.InternalUser{
+		Description:  currentUser.Description,
+		BackendRoles: currentUser.BackendRoles,
+		Attributes:   currentUser.Attributes,
+	}, cmpopts.EquateEmpty()); d != "" {
+		diff.NeedUpdate = true
+		diff.Diff = d
+	}
+
+	if secret.ResourceVersion != user.Status.PasswordSecretVersion {
+		diff.NeedUpdate = true
+		diff.Diff += fmt.Sprintf("Password change on secret %s\n", secret.Name)
+	}
+
+	return diff, nil
+}
+
+// OnError permit to set status condition on the right state and record error
+func (r *OpensearchUserReconciler) OnError(ctx context.Context, resource resource.Resource, data map[string]any, meta any, err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+
+	r.log.Error(err)
+	r.recorder.Event(resource, corev1.EventTypeWarning, "Failed", err.Error())
+
+	condition.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+		Type:    OpensearchUserCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Failed",
+		Message: err.Error(),
+	})
+
+	// Update metrics
+	totalErrors.Inc()
+}
+
+// OnSuccess permit to set status condition
+func (r *OpensearchUserReconciler) OnSuccess(ctx context.Context, resource resource.Resource, data map[string]any, meta any, diff controller.Diff) (err error) {
+	user := resource.(*opensearchapi.OpensearchUser)
+
+	if diff.NeedCreate || diff.NeedUpdate {
+		r.recorder.Eventf(resource, corev1.EventTypeNormal, "Completed", "User successfully updated:\n%s", diff.Diff)
+	}
+
+	// Update condition status if needed
+	if !condition.IsStatusConditionPresentAndEqual(user.Status.Conditions, OpensearchUserCondition, metav1.ConditionTrue) {
+		condition.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+			Type:    OpensearchUserCondition,
+			Reason:  "Success",
+			Status:  metav1.ConditionTrue,
+			Message: "User up to date",
+		})
+	}
+
+	return nil
+}
+
+// putUser permit to create or update the user with its password
+// It keep the secret version on status to know when password change
+func (r *OpensearchUserReconciler) putUser(ctx context.Context, user *opensearchapi.OpensearchUser, data map[string]any, m *opensearchMeta) (err error) {
+	var d any
+
+	d, err = helper.Get(data, "expectedUser")
+	if err != nil {
+		return err
+	}
+	expectedUser := d.(*opensearch.InternalUser)
+
+	d, err = helper.Get(data, "secret")
+	if err != nil {
+		return err
+	}
+	secret := d.(*corev1.Secret)
+
+	if err = m.client.PutInternalUser(ctx, user.GetUsername(), expectedUser); err != nil {
+		return err
+	}
+	user.Status.PasswordSecretVersion = secret.ResourceVersion
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/disaster37/operator-sdk-extra/pkg/controller"
+	"github.com/disaster37/operator-sdk-extra/pkg/helper"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// specHashAnnotation is the annotation that store the hash of expected object
+// It permit to detect change without compare the default values set by Kubernetes
+var specHashAnnotation = fmt.Sprintf("%s/spec-hash", opensearchAnnotationKey)
+
+// getResource permit to read object from Kubernetes
+// It return nil if object not exist
+func getResource(ctx context.Context, c client.Client, namespace, name string, o client.Object) (client.Object, error) {
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, o); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when read %s %s", reflect.TypeOf(o).Elem().Name(), name)
+	}
+
+	return o, nil
+}
+
+// setSpecHash permit to store the hash of expected object on annotation
+func setSpecHash(o client.Object) (err error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return errors.Wrapf(err, "Error when marshall %s", o.GetName())
+	}
+
+	// Annotations can be shared with the parent object
+	annotations := make(map[string]string, len(o.GetAnnotations())+1)
+	for key, value := range o.GetAnnotations() {
+		annotations[key] = value
+	}
+	annotations[specHashAnnotation] = fmt.Sprintf("%x", sha256.Sum256(b))
+	o.SetAnnotations(annotations)
+
+	return nil
+}
+
+// compareResource permit to compare the current object with the expected object from the spec hash
+// The current object is nil when it not exist, and the expected object is nil when it must be removed
+func compareResource(current, expected client.Object) (compare CompareResource, err error) {
+	compare = CompareResource{
+		Current:  current,
+		Expected: expected,
+		Diff:     &controller.Diff{},
+	}
+
+	if expected != nil {
+		if err = setSpecHash(expected); err != nil {
+			return compare, err
+		}
+	}
+
+	switch {
+	case current == nil && expected == nil:
+		return compare, nil
+	case current == nil:
+		compare.Diff.NeedCreate = true
+		compare.Diff.Diff = fmt.Sprintf("%s %s not exist\n", reflect.TypeOf(expected).Elem().Name(), expected.GetName())
+	case expected == nil:
+		compare.Diff.NeedUpdate = true
+		compare.Diff.Diff = fmt.Sprintf("%s %s must be removed\n", reflect.TypeOf(current).Elem().Name(), current.GetName())
+	case current.GetAnnotations()[specHashAnnotation] != expected.GetAnnotations()[specHashAnnotation]:
+		compare.Diff.NeedUpdate = true
+		compare.Diff.Diff = fmt.Sprintf("%s %s need to be updated\n", reflect.TypeOf(expected).Elem().Name(), expected.GetName())
+	}
+
+	return compare, nil
+}
+
+// compareResources permit to compare the expected resources with the current resources read on data with the same key
+// The expected resources are owned by the parent resource. A nil expected resource is removed
+// It return only the resources that need to be created, updated or removed
+func compareResources(owner client.Object, scheme *runtime.Scheme, data map[string]any, expectedResources map[string]client.Object) (compares []CompareResource, diff controller.Diff, err error) {
+	keys := make([]string, 0, len(expectedResources))
+	for key := range expectedResources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	compares = make([]CompareResource, 0, len(expectedResources))
+	for _, key := range keys {
+		d, err := helper.Get(data, key)
+		if err != nil {
+			return nil, diff, err
+		}
+		var current client.Object
+		if d != nil {
+			current = d.(client.Object)
+		}
+
+		expected := expectedResources[key]
+		if expected != nil {
+			if err = ctrl.SetControllerReference(owner, expected, scheme); err != nil {
+				return nil, diff, errors.Wrapf(err, "Error when set owner reference on %s", expected.GetName())
+			}
+		}
+
+		compare, err := compareResource(current, expected)
+		if err != nil {
+			return nil, diff, err
+		}
+		if compare.Diff.NeedCreate || compare.Diff.NeedUpdate {
+			diff.Diff += compare.Diff.Diff
+			compares = append(compares, compare)
+		}
+	}
+
+	return compares, diff, nil
+}
+
+// applyResources permit to create, update or delete the compared resources
+func applyResources(ctx context.Context, c client.Client, compares []CompareResource) (err error) {
+	for _, compare := range compares {
+		if err = applyResource(ctx, c, compare); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyResource permit to create, update or delete object from the comparison
+func applyResource(ctx context.Context, c client.Client, compare CompareResource) (err error) {
+	switch {
+	case compare.Diff.NeedCreate:
+		if err = c.Create(ctx, compare.Expected); err != nil {
+			return errors.Wrapf(err, "Error when create %s", compare.Expected.GetName())
+		}
+	case compare.Diff.NeedUpdate && compare.Expected == nil:
+		if err = c.Delete(ctx, compare.Current); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Error when delete %s", compare.Current.GetName())
+		}
+	case compare.Diff.NeedUpdate:
+		compare.Expected.SetResourceVersion(compare.Current.GetResourceVersion())
+
+		// Finalizers are managed by the controller of object
+		compare.Expected.SetFinalizers(compare.Current.GetFinalizers())
+
+		// Cluster IP is immutable
+		if currentService, ok := compare.Current.(*corev1.Service); ok {
+			expectedService := compare.Expected.(*corev1.Service)
+			expectedService.Spec.ClusterIP = currentService.Spec.ClusterIP
+			expectedService.Spec.ClusterIPs = currentService.Spec.ClusterIPs
+		}
+
+		if err = c.Update(ctx, compare.Expected); err != nil {
+			return errors.Wrapf(err, "Error when update %s", compare.Expected.GetName())
+		}
+	}
+
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -97,6 +98,108 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Opensearch")
 		os.Exit(1)
 	}
+	log := logrus.NewEntry(logrus.StandardLogger())
+
+	opensearchSnapshotPolicyController := controllers.NewOpensearchSnapshotPolicyReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchSnapshotPolicyController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchSnapshotPolicyController",
+	}))
+	opensearchSnapshotPolicyController.SetRecorder(mgr.GetEventRecorderFor("opensearch-snapshot-policy-controller"))
+	opensearchSnapshotPolicyController.SetReconsiler(opensearchSnapshotPolicyController)
+	if err = opensearchSnapshotPolicyController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchSnapshotPolicy")
+		os.Exit(1)
+	}
+	opensearchUserController := controllers.NewOpensearchUserReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchUserController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchUserController",
+	}))
+	opensearchUserController.SetRecorder(mgr.GetEventRecorderFor("opensearch-user-controller"))
+	opensearchUserController.SetReconsiler(opensearchUserController)
+	if err = opensearchUserController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchUser")
+		os.Exit(1)
+	}
+	opensearchRoleController := controllers.NewOpensearchRoleReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchRoleController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchRoleController",
+	}))
+	opensearchRoleController.SetRecorder(mgr.GetEventRecorderFor("opensearch-role-controller"))
+	opensearchRoleController.SetReconsiler(opensearchRoleController)
+	if err = opensearchRoleController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchRole")
+		os.Exit(1)
+	}
+	opensearchRoleMappingController := controllers.NewOpensearchRoleMappingReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchRoleMappingController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchRoleMappingController",
+	}))
+	opensearchRoleMappingController.SetRecorder(mgr.GetEventRecorderFor("opensearch-role-mapping-controller"))
+	opensearchRoleMappingController.SetReconsiler(opensearchRoleMappingController)
+	if err = opensearchRoleMappingController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchRoleMapping")
+		os.Exit(1)
+	}
+	opensearchIndexTemplateController := controllers.NewOpensearchIndexTemplateReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchIndexTemplateController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchIndexTemplateController",
+	}))
+	opensearchIndexTemplateController.SetRecorder(mgr.GetEventRecorderFor("opensearch-index-template-controller"))
+	opensearchIndexTemplateController.SetReconsiler(opensearchIndexTemplateController)
+	if err = opensearchIndexTemplateController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIndexTemplate")
+		os.Exit(1)
+	}
+	opensearchComponentTemplateController := controllers.NewOpensearchComponentTemplateReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchComponentTemplateController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchComponentTemplateController",
+	}))
+	opensearchComponentTemplateController.SetRecorder(mgr.GetEventRecorderFor("opensearch-component-template-controller"))
+	opensearchComponentTemplateController.SetReconsiler(opensearchComponentTemplateController)
+	if err = opensearchComponentTemplateController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchComponentTemplate")
+		os.Exit(1)
+	}
+	opensearchIndexStateManagementPolicyController := controllers.NewOpensearchIndexStateManagementPolicyReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchIndexStateManagementPolicyController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchIndexStateManagementPolicyController",
+	}))
+	opensearchIndexStateManagementPolicyController.SetRecorder(mgr.GetEventRecorderFor("opensearch-ism-policy-controller"))
+	opensearchIndexStateManagementPolicyController.SetReconsiler(opensearchIndexStateManagementPolicyController)
+	if err = opensearchIndexStateManagementPolicyController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIndexStateManagementPolicy")
+		os.Exit(1)
+	}
+	opensearchIndexController := controllers.NewOpensearchIndexReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchIndexController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchIndexController",
+	}))
+	opensearchIndexController.SetRecorder(mgr.GetEventRecorderFor("opensearch-index-controller"))
+	opensearchIndexController.SetReconsiler(opensearchIndexController)
+	if err = opensearchIndexController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchIndex")
+		os.Exit(1)
+	}
+	opensearchDashboardsController := controllers.NewOpensearchDashboardsReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchDashboardsController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchDashboardsController",
+	}))
+	opensearchDashboardsController.SetRecorder(mgr.GetEventRecorderFor("opensearch-dashboards-controller"))
+	opensearchDashboardsController.SetReconsiler(opensearchDashboardsController)
+	if err = opensearchDashboardsController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchDashboards")
+		os.Exit(1)
+	}
+	opensearchDataPrepperController := controllers.NewOpensearchDataPrepperReconciler(mgr.GetClient(), mgr.GetScheme())
+	opensearchDataPrepperController.SetLogger(log.WithFields(logrus.Fields{
+		"type": "OpensearchDataPrepperController",
+	}))
+	opensearchDataPrepperController.SetRecorder(mgr.GetEventRecorderFor("opensearch-dataprepper-controller"))
+	opensearchDataPrepperController.SetReconsiler(opensearchDataPrepperController)
+	if err = opensearchDataPrepperController.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpensearchDataPrepper")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
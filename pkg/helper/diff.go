@@ -6,8 +6,12 @@ import (
 	"github.com/kr/pretty"
 )
 
-func Diff(expected, current any) string {
-	return cmp.Diff(expected, current, cmpopts.SortSlices(func(x, y any) bool {
+// Diff permit to compare expected and current object without care about slice order
+// Additionnal options can be provided, like cmpopts.EquateEmpty() when Opensearch API return empty values
+func Diff(expected, current any, opts ...cmp.Option) string {
+	opts = append([]cmp.Option{cmpopts.SortSlices(func(x, y any) bool {
 		return pretty.Sprint(x) < pretty.Sprint(y)
-	}))
-}
\ No newline at end of file
+	})}, opts...)
+
+	return cmp.Diff(expected, current, opts...)
+}
@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+const passwordCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// GeneratePassword permit to generate random password with alphanumeric characters
+func GeneratePassword(length int) (password string, err error) {
+	b := make([]byte, length)
+	max := big.NewInt(int64(len(passwordCharset)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Wrap(err, "Error when generate random password")
+		}
+		b[i] = passwordCharset[n.Int64()]
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,152 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+)
+
+// Config is the settings needed to call Opensearch API
+type Config struct {
+	// URL is the Opensearch endpoint, like https://opensearch.default.svc:9200
+	URL string
+
+	// Username is the user used with basic authentification
+	Username string
+
+	// Password is the password used with basic authentification
+	Password string
+
+	// CACert is the PEM certificate of the CA that sign Opensearch API certificate
+	// When empty, the system CA are used
+	CACert []byte
+
+	// Timeout is the timeout of each request
+	// Default to 30 seconds
+	Timeout time.Duration
+}
+
+// Client permit to call Opensearch API
+type Client struct {
+	httpClient *http.Client
+	url        string
+	username   string
+	password   string
+}
+
+// ResponseError is returned when Opensearch API not return 2xx status code
+type ResponseError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("Opensearch return status code %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound return true if the error is returned because the object not exist on Opensearch
+func IsNotFound(err error) bool {
+	var responseError *ResponseError
+	if errors.As(err, &responseError) {
+		return responseError.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
+
+// NewClient permit to create new Opensearch API client
+func NewClient(cfg Config) (client *Client, err error) {
+	if cfg.URL == "" {
+		return nil, errors.New("URL must be provided")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(cfg.CACert) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(cfg.CACert) {
+			return nil, errors.New("Error when load CA certificate")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	timeout := defaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		url:      strings.TrimSuffix(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+	}, nil
+}
+
+// do permit to call Opensearch API and decode the JSON response on result
+// body and result can be nil
+func (c *Client) do(ctx context.Context, method string, path string, body any, result any) (err error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "Error when marshall body")
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return errors.Wrapf(err, "Error when create request %s %s", method, path)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Error when call %s %s", method, path)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "Error when read response of %s %s", method, path)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &ResponseError{
+			StatusCode: resp.StatusCode,
+			Body:       string(b),
+		}
+	}
+
+	if result != nil && len(b) > 0 {
+		if err = json.Unmarshal(b, result); err != nil {
+			return errors.Wrapf(err, "Error when decode response of %s %s", method, path)
+		}
+	}
+
+	return nil
+}
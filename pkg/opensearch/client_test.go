@@ -0,0 +1,115 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// request is the request received by fake Opensearch API
+type request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]any
+}
+
+// newFakeOpensearch permit to start fake Opensearch API that record requests and return the response of handler
+func newFakeOpensearch(t *testing.T, handler func(r *request) (statusCode int, body any)) (client *Client, requests *[]request) {
+	requests = &[]request{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		if username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		req := request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+		}
+		b, _ := io.ReadAll(r.Body)
+		if len(b) > 0 {
+			if err := json.Unmarshal(b, &req.Body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		*requests = append(*requests, req)
+
+		statusCode, body := handler(&req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		if body != nil {
+			if err := json.NewEncoder(w).Encode(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{
+		URL:      server.URL,
+		Username: "admin",
+		Password: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, requests
+}
+
+func TestNewClient(t *testing.T) {
+	// When URL is missing
+	_, err := NewClient(Config{})
+	assert.Error(t, err)
+
+	// When CA is not valid
+	_, err = NewClient(Config{
+		URL:    "https://localhost:9200",
+		CACert: []byte("bad"),
+	})
+	assert.Error(t, err)
+
+	// When all is right
+	client, err := NewClient(Config{
+		URL: "https://localhost:9200/",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://localhost:9200", client.url)
+}
+
+func TestDo(t *testing.T) {
+	client, _ := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch r.Path {
+		case "/ok":
+			return 200, map[string]any{"acknowledged": true}
+		default:
+			return 404, map[string]any{"error": "not found"}
+		}
+	})
+
+	// When success
+	result := map[string]any{}
+	err := client.do(context.Background(), http.MethodGet, "/ok", nil, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, true, result["acknowledged"])
+
+	// When not found
+	err = client.do(context.Background(), http.MethodGet, "/missing", nil, nil)
+	assert.Error(t, err)
+	assert.True(t, IsNotFound(err))
+
+	// When authentification failed
+	client.password = "bad"
+	err = client.do(context.Background(), http.MethodGet, "/ok", nil, nil)
+	assert.Error(t, err)
+	assert.False(t, IsNotFound(err))
+}
@@ -0,0 +1,87 @@
+package opensearch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/thoas/go-funk"
+)
+
+// ClusterSettings is the cluster settings
+type ClusterSettings struct {
+	Persistent map[string]any `json:"persistent,omitempty"`
+	Transient  map[string]any `json:"transient,omitempty"`
+}
+
+// GetClusterSettings permit to get the cluster settings with flat keys
+func (c *Client) GetClusterSettings(ctx context.Context) (settings *ClusterSettings, err error) {
+	settings = &ClusterSettings{}
+	if err = c.do(ctx, http.MethodGet, "/_cluster/settings?flat_settings=true", nil, settings); err != nil {
+		return nil, errors.Wrap(err, "Error when get cluster settings")
+	}
+
+	return settings, nil
+}
+
+// PutClusterSettings permit to update the persistent cluster settings
+// Set nil value to reset setting
+func (c *Client) PutClusterSettings(ctx context.Context, settings map[string]any) (err error) {
+	if err = c.do(ctx, http.MethodPut, "/_cluster/settings", &ClusterSettings{Persistent: settings}, nil); err != nil {
+		return errors.Wrap(err, "Error when update cluster settings")
+	}
+
+	return nil
+}
+
+// CountShardsOnNodes permit to count the shards allocated on nodes
+func (c *Client) CountShardsOnNodes(ctx context.Context, nodeNames []string) (count int, err error) {
+	shards := make([]map[string]any, 0)
+	if err = c.do(ctx, http.MethodGet, "/_cat/shards?format=json&h=node", nil, &shards); err != nil {
+		return 0, errors.Wrap(err, "Error when get shards")
+	}
+
+	for _, shard := range shards {
+		if node, ok := shard["node"].(string); ok && funk.ContainsString(nodeNames, node) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// AddVotingConfigExclusions permit to exclude master nodes from the voting configuration before remove them
+func (c *Client) AddVotingConfigExclusions(ctx context.Context, nodeNames []string) (err error) {
+	if err = c.do(ctx, http.MethodPost, "/_cluster/voting_config_exclusions?node_names="+url.QueryEscape(strings.Join(nodeNames, ",")), nil, nil); err != nil {
+		return errors.Wrap(err, "Error when add voting config exclusions")
+	}
+
+	return nil
+}
+
+// ClearVotingConfigExclusions permit to clear voting configuration exclusions once excluded nodes are removed
+func (c *Client) ClearVotingConfigExclusions(ctx context.Context) (err error) {
+	if err = c.do(ctx, http.MethodDelete, "/_cluster/voting_config_exclusions?wait_for_removal=false", nil, nil); err != nil {
+		return errors.Wrap(err, "Error when clear voting config exclusions")
+	}
+
+	return nil
+}
+
+// ClusterHealth is the cluster health
+type ClusterHealth struct {
+	Status           string `json:"status"`
+	UnassignedShards int    `json:"unassigned_shards"`
+}
+
+// GetClusterHealth permit to get the cluster health
+func (c *Client) GetClusterHealth(ctx context.Context) (health *ClusterHealth, err error) {
+	health = &ClusterHealth{}
+	if err = c.do(ctx, http.MethodGet, "/_cluster/health", nil, health); err != nil {
+		return nil, errors.Wrap(err, "Error when get cluster health")
+	}
+
+	return health, nil
+}
@@ -0,0 +1,88 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterSettings(t *testing.T) {
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		if r.Method == "GET" {
+			return 200, map[string]any{
+				"persistent": map[string]any{
+					"cluster.routing.allocation.exclude._name": "test-master-os-0",
+				},
+				"transient": map[string]any{},
+			}
+		}
+		return 200, map[string]any{"acknowledged": true}
+	})
+
+	// Get settings
+	settings, err := client.GetClusterSettings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "test-master-os-0", settings.Persistent["cluster.routing.allocation.exclude._name"])
+	assert.Equal(t, "flat_settings=true", (*requests)[0].Query)
+
+	// Put settings
+	err = client.PutClusterSettings(context.Background(), map[string]any{
+		"cluster.routing.allocation.exclude._name": nil,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", (*requests)[1].Method)
+	assert.Equal(t, "/_cluster/settings", (*requests)[1].Path)
+	assert.Equal(t, map[string]any{"persistent": map[string]any{"cluster.routing.allocation.exclude._name": nil}}, (*requests)[1].Body)
+}
+
+func TestCountShardsOnNodes(t *testing.T) {
+	client, _ := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		return 200, []map[string]any{
+			{"node": "test-master-os-0"},
+			{"node": "test-master-os-1"},
+			{"node": "test-data-os-0"},
+			{"node": nil},
+		}
+	})
+
+	count, err := client.CountShardsOnNodes(context.Background(), []string{"test-master-os-0", "test-master-os-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = client.CountShardsOnNodes(context.Background(), []string{"test-client-os-0"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestVotingConfigExclusions(t *testing.T) {
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		return 200, nil
+	})
+
+	err := client.AddVotingConfigExclusions(context.Background(), []string{"test-master-os-0", "test-master-os-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", (*requests)[0].Method)
+	assert.Equal(t, "/_cluster/voting_config_exclusions", (*requests)[0].Path)
+	assert.Equal(t, "node_names=test-master-os-0%2Ctest-master-os-1", (*requests)[0].Query)
+
+	err = client.ClearVotingConfigExclusions(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE", (*requests)[1].Method)
+	assert.Equal(t, "wait_for_removal=false", (*requests)[1].Query)
+}
+
+func TestGetClusterHealth(t *testing.T) {
+	client, _ := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		return 200, map[string]any{
+			"cluster_name":      "test",
+			"status":            "red",
+			"unassigned_shards": 2,
+		}
+	})
+
+	health, err := client.GetClusterHealth(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "red", health.Status)
+	assert.Equal(t, 2, health.UnassignedShards)
+}
@@ -0,0 +1,100 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// staticSettings is the index settings that can only be set when create index
+var staticSettings = []string{
+	"index.number_of_shards",
+	"index.number_of_routing_shards",
+	"index.routing_partition_size",
+	"index.codec",
+	"index.soft_deletes.enabled",
+	"index.load_fixed_bitset_filters_eagerly",
+	"index.shard.check_on_startup",
+	"index.knn",
+	"index.sort.",
+	"index.analysis.",
+}
+
+// Index is the index settings, mappings and aliases
+type Index struct {
+	Settings map[string]any `json:"settings,omitempty"`
+	Mappings map[string]any `json:"mappings,omitempty"`
+	Aliases  map[string]any `json:"aliases,omitempty"`
+}
+
+// IsStaticSetting return true if the index setting can't be updated on existing index
+func IsStaticSetting(key string) bool {
+	for _, setting := range staticSettings {
+		if key == setting || (strings.HasSuffix(setting, ".") && strings.HasPrefix(key, setting)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetIndex permit to get index
+// It return nil if index not exist
+// The settings are flattened to be compared with expected settings
+func (c *Client) GetIndex(ctx context.Context, name string) (index *Index, err error) {
+	indices := map[string]Index{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/%s?flat_settings=true", url.PathEscape(name)), nil, &indices); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get index %s", name)
+	}
+
+	i, ok := indices[name]
+	if !ok {
+		return nil, nil
+	}
+	i.Settings = NormalizeSettings(i.Settings)
+
+	return &i, nil
+}
+
+// CreateIndex permit to create index
+func (c *Client) CreateIndex(ctx context.Context, name string, index *Index) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/%s", url.PathEscape(name)), index, nil); err != nil {
+		return errors.Wrapf(err, "Error when create index %s", name)
+	}
+
+	return nil
+}
+
+// UpdateIndexSettings permit to update dynamic settings of index
+func (c *Client) UpdateIndexSettings(ctx context.Context, name string, settings map[string]any) (err error) {
+	for key := range settings {
+		if IsStaticSetting(key) {
+			return errors.Errorf("Setting %s is static and can't be updated on index %s", key, name)
+		}
+	}
+
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/%s/_settings", url.PathEscape(name)), settings, nil); err != nil {
+		return errors.Wrapf(err, "Error when update settings of index %s", name)
+	}
+
+	return nil
+}
+
+// DeleteIndex permit to delete index and all its data
+func (c *Client) DeleteIndex(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete index %s", name)
+	}
+
+	return nil
+}
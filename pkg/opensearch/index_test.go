@@ -0,0 +1,97 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex(t *testing.T) {
+	var index map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch {
+		case r.Method == "GET":
+			if index == nil {
+				return 404, map[string]any{"error": "index_not_found_exception"}
+			}
+			return 200, map[string]any{
+				"logs-000001": index,
+			}
+		case r.Method == "PUT" && r.Path == "/logs-000001":
+			index = r.Body
+			return 200, map[string]any{"acknowledged": true}
+		case r.Method == "PUT" && r.Path == "/logs-000001/_settings":
+			for key, value := range r.Body {
+				index["settings"].(map[string]any)[key] = value
+			}
+			return 200, map[string]any{"acknowledged": true}
+		case r.Method == "DELETE":
+			if index == nil {
+				return 404, map[string]any{"error": "index_not_found_exception"}
+			}
+			index = nil
+			return 200, map[string]any{"acknowledged": true}
+		}
+
+		return 400, nil
+	})
+
+	// When index not exist
+	current, err := client.GetIndex(context.Background(), "logs-000001")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create index
+	expected := &Index{
+		Settings: map[string]any{
+			"index.number_of_shards":   "1",
+			"index.number_of_replicas": "1",
+		},
+		Aliases: map[string]any{
+			"logs": map[string]any{
+				"is_write_index": true,
+			},
+		},
+	}
+	err = client.CreateIndex(context.Background(), "logs-000001", expected)
+	assert.NoError(t, err)
+
+	// Get index
+	current, err = client.GetIndex(context.Background(), "logs-000001")
+	assert.NoError(t, err)
+	assert.Equal(t, "flat_settings=true", (*requests)[2].Query)
+	assert.Equal(t, expected, current)
+
+	// Update dynamic settings
+	err = client.UpdateIndexSettings(context.Background(), "logs-000001", map[string]any{
+		"index.number_of_replicas": "2",
+	})
+	assert.NoError(t, err)
+	current, err = client.GetIndex(context.Background(), "logs-000001")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", current.Settings["index.number_of_replicas"])
+
+	// Update static settings
+	err = client.UpdateIndexSettings(context.Background(), "logs-000001", map[string]any{
+		"index.number_of_shards": "2",
+	})
+	assert.Error(t, err)
+
+	// Delete index
+	err = client.DeleteIndex(context.Background(), "logs-000001")
+	assert.NoError(t, err)
+
+	// Delete index that not exist
+	err = client.DeleteIndex(context.Background(), "logs-000001")
+	assert.NoError(t, err)
+}
+
+func TestIsStaticSetting(t *testing.T) {
+	assert.True(t, IsStaticSetting("index.number_of_shards"))
+	assert.True(t, IsStaticSetting("index.sort.field"))
+	assert.True(t, IsStaticSetting("index.analysis.analyzer.default.type"))
+	assert.False(t, IsStaticSetting("index.number_of_replicas"))
+	assert.False(t, IsStaticSetting("index.refresh_interval"))
+}
@@ -0,0 +1,176 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ismDefaultRetry is the retry added by Opensearch on each action when not provided
+var ismDefaultRetry = map[string]any{
+	"count":   float64(3),
+	"backoff": "exponential",
+	"delay":   "1m",
+}
+
+// IsmPolicy is the index state management policy
+type IsmPolicy struct {
+	Description  string        `json:"description,omitempty"`
+	DefaultState string        `json:"default_state"`
+	States       []IsmState    `json:"states"`
+	IsmTemplate  []IsmTemplate `json:"ism_template,omitempty"`
+}
+
+// IsmState is the state of index state management policy
+type IsmState struct {
+	Name        string           `json:"name"`
+	Actions     []map[string]any `json:"actions"`
+	Transitions []IsmTransition  `json:"transitions"`
+}
+
+// IsmTransition is the transition to another state
+type IsmTransition struct {
+	StateName  string                   `json:"state_name"`
+	Conditions *IsmTransitionConditions `json:"conditions,omitempty"`
+}
+
+// IsmTransitionConditions is the conditions needed to transition to another state
+type IsmTransitionConditions struct {
+	MinIndexAge    string `json:"min_index_age,omitempty"`
+	MinRolloverAge string `json:"min_rollover_age,omitempty"`
+	MinDocCount    *int64 `json:"min_doc_count,omitempty"`
+	MinSize        string `json:"min_size,omitempty"`
+}
+
+// IsmTemplate permit to apply automatically the policy on new indices
+type IsmTemplate struct {
+	IndexPatterns   []string `json:"index_patterns"`
+	Priority        int64    `json:"priority"`
+	LastUpdatedTime int64    `json:"last_updated_time,omitempty"`
+}
+
+// IsmPolicyResponse is the index state management policy with its version
+// The sequence number and primary term are needed to update policy
+type IsmPolicyResponse struct {
+	SeqNo       int64     `json:"_seq_no"`
+	PrimaryTerm int64     `json:"_primary_term"`
+	Policy      IsmPolicy `json:"policy"`
+}
+
+// GetIsmPolicy permit to get index state management policy
+// It return nil if policy not exist
+// The default values added by Opensearch are removed, so the policy can be compared with expected policy
+func (c *Client) GetIsmPolicy(ctx context.Context, name string) (policy *IsmPolicyResponse, err error) {
+	policy = &IsmPolicyResponse{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_plugins/_ism/policies/%s", url.PathEscape(name)), nil, policy); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get ISM policy %s", name)
+	}
+
+	for i := range policy.Policy.IsmTemplate {
+		policy.Policy.IsmTemplate[i].LastUpdatedTime = 0
+	}
+	for _, state := range policy.Policy.States {
+		for _, action := range state.Actions {
+			if reflect.DeepEqual(action["retry"], ismDefaultRetry) {
+				delete(action, "retry")
+			}
+		}
+	}
+
+	return policy, nil
+}
+
+// CreateIsmPolicy permit to create index state management policy
+func (c *Client) CreateIsmPolicy(ctx context.Context, name string, policy *IsmPolicy) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_plugins/_ism/policies/%s", url.PathEscape(name)), map[string]any{"policy": policy}, nil); err != nil {
+		return errors.Wrapf(err, "Error when create ISM policy %s", name)
+	}
+
+	return nil
+}
+
+// UpdateIsmPolicy permit to update index state management policy
+// It use the sequence number and primary term of current policy to avoid concurrent update
+func (c *Client) UpdateIsmPolicy(ctx context.Context, name string, policy *IsmPolicy, seqNo int64, primaryTerm int64) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_plugins/_ism/policies/%s?if_seq_no=%d&if_primary_term=%d", url.PathEscape(name), seqNo, primaryTerm), map[string]any{"policy": policy}, nil); err != nil {
+		return errors.Wrapf(err, "Error when update ISM policy %s", name)
+	}
+
+	return nil
+}
+
+// DeleteIsmPolicy permit to delete index state management policy
+func (c *Client) DeleteIsmPolicy(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_plugins/_ism/policies/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete ISM policy %s", name)
+	}
+
+	return nil
+}
+
+// GetIsmManagedIndices permit to get the indices managed by the index state management policy
+func (c *Client) GetIsmManagedIndices(ctx context.Context, name string) (indices []string, err error) {
+	res := map[string]json.RawMessage{}
+	if err = c.do(ctx, http.MethodGet, "/_plugins/_ism/explain/*", nil, &res); err != nil {
+		return nil, errors.Wrap(err, "Error when explain ISM managed indices")
+	}
+
+	indices = []string{}
+	for index, raw := range res {
+		if index == "total_managed_indices" {
+			continue
+		}
+		explain := &struct {
+			PolicyID string `json:"index.plugins.index_state_management.policy_id"`
+		}{}
+		if err = json.Unmarshal(raw, explain); err != nil {
+			return nil, errors.Wrapf(err, "Error when decode ISM explain of index %s", index)
+		}
+		if explain.PolicyID == name {
+			indices = append(indices, index)
+		}
+	}
+
+	return indices, nil
+}
+
+// ChangeIsmPolicy permit to apply the last version of index state management policy on managed indices
+// Opensearch switch to the new policy when the current state of index is completed
+func (c *Client) ChangeIsmPolicy(ctx context.Context, name string, indices []string) (err error) {
+	if len(indices) == 0 {
+		return nil
+	}
+
+	res := &struct {
+		Failures      bool `json:"failures"`
+		FailedIndices []struct {
+			IndexName string `json:"index_name"`
+			Reason    string `json:"reason"`
+		} `json:"failed_indices"`
+	}{}
+	if err = c.do(ctx, http.MethodPost, fmt.Sprintf("/_plugins/_ism/change_policy/%s", url.PathEscape(strings.Join(indices, ","))), map[string]any{"policy_id": name}, res); err != nil {
+		return errors.Wrapf(err, "Error when change ISM policy %s on indices %s", name, strings.Join(indices, ","))
+	}
+
+	if res.Failures {
+		reasons := make([]string, 0, len(res.FailedIndices))
+		for _, failedIndex := range res.FailedIndices {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", failedIndex.IndexName, failedIndex.Reason))
+		}
+		return errors.Errorf("Error when change ISM policy %s on indices: %s", name, strings.Join(reasons, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsmPolicy(t *testing.T) {
+	var policy map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch r.Method {
+		case "GET":
+			if policy == nil {
+				return 404, map[string]any{"error": "not_found"}
+			}
+			return 200, map[string]any{
+				"_id":           "logs",
+				"_seq_no":       7,
+				"_primary_term": 1,
+				"policy":        policy,
+			}
+		case "PUT":
+			policy = r.Body["policy"].(map[string]any)
+			// Opensearch add default values
+			for _, state := range policy["states"].([]any) {
+				for _, action := range state.(map[string]any)["actions"].([]any) {
+					action.(map[string]any)["retry"] = map[string]any{
+						"count":   3,
+						"backoff": "exponential",
+						"delay":   "1m",
+					}
+				}
+			}
+			for _, ismTemplate := range policy["ism_template"].([]any) {
+				ismTemplate.(map[string]any)["last_updated_time"] = 1667260800000
+			}
+			return 201, map[string]any{}
+		case "DELETE":
+			if policy == nil {
+				return 404, map[string]any{"error": "not_found"}
+			}
+			policy = nil
+			return 200, map[string]any{}
+		}
+
+		return 400, nil
+	})
+
+	// When policy not exist
+	current, err := client.GetIsmPolicy(context.Background(), "logs")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create policy
+	expected := &IsmPolicy{
+		Description:  "Logs retention",
+		DefaultState: "hot",
+		States: []IsmState{
+			{
+				Name: "hot",
+				Actions: []map[string]any{
+					{
+						"rollover": map[string]any{
+							"min_size": "50gb",
+						},
+					},
+				},
+				Transitions: []IsmTransition{
+					{
+						StateName: "delete",
+						Conditions: &IsmTransitionConditions{
+							MinIndexAge: "30d",
+						},
+					},
+				},
+			},
+			{
+				Name: "delete",
+				Actions: []map[string]any{
+					{
+						"delete": map[string]any{},
+					},
+				},
+				Transitions: []IsmTransition{},
+			},
+		},
+		IsmTemplate: []IsmTemplate{
+			{
+				IndexPatterns: []string{"logs-*"},
+				Priority:      100,
+			},
+		},
+	}
+	err = client.CreateIsmPolicy(context.Background(), "logs", expected)
+	assert.NoError(t, err)
+	assert.Equal(t, "/_plugins/_ism/policies/logs", (*requests)[1].Path)
+	assert.Empty(t, (*requests)[1].Query)
+
+	// Get policy without default values
+	current, err = client.GetIsmPolicy(context.Background(), "logs")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), current.SeqNo)
+	assert.Equal(t, int64(1), current.PrimaryTerm)
+	assert.Equal(t, *expected, current.Policy)
+
+	// Update policy
+	err = client.UpdateIsmPolicy(context.Background(), "logs", expected, 7, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "if_seq_no=7&if_primary_term=1", (*requests)[3].Query)
+
+	// Delete policy
+	err = client.DeleteIsmPolicy(context.Background(), "logs")
+	assert.NoError(t, err)
+
+	// Delete policy that not exist
+	err = client.DeleteIsmPolicy(context.Background(), "logs")
+	assert.NoError(t, err)
+}
+
+func TestIsmManagedIndices(t *testing.T) {
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch {
+		case r.Method == "GET" && r.Path == "/_plugins/_ism/explain/*":
+			return 200, map[string]any{
+				"logs-000001": map[string]any{
+					"index.plugins.index_state_management.policy_id": "logs",
+				},
+				"metrics-000001": map[string]any{
+					"index.plugins.index_state_management.policy_id": "metrics",
+				},
+				"total_managed_indices": 2,
+			}
+		case r.Method == "POST" && r.Path == "/_plugins/_ism/change_policy/logs-000001":
+			return 200, map[string]any{
+				"updated_indices": 1,
+				"failures":        false,
+				"failed_indices":  []any{},
+			}
+		case r.Method == "POST":
+			return 200, map[string]any{
+				"updated_indices": 0,
+				"failures":        true,
+				"failed_indices": []map[string]any{
+					{
+						"index_name": "metrics-000001",
+						"reason":     "This index is not being managed",
+					},
+				},
+			}
+		}
+
+		return 400, nil
+	})
+
+	// Get managed indices
+	indices, err := client.GetIsmManagedIndices(context.Background(), "logs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"logs-000001"}, indices)
+
+	// Change policy
+	err = client.ChangeIsmPolicy(context.Background(), "logs", indices)
+	assert.NoError(t, err)
+	assert.Equal(t, "logs", (*requests)[1].Body["policy_id"])
+
+	// When no indices
+	err = client.ChangeIsmPolicy(context.Background(), "logs", nil)
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 2)
+
+	// When failed
+	err = client.ChangeIsmPolicy(context.Background(), "metrics", []string{"metrics-000001"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package opensearch
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// RemoteClusterInfo is the connection state of remote cluster
+type RemoteClusterInfo struct {
+	Connected       bool     `json:"connected"`
+	Mode            string   `json:"mode,omitempty"`
+	Seeds           []string `json:"seeds,omitempty"`
+	SkipUnavailable bool     `json:"skip_unavailable"`
+}
+
+// AutoFollowRule is the cross cluster replication rule that start replication of leader indices that match the pattern
+type AutoFollowRule struct {
+	LeaderAlias string            `json:"leader_alias"`
+	Name        string            `json:"name"`
+	Pattern     string            `json:"pattern,omitempty"`
+	UseRoles    *ReplicationRoles `json:"use_roles,omitempty"`
+}
+
+// ReplicationRoles is the security roles used by replication on leader and follower clusters
+type ReplicationRoles struct {
+	LeaderClusterRole   string `json:"leader_cluster_role"`
+	FollowerClusterRole string `json:"follower_cluster_role"`
+}
+
+// AutoFollowRuleStats is the state of auto follow rule
+type AutoFollowRuleStats struct {
+	Name                       string   `json:"name"`
+	Pattern                    string   `json:"pattern"`
+	NumSuccessStartReplication int64    `json:"num_success_start_replication"`
+	NumFailedStartReplication  int64    `json:"num_failed_start_replication"`
+	FailedIndices              []string `json:"failed_indices,omitempty"`
+}
+
+// autoFollowStatsResponse is the response of auto follow stats API
+type autoFollowStatsResponse struct {
+	AutoFollowStats []AutoFollowRuleStats `json:"autofollow_stats"`
+}
+
+// GetRemoteClusters permit to get the connection state of remote clusters
+func (c *Client) GetRemoteClusters(ctx context.Context) (remoteClusters map[string]RemoteClusterInfo, err error) {
+	remoteClusters = map[string]RemoteClusterInfo{}
+	if err = c.do(ctx, http.MethodGet, "/_remote/info", nil, &remoteClusters); err != nil {
+		return nil, errors.Wrap(err, "Error when get remote clusters")
+	}
+
+	return remoteClusters, nil
+}
+
+// GetAutoFollowRules permit to get the auto follow rules from cross cluster replication stats
+// The stats not return the leader alias, so the rule name must be unique
+func (c *Client) GetAutoFollowRules(ctx context.Context) (rules map[string]AutoFollowRuleStats, err error) {
+	response := &autoFollowStatsResponse{}
+	if err = c.do(ctx, http.MethodGet, "/_plugins/_replication/autofollow_stats", nil, response); err != nil {
+		return nil, errors.Wrap(err, "Error when get auto follow rules")
+	}
+
+	rules = make(map[string]AutoFollowRuleStats, len(response.AutoFollowStats))
+	for _, rule := range response.AutoFollowStats {
+		rules[rule.Name] = rule
+	}
+
+	return rules, nil
+}
+
+// CreateAutoFollowRule permit to create auto follow rule
+// The existing leader indices that match the pattern are also replicated
+func (c *Client) CreateAutoFollowRule(ctx context.Context, rule *AutoFollowRule) (err error) {
+	if err = c.do(ctx, http.MethodPost, "/_plugins/_replication/_autofollow", rule, nil); err != nil {
+		return errors.Wrapf(err, "Error when create auto follow rule %s", rule.Name)
+	}
+
+	return nil
+}
+
+// DeleteAutoFollowRule permit to delete auto follow rule
+// The indices already replicated are kept and continue to be replicated
+func (c *Client) DeleteAutoFollowRule(ctx context.Context, leaderAlias string, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, "/_plugins/_replication/_autofollow", &AutoFollowRule{LeaderAlias: leaderAlias, Name: name}, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete auto follow rule %s", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRemoteClusters(t *testing.T) {
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		return 200, map[string]any{
+			"leader": map[string]any{
+				"connected":        true,
+				"mode":             "sniff",
+				"seeds":            []string{"test-master-os-headless.default.svc:9300"},
+				"skip_unavailable": false,
+			},
+		}
+	})
+
+	remoteClusters, err := client.GetRemoteClusters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "/_remote/info", (*requests)[0].Path)
+	assert.Equal(t, map[string]RemoteClusterInfo{
+		"leader": {
+			Connected: true,
+			Mode:      "sniff",
+			Seeds:     []string{"test-master-os-headless.default.svc:9300"},
+		},
+	}, remoteClusters)
+}
+
+func TestAutoFollowRule(t *testing.T) {
+	rules := []map[string]any{}
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch {
+		case r.Method == "GET" && r.Path == "/_plugins/_replication/autofollow_stats":
+			return 200, map[string]any{"autofollow_stats": rules}
+		case r.Method == "POST" && r.Path == "/_plugins/_replication/_autofollow":
+			rules = append(rules, map[string]any{
+				"name":                          r.Body["name"],
+				"pattern":                       r.Body["pattern"],
+				"num_success_start_replication": 0,
+			})
+			return 200, map[string]any{"acknowledged": true}
+		case r.Method == "DELETE" && r.Path == "/_plugins/_replication/_autofollow":
+			for i, rule := range rules {
+				if rule["name"] == r.Body["name"] {
+					rules = append(rules[:i], rules[i+1:]...)
+					return 200, map[string]any{"acknowledged": true}
+				}
+			}
+			return 404, map[string]any{"error": "resource_not_found_exception"}
+		}
+
+		return 400, nil
+	})
+
+	// Create rule
+	err := client.CreateAutoFollowRule(context.Background(), &AutoFollowRule{
+		LeaderAlias: "leader",
+		Name:        "logs",
+		Pattern:     "logs-*",
+		UseRoles: &ReplicationRoles{
+			LeaderClusterRole:   "leader_role",
+			FollowerClusterRole: "follower_role",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"leader_alias": "leader",
+		"name":         "logs",
+		"pattern":      "logs-*",
+		"use_roles": map[string]any{
+			"leader_cluster_role":   "leader_role",
+			"follower_cluster_role": "follower_role",
+		},
+	}, (*requests)[0].Body)
+
+	// Get rules
+	currentRules, err := client.GetAutoFollowRules(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]AutoFollowRuleStats{
+		"logs": {
+			Name:    "logs",
+			Pattern: "logs-*",
+		},
+	}, currentRules)
+
+	// Delete rule
+	err = client.DeleteAutoFollowRule(context.Background(), "leader", "logs")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"leader_alias": "leader", "name": "logs"}, (*requests)[2].Body)
+
+	// Delete rule that not exist
+	err = client.DeleteAutoFollowRule(context.Background(), "leader", "logs")
+	assert.NoError(t, err)
+
+	currentRules, err = client.GetAutoFollowRules(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, currentRules)
+}
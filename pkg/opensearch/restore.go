@@ -0,0 +1,55 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// RestoreRequest is the settings of snapshot restore
+type RestoreRequest struct {
+	Indices            string `json:"indices,omitempty"`
+	RenamePattern      string `json:"rename_pattern,omitempty"`
+	RenameReplacement  string `json:"rename_replacement,omitempty"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+	IgnoreUnavailable  bool   `json:"ignore_unavailable"`
+}
+
+// RecoveryShard is the recovery state of shard
+type RecoveryShard struct {
+	Type  string `json:"type"`
+	Stage string `json:"stage"`
+}
+
+// RestoreSnapshot permit to start snapshot restore
+// It not wait the restore completion
+func (c *Client) RestoreSnapshot(ctx context.Context, repository string, snapshot string, request *RestoreRequest) (err error) {
+	if err = c.do(ctx, http.MethodPost, fmt.Sprintf("/_snapshot/%s/%s/_restore", url.PathEscape(repository), url.PathEscape(snapshot)), request, nil); err != nil {
+		return errors.Wrapf(err, "Error when restore snapshot %s from repository %s", snapshot, repository)
+	}
+
+	return nil
+}
+
+// CountActiveSnapshotRecoveries permit to count the shards that are being restored from snapshot
+func (c *Client) CountActiveSnapshotRecoveries(ctx context.Context) (count int, err error) {
+	recoveries := map[string]struct {
+		Shards []RecoveryShard `json:"shards"`
+	}{}
+	if err = c.do(ctx, http.MethodGet, "/_recovery?active_only=true", nil, &recoveries); err != nil {
+		return 0, errors.Wrap(err, "Error when get active recoveries")
+	}
+
+	for _, index := range recoveries {
+		for _, shard := range index.Shards {
+			if shard.Type == "SNAPSHOT" {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
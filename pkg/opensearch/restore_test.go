@@ -0,0 +1,61 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreSnapshot(t *testing.T) {
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		if r.Path == "/_snapshot/backup/missing/_restore" {
+			return 404, map[string]any{"error": "snapshot_missing_exception"}
+		}
+		return 200, map[string]any{"accepted": true}
+	})
+
+	err := client.RestoreSnapshot(context.Background(), "backup", "daily-2022.11.01", &RestoreRequest{
+		Indices:           "logs-*",
+		RenamePattern:     "(.+)",
+		RenameReplacement: "restored-$1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", (*requests)[0].Method)
+	assert.Equal(t, "/_snapshot/backup/daily-2022.11.01/_restore", (*requests)[0].Path)
+	assert.Equal(t, map[string]any{
+		"indices":              "logs-*",
+		"rename_pattern":       "(.+)",
+		"rename_replacement":   "restored-$1",
+		"include_global_state": false,
+		"ignore_unavailable":   false,
+	}, (*requests)[0].Body)
+
+	// When snapshot not exist
+	err = client.RestoreSnapshot(context.Background(), "backup", "missing", &RestoreRequest{})
+	assert.Error(t, err)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestCountActiveSnapshotRecoveries(t *testing.T) {
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		return 200, map[string]any{
+			"logs": map[string]any{
+				"shards": []map[string]any{
+					{"type": "SNAPSHOT", "stage": "INDEX"},
+					{"type": "PEER", "stage": "INDEX"},
+				},
+			},
+			"metrics": map[string]any{
+				"shards": []map[string]any{
+					{"type": "SNAPSHOT", "stage": "TRANSLOG"},
+				},
+			},
+		}
+	})
+
+	count, err := client.CountActiveSnapshotRecoveries(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "active_only=true", (*requests)[0].Query)
+}
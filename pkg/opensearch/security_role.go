@@ -0,0 +1,126 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Role is the role of security plugin
+type Role struct {
+	Reserved           bool               `json:"reserved,omitempty"`
+	Hidden             bool               `json:"hidden,omitempty"`
+	Static             bool               `json:"static,omitempty"`
+	Description        string             `json:"description,omitempty"`
+	ClusterPermissions []string           `json:"cluster_permissions,omitempty"`
+	IndexPermissions   []IndexPermission  `json:"index_permissions,omitempty"`
+	TenantPermissions  []TenantPermission `json:"tenant_permissions,omitempty"`
+}
+
+// IndexPermission is the permissions of role on index patterns
+type IndexPermission struct {
+	IndexPatterns  []string `json:"index_patterns"`
+	DLS            string   `json:"dls,omitempty"`
+	FLS            []string `json:"fls,omitempty"`
+	MaskedFields   []string `json:"masked_fields,omitempty"`
+	AllowedActions []string `json:"allowed_actions,omitempty"`
+}
+
+// TenantPermission is the permissions of role on tenant patterns
+type TenantPermission struct {
+	TenantPatterns []string `json:"tenant_patterns"`
+	AllowedActions []string `json:"allowed_actions,omitempty"`
+}
+
+// RoleMapping is the mapping between role and users, backend roles or hosts
+type RoleMapping struct {
+	Reserved     bool     `json:"reserved,omitempty"`
+	Hidden       bool     `json:"hidden,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Users        []string `json:"users,omitempty"`
+	BackendRoles []string `json:"backend_roles,omitempty"`
+	Hosts        []string `json:"hosts,omitempty"`
+}
+
+// GetRole permit to get role of security plugin
+// It return nil if role not exist
+func (c *Client) GetRole(ctx context.Context, name string) (role *Role, err error) {
+	roles := map[string]Role{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_plugins/_security/api/roles/%s", url.PathEscape(name)), nil, &roles); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get role %s", name)
+	}
+
+	r, ok := roles[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &r, nil
+}
+
+// PutRole permit to create or update role of security plugin
+func (c *Client) PutRole(ctx context.Context, name string, role *Role) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_plugins/_security/api/roles/%s", url.PathEscape(name)), role, nil); err != nil {
+		return errors.Wrapf(err, "Error when put role %s", name)
+	}
+
+	return nil
+}
+
+// DeleteRole permit to delete role of security plugin
+func (c *Client) DeleteRole(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_plugins/_security/api/roles/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete role %s", name)
+	}
+
+	return nil
+}
+
+// GetRoleMapping permit to get role mapping of security plugin
+// It return nil if role mapping not exist
+func (c *Client) GetRoleMapping(ctx context.Context, role string) (roleMapping *RoleMapping, err error) {
+	roleMappings := map[string]RoleMapping{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_plugins/_security/api/rolesmapping/%s", url.PathEscape(role)), nil, &roleMappings); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get role mapping %s", role)
+	}
+
+	rm, ok := roleMappings[role]
+	if !ok {
+		return nil, nil
+	}
+
+	return &rm, nil
+}
+
+// PutRoleMapping permit to create or update role mapping of security plugin
+func (c *Client) PutRoleMapping(ctx context.Context, role string, roleMapping *RoleMapping) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_plugins/_security/api/rolesmapping/%s", url.PathEscape(role)), roleMapping, nil); err != nil {
+		return errors.Wrapf(err, "Error when put role mapping %s", role)
+	}
+
+	return nil
+}
+
+// DeleteRoleMapping permit to delete role mapping of security plugin
+func (c *Client) DeleteRoleMapping(ctx context.Context, role string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_plugins/_security/api/rolesmapping/%s", url.PathEscape(role)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete role mapping %s", role)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole(t *testing.T) {
+	var role map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch r.Method {
+		case "GET":
+			if role == nil {
+				return 404, map[string]any{"status": "NOT_FOUND"}
+			}
+			return 200, map[string]any{
+				"logs-reader": role,
+			}
+		case "PUT":
+			role = r.Body
+			return 200, map[string]any{"status": "OK"}
+		case "DELETE":
+			if role == nil {
+				return 404, map[string]any{"status": "NOT_FOUND"}
+			}
+			role = nil
+			return 200, map[string]any{"status": "OK"}
+		}
+
+		return 400, nil
+	})
+
+	// When role not exist
+	current, err := client.GetRole(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create role
+	expected := &Role{
+		ClusterPermissions: []string{"cluster_composite_ops_ro"},
+		IndexPermissions: []IndexPermission{
+			{
+				IndexPatterns:  []string{"logs-*"},
+				DLS:            `{"term": {"team": "ops"}}`,
+				FLS:            []string{"~secret"},
+				MaskedFields:   []string{"ip"},
+				AllowedActions: []string{"read"},
+			},
+		},
+		TenantPermissions: []TenantPermission{
+			{
+				TenantPatterns: []string{"ops"},
+				AllowedActions: []string{"kibana_all_read"},
+			},
+		},
+	}
+	err = client.PutRole(context.Background(), "logs-reader", expected)
+	assert.NoError(t, err)
+	assert.Equal(t, "/_plugins/_security/api/roles/logs-reader", (*requests)[1].Path)
+
+	// Get role
+	current, err = client.GetRole(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, current)
+
+	// Delete role
+	err = client.DeleteRole(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+
+	// Delete role that not exist
+	err = client.DeleteRole(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+}
+
+func TestRoleMapping(t *testing.T) {
+	var roleMapping map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch r.Method {
+		case "GET":
+			if roleMapping == nil {
+				return 404, map[string]any{"status": "NOT_FOUND"}
+			}
+			return 200, map[string]any{
+				"logs-reader": roleMapping,
+			}
+		case "PUT":
+			roleMapping = r.Body
+			return 200, map[string]any{"status": "OK"}
+		case "DELETE":
+			if roleMapping == nil {
+				return 404, map[string]any{"status": "NOT_FOUND"}
+			}
+			roleMapping = nil
+			return 200, map[string]any{"status": "OK"}
+		}
+
+		return 400, nil
+	})
+
+	// When role mapping not exist
+	current, err := client.GetRoleMapping(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create role mapping
+	expected := &RoleMapping{
+		Users:        []string{"john"},
+		BackendRoles: []string{"ops"},
+		Hosts:        []string{"*.cluster.local"},
+	}
+	err = client.PutRoleMapping(context.Background(), "logs-reader", expected)
+	assert.NoError(t, err)
+	assert.Equal(t, "/_plugins/_security/api/rolesmapping/logs-reader", (*requests)[1].Path)
+
+	// Get role mapping
+	current, err = client.GetRoleMapping(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, current)
+
+	// Delete role mapping
+	err = client.DeleteRoleMapping(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+
+	// Delete role mapping that not exist
+	err = client.DeleteRoleMapping(context.Background(), "logs-reader")
+	assert.NoError(t, err)
+}
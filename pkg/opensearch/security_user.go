@@ -0,0 +1,62 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// InternalUser is the user stored on internal database of security plugin
+type InternalUser struct {
+	Password     string            `json:"password,omitempty"`
+	Hash         string            `json:"hash,omitempty"`
+	Reserved     bool              `json:"reserved,omitempty"`
+	Hidden       bool              `json:"hidden,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	BackendRoles []string          `json:"backend_roles,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+}
+
+// GetInternalUser permit to get user from internal database of security plugin
+// It return nil if user not exist
+func (c *Client) GetInternalUser(ctx context.Context, name string) (user *InternalUser, err error) {
+	users := map[string]InternalUser{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_plugins/_security/api/internalusers/%s", url.PathEscape(name)), nil, &users); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get internal user %s", name)
+	}
+
+	u, ok := users[name]
+	if !ok {
+		return nil, nil
+	}
+
+	return &u, nil
+}
+
+// PutInternalUser permit to create or update user on internal database of security plugin
+// The password is needed to create user
+func (c *Client) PutInternalUser(ctx context.Context, name string, user *InternalUser) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_plugins/_security/api/internalusers/%s", url.PathEscape(name)), user, nil); err != nil {
+		return errors.Wrapf(err, "Error when put internal user %s", name)
+	}
+
+	return nil
+}
+
+// DeleteInternalUser permit to delete user from internal database of security plugin
+func (c *Client) DeleteInternalUser(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_plugins/_security/api/internalusers/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete internal user %s", name)
+	}
+
+	return nil
+}
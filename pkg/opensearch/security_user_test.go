@@ -0,0 +1,77 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternalUser(t *testing.T) {
+	var user map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch r.Method {
+		case "GET":
+			if user == nil {
+				return 404, map[string]any{"status": "NOT_FOUND"}
+			}
+			return 200, map[string]any{
+				"john": user,
+			}
+		case "PUT":
+			user = map[string]any{}
+			for key, value := range r.Body {
+				if key != "password" {
+					user[key] = value
+				}
+			}
+			return 200, map[string]any{"status": "OK"}
+		case "DELETE":
+			if user == nil {
+				return 404, map[string]any{"status": "NOT_FOUND"}
+			}
+			user = nil
+			return 200, map[string]any{"status": "OK"}
+		}
+
+		return 400, nil
+	})
+
+	// When user not exist
+	current, err := client.GetInternalUser(context.Background(), "john")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create user
+	err = client.PutInternalUser(context.Background(), "john", &InternalUser{
+		Password:     "password",
+		Description:  "John",
+		BackendRoles: []string{"admin"},
+		Attributes: map[string]string{
+			"team": "ops",
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/_plugins/_security/api/internalusers/john", (*requests)[1].Path)
+	assert.Equal(t, "password", (*requests)[1].Body["password"])
+
+	// Get user
+	current, err = client.GetInternalUser(context.Background(), "john")
+	assert.NoError(t, err)
+	assert.Equal(t, &InternalUser{
+		Description:  "John",
+		BackendRoles: []string{"admin"},
+		Attributes: map[string]string{
+			"team": "ops",
+		},
+	}, current)
+
+	// Delete user
+	err = client.DeleteInternalUser(context.Background(), "john")
+	assert.NoError(t, err)
+
+	// Delete user that not exist
+	err = client.DeleteInternalUser(context.Background(), "john")
+	assert.NoError(t, err)
+}
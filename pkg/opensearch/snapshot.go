@@ -0,0 +1,71 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotRepository is the snapshot repository
+type SnapshotRepository struct {
+	Type     string            `json:"type"`
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// VerifySnapshotRepositoryResponse is the nodes that can access to the repository
+type VerifySnapshotRepositoryResponse struct {
+	Nodes map[string]struct {
+		Name string `json:"name"`
+	} `json:"nodes"`
+}
+
+// GetSnapshotRepositories permit to get all snapshot repositories
+func (c *Client) GetSnapshotRepositories(ctx context.Context) (repositories map[string]SnapshotRepository, err error) {
+	repositories = map[string]SnapshotRepository{}
+	if err = c.do(ctx, http.MethodGet, "/_snapshot", nil, &repositories); err != nil {
+		return nil, errors.Wrap(err, "Error when get snapshot repositories")
+	}
+
+	return repositories, nil
+}
+
+// PutSnapshotRepository permit to create or update snapshot repository
+func (c *Client) PutSnapshotRepository(ctx context.Context, name string, repository *SnapshotRepository) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_snapshot/%s", url.PathEscape(name)), repository, nil); err != nil {
+		return errors.Wrapf(err, "Error when put snapshot repository %s", name)
+	}
+
+	return nil
+}
+
+// DeleteSnapshotRepository permit to unregister snapshot repository
+// Snapshots are kept on repository
+func (c *Client) DeleteSnapshotRepository(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_snapshot/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete snapshot repository %s", name)
+	}
+
+	return nil
+}
+
+// VerifySnapshotRepository permit to check that all nodes can access to snapshot repository
+// It return the node names that can access to the repository
+func (c *Client) VerifySnapshotRepository(ctx context.Context, name string) (nodeNames []string, err error) {
+	response := &VerifySnapshotRepositoryResponse{}
+	if err = c.do(ctx, http.MethodPost, fmt.Sprintf("/_snapshot/%s/_verify", url.PathEscape(name)), nil, response); err != nil {
+		return nil, errors.Wrapf(err, "Error when verify snapshot repository %s", name)
+	}
+
+	nodeNames = make([]string, 0, len(response.Nodes))
+	for _, node := range response.Nodes {
+		nodeNames = append(nodeNames, node.Name)
+	}
+
+	return nodeNames, nil
+}
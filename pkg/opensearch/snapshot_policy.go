@@ -0,0 +1,189 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotManagementPolicy is the snapshot management policy
+type SnapshotManagementPolicy struct {
+	Description    string                      `json:"description,omitempty"`
+	Enabled        *bool                       `json:"enabled,omitempty"`
+	Creation       SnapshotManagementCreation  `json:"creation"`
+	Deletion       *SnapshotManagementDeletion `json:"deletion,omitempty"`
+	SnapshotConfig SnapshotManagementConfig    `json:"snapshot_config"`
+}
+
+// SnapshotManagementSchedule is the cron schedule of snapshot management policy
+type SnapshotManagementSchedule struct {
+	Cron SnapshotManagementCron `json:"cron"`
+}
+
+// SnapshotManagementCron is the cron expression with its timezone
+type SnapshotManagementCron struct {
+	Expression string `json:"expression"`
+	Timezone   string `json:"timezone,omitempty"`
+}
+
+// SnapshotManagementCreation is the snapshot creation schedule
+type SnapshotManagementCreation struct {
+	Schedule  SnapshotManagementSchedule `json:"schedule"`
+	TimeLimit string                     `json:"time_limit,omitempty"`
+}
+
+// SnapshotManagementDeletion is the snapshot deletion schedule and retention
+type SnapshotManagementDeletion struct {
+	Schedule  *SnapshotManagementSchedule         `json:"schedule,omitempty"`
+	Condition SnapshotManagementDeletionCondition `json:"condition"`
+	TimeLimit string                              `json:"time_limit,omitempty"`
+}
+
+// SnapshotManagementDeletionCondition is the snapshot retention
+type SnapshotManagementDeletionCondition struct {
+	MaxAge   string `json:"max_age,omitempty"`
+	MaxCount *int64 `json:"max_count,omitempty"`
+	MinCount *int64 `json:"min_count,omitempty"`
+}
+
+// SnapshotManagementConfig is the snapshot settings
+type SnapshotManagementConfig struct {
+	Repository         string `json:"repository"`
+	Indices            string `json:"indices,omitempty"`
+	DateFormat         string `json:"date_format,omitempty"`
+	Timezone           string `json:"timezone,omitempty"`
+	IgnoreUnavailable  bool   `json:"ignore_unavailable"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+	Partial            bool   `json:"partial"`
+}
+
+// SnapshotManagementPolicyResponse is the snapshot management policy with its version
+// The sequence number and primary term are needed to update policy
+type SnapshotManagementPolicyResponse struct {
+	SeqNo       int64                    `json:"_seq_no"`
+	PrimaryTerm int64                    `json:"_primary_term"`
+	Policy      SnapshotManagementPolicy `json:"sm_policy"`
+}
+
+// SnapshotManagementExplain is the state of snapshot management policy
+type SnapshotManagementExplain struct {
+	Name     string                          `json:"name"`
+	Enabled  bool                            `json:"enabled"`
+	Creation *SnapshotManagementExplainState `json:"creation,omitempty"`
+	Deletion *SnapshotManagementExplainState `json:"deletion,omitempty"`
+}
+
+// SnapshotManagementExplainState is the state of creation or deletion workflow
+type SnapshotManagementExplainState struct {
+	CurrentState string `json:"current_state"`
+	Trigger      struct {
+		Time int64 `json:"time"`
+	} `json:"trigger"`
+	LatestExecution *SnapshotManagementExecution `json:"latest_execution,omitempty"`
+}
+
+// SnapshotManagementExecution is the latest execution of creation or deletion workflow
+type SnapshotManagementExecution struct {
+	Status    string `json:"status"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Info      struct {
+		Message string `json:"message"`
+		Cause   string `json:"cause"`
+	} `json:"info"`
+}
+
+// Snapshot is the snapshot stored on repository
+type Snapshot struct {
+	Snapshot         string   `json:"snapshot"`
+	State            string   `json:"state"`
+	Indices          []string `json:"indices,omitempty"`
+	StartTimeInMilis int64    `json:"start_time_in_millis"`
+	EndTimeInMilis   int64    `json:"end_time_in_millis"`
+}
+
+// GetSnapshotManagementPolicy permit to get snapshot management policy
+// It return nil if policy not exist
+func (c *Client) GetSnapshotManagementPolicy(ctx context.Context, name string) (policy *SnapshotManagementPolicyResponse, err error) {
+	policy = &SnapshotManagementPolicyResponse{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_plugins/_sm/policies/%s", url.PathEscape(name)), nil, policy); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get snapshot management policy %s", name)
+	}
+
+	return policy, nil
+}
+
+// CreateSnapshotManagementPolicy permit to create snapshot management policy
+func (c *Client) CreateSnapshotManagementPolicy(ctx context.Context, name string, policy *SnapshotManagementPolicy) (err error) {
+	if err = c.do(ctx, http.MethodPost, fmt.Sprintf("/_plugins/_sm/policies/%s", url.PathEscape(name)), policy, nil); err != nil {
+		return errors.Wrapf(err, "Error when create snapshot management policy %s", name)
+	}
+
+	return nil
+}
+
+// UpdateSnapshotManagementPolicy permit to update snapshot management policy
+// It use the sequence number and primary term of current policy to avoid concurrent update
+func (c *Client) UpdateSnapshotManagementPolicy(ctx context.Context, name string, policy *SnapshotManagementPolicy, seqNo int64, primaryTerm int64) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_plugins/_sm/policies/%s?if_seq_no=%d&if_primary_term=%d", url.PathEscape(name), seqNo, primaryTerm), policy, nil); err != nil {
+		return errors.Wrapf(err, "Error when update snapshot management policy %s", name)
+	}
+
+	return nil
+}
+
+// DeleteSnapshotManagementPolicy permit to delete snapshot management policy
+// Snapshots are kept on repository
+func (c *Client) DeleteSnapshotManagementPolicy(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_plugins/_sm/policies/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete snapshot management policy %s", name)
+	}
+
+	return nil
+}
+
+// ExplainSnapshotManagementPolicy permit to get the state of snapshot management policy
+// It return nil if policy not exist
+func (c *Client) ExplainSnapshotManagementPolicy(ctx context.Context, name string) (explain *SnapshotManagementExplain, err error) {
+	response := &struct {
+		Policies []SnapshotManagementExplain `json:"policies"`
+	}{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_plugins/_sm/policies/%s/_explain", url.PathEscape(name)), nil, response); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when explain snapshot management policy %s", name)
+	}
+
+	for _, policy := range response.Policies {
+		if policy.Name == name {
+			return &policy, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetSnapshots permit to get the snapshots of repository that match the pattern
+func (c *Client) GetSnapshots(ctx context.Context, repository string, pattern string) (snapshots []Snapshot, err error) {
+	response := &struct {
+		Snapshots []Snapshot `json:"snapshots"`
+	}{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_snapshot/%s/%s", url.PathEscape(repository), url.PathEscape(pattern)), nil, response); err != nil {
+		if IsNotFound(err) {
+			return []Snapshot{}, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get snapshots %s on repository %s", pattern, repository)
+	}
+
+	return response.Snapshots, nil
+}
@@ -0,0 +1,139 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+)
+
+func TestSnapshotManagementPolicy(t *testing.T) {
+	var policy map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch {
+		case r.Method == "GET" && r.Path == "/_plugins/_sm/policies/daily/_explain":
+			return 200, map[string]any{
+				"policies": []map[string]any{
+					{
+						"name":    "daily",
+						"enabled": true,
+						"creation": map[string]any{
+							"current_state": "CREATION_START",
+							"trigger": map[string]any{
+								"time": 1667260800000,
+							},
+							"latest_execution": map[string]any{
+								"status":     "FAILED",
+								"start_time": 1667174400000,
+								"end_time":   1667174460000,
+								"info": map[string]any{
+									"message": "Snapshot creation failed",
+									"cause":   "repository_missing_exception",
+								},
+							},
+						},
+					},
+				},
+			}
+		case r.Method == "GET":
+			if policy == nil {
+				return 404, map[string]any{"error": "not_found"}
+			}
+			return 200, map[string]any{
+				"_seq_no":       3,
+				"_primary_term": 1,
+				"sm_policy":     policy,
+			}
+		case r.Method == "POST" || r.Method == "PUT":
+			policy = r.Body
+			return 200, map[string]any{}
+		case r.Method == "DELETE":
+			if policy == nil {
+				return 404, map[string]any{"error": "not_found"}
+			}
+			policy = nil
+			return 200, map[string]any{}
+		}
+
+		return 400, nil
+	})
+
+	// When policy not exist
+	current, err := client.GetSnapshotManagementPolicy(context.Background(), "daily")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create policy
+	expected := &SnapshotManagementPolicy{
+		Enabled: pointer.Bool(true),
+		Creation: SnapshotManagementCreation{
+			Schedule: SnapshotManagementSchedule{
+				Cron: SnapshotManagementCron{
+					Expression: "0 0 * * *",
+					Timezone:   "UTC",
+				},
+			},
+		},
+		Deletion: &SnapshotManagementDeletion{
+			Condition: SnapshotManagementDeletionCondition{
+				MaxCount: pointer.Int64(7),
+			},
+		},
+		SnapshotConfig: SnapshotManagementConfig{
+			Repository: "backup",
+			Indices:    "*",
+		},
+	}
+	err = client.CreateSnapshotManagementPolicy(context.Background(), "daily", expected)
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", (*requests)[1].Method)
+
+	// Get policy
+	current, err = client.GetSnapshotManagementPolicy(context.Background(), "daily")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), current.SeqNo)
+	assert.Equal(t, int64(1), current.PrimaryTerm)
+	assert.Equal(t, *expected, current.Policy)
+
+	// Update policy
+	err = client.UpdateSnapshotManagementPolicy(context.Background(), "daily", expected, current.SeqNo, current.PrimaryTerm)
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", (*requests)[3].Method)
+	assert.Equal(t, "if_seq_no=3&if_primary_term=1", (*requests)[3].Query)
+
+	// Explain policy
+	explain, err := client.ExplainSnapshotManagementPolicy(context.Background(), "daily")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1667260800000), explain.Creation.Trigger.Time)
+	assert.Equal(t, "FAILED", explain.Creation.LatestExecution.Status)
+	assert.Equal(t, "repository_missing_exception", explain.Creation.LatestExecution.Info.Cause)
+
+	// Delete policy
+	err = client.DeleteSnapshotManagementPolicy(context.Background(), "daily")
+	assert.NoError(t, err)
+	err = client.DeleteSnapshotManagementPolicy(context.Background(), "daily")
+	assert.NoError(t, err)
+}
+
+func TestGetSnapshots(t *testing.T) {
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		return 200, map[string]any{
+			"snapshots": []map[string]any{
+				{
+					"snapshot":             "daily-2022.11.01",
+					"state":                "SUCCESS",
+					"start_time_in_millis": 1667260800000,
+					"end_time_in_millis":   1667260860000,
+				},
+			},
+		}
+	})
+
+	snapshots, err := client.GetSnapshots(context.Background(), "backup", "daily-*")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(snapshots))
+	assert.Equal(t, "SUCCESS", snapshots[0].State)
+	assert.Equal(t, "/_snapshot/backup/daily-*", (*requests)[0].Path)
+}
@@ -0,0 +1,105 @@
+package opensearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeSnapshotOpensearch permit to start fake Opensearch API that store repositories on memory
+// The verify API check that fs repository location is writable, like Opensearch nodes
+func newFakeSnapshotOpensearch(t *testing.T) (client *Client, requests *[]request) {
+	repositories := map[string]SnapshotRepository{}
+
+	return newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		name := strings.TrimPrefix(r.Path, "/_snapshot/")
+		switch {
+		case r.Method == "GET" && r.Path == "/_snapshot":
+			return 200, repositories
+		case r.Method == "PUT":
+			settings := map[string]string{}
+			if s, ok := r.Body["settings"].(map[string]any); ok {
+				for key, value := range s {
+					settings[key] = value.(string)
+				}
+			}
+			repositories[name] = SnapshotRepository{
+				Type:     r.Body["type"].(string),
+				Settings: settings,
+			}
+			return 200, map[string]any{"acknowledged": true}
+		case r.Method == "DELETE":
+			if _, ok := repositories[name]; !ok {
+				return 404, map[string]any{"error": "repository_missing_exception"}
+			}
+			delete(repositories, name)
+			return 200, map[string]any{"acknowledged": true}
+		case r.Method == "POST":
+			name = strings.TrimSuffix(name, "/_verify")
+			repository, ok := repositories[name]
+			if !ok {
+				return 404, map[string]any{"error": "repository_missing_exception"}
+			}
+			if err := os.WriteFile(filepath.Join(repository.Settings["location"], "verify"), []byte("test"), 0600); err != nil {
+				return 500, map[string]any{"error": "repository_verification_exception"}
+			}
+			return 200, map[string]any{"nodes": map[string]any{"abc": map[string]any{"name": "test-master-os-0"}}}
+		}
+
+		return 400, nil
+	})
+}
+
+func TestSnapshotRepository(t *testing.T) {
+	client, requests := newFakeSnapshotOpensearch(t)
+	location := t.TempDir()
+
+	// Register fs repository
+	err := client.PutSnapshotRepository(context.Background(), "backup", &SnapshotRepository{
+		Type: "fs",
+		Settings: map[string]string{
+			"location": location,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/_snapshot/backup", (*requests)[0].Path)
+
+	// Get repositories
+	repositories, err := client.GetSnapshotRepositories(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "fs", repositories["backup"].Type)
+	assert.Equal(t, location, repositories["backup"].Settings["location"])
+
+	// Verify repository
+	nodeNames, err := client.VerifySnapshotRepository(context.Background(), "backup")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-master-os-0"}, nodeNames)
+	_, err = os.Stat(filepath.Join(location, "verify"))
+	assert.NoError(t, err)
+
+	// Verify repository with wrong location
+	err = client.PutSnapshotRepository(context.Background(), "wrong", &SnapshotRepository{
+		Type: "fs",
+		Settings: map[string]string{
+			"location": filepath.Join(location, "not-exist"),
+		},
+	})
+	assert.NoError(t, err)
+	_, err = client.VerifySnapshotRepository(context.Background(), "wrong")
+	assert.Error(t, err)
+
+	// Delete repository
+	err = client.DeleteSnapshotRepository(context.Background(), "wrong")
+	assert.NoError(t, err)
+	repositories, err = client.GetSnapshotRepositories(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(repositories))
+
+	// Delete repository that not exist
+	err = client.DeleteSnapshotRepository(context.Background(), "wrong")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,195 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Template is the settings, mappings and aliases applied on new indices
+type Template struct {
+	Settings map[string]any `json:"settings,omitempty"`
+	Mappings map[string]any `json:"mappings,omitempty"`
+	Aliases  map[string]any `json:"aliases,omitempty"`
+}
+
+// IndexTemplate is the composable index template
+type IndexTemplate struct {
+	IndexPatterns []string  `json:"index_patterns"`
+	Template      *Template `json:"template,omitempty"`
+	ComposedOf    []string  `json:"composed_of,omitempty"`
+	Priority      *int64    `json:"priority,omitempty"`
+	Version       *int64    `json:"version,omitempty"`
+}
+
+// ComponentTemplate is the component template used by composable index templates
+type ComponentTemplate struct {
+	Template *Template `json:"template"`
+	Version  *int64    `json:"version,omitempty"`
+}
+
+// GetIndexTemplate permit to get composable index template
+// It return nil if template not exist
+// The settings are normalized to be compared with expected template
+func (c *Client) GetIndexTemplate(ctx context.Context, name string) (template *IndexTemplate, err error) {
+	res := &struct {
+		IndexTemplates []struct {
+			Name          string        `json:"name"`
+			IndexTemplate IndexTemplate `json:"index_template"`
+		} `json:"index_templates"`
+	}{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_index_template/%s", url.PathEscape(name)), nil, res); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get index template %s", name)
+	}
+
+	for _, t := range res.IndexTemplates {
+		if t.Name == name {
+			template = &t.IndexTemplate
+			if template.Template != nil {
+				template.Template.Settings = NormalizeSettings(template.Template.Settings)
+			}
+			return template, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// PutIndexTemplate permit to create or update composable index template
+func (c *Client) PutIndexTemplate(ctx context.Context, name string, template *IndexTemplate) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_index_template/%s", url.PathEscape(name)), template, nil); err != nil {
+		return errors.Wrapf(err, "Error when put index template %s", name)
+	}
+
+	return nil
+}
+
+// DeleteIndexTemplate permit to delete composable index template
+func (c *Client) DeleteIndexTemplate(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_index_template/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete index template %s", name)
+	}
+
+	return nil
+}
+
+// GetComponentTemplate permit to get component template
+// It return nil if template not exist
+// The settings are normalized to be compared with expected template
+func (c *Client) GetComponentTemplate(ctx context.Context, name string) (template *ComponentTemplate, err error) {
+	res := &struct {
+		ComponentTemplates []struct {
+			Name              string            `json:"name"`
+			ComponentTemplate ComponentTemplate `json:"component_template"`
+		} `json:"component_templates"`
+	}{}
+	if err = c.do(ctx, http.MethodGet, fmt.Sprintf("/_component_template/%s", url.PathEscape(name)), nil, res); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Error when get component template %s", name)
+	}
+
+	for _, t := range res.ComponentTemplates {
+		if t.Name == name {
+			template = &t.ComponentTemplate
+			if template.Template != nil {
+				template.Template.Settings = NormalizeSettings(template.Template.Settings)
+			}
+			return template, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// PutComponentTemplate permit to create or update component template
+func (c *Client) PutComponentTemplate(ctx context.Context, name string, template *ComponentTemplate) (err error) {
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/_component_template/%s", url.PathEscape(name)), template, nil); err != nil {
+		return errors.Wrapf(err, "Error when put component template %s", name)
+	}
+
+	return nil
+}
+
+// DeleteComponentTemplate permit to delete component template
+func (c *Client) DeleteComponentTemplate(ctx context.Context, name string) (err error) {
+	if err = c.do(ctx, http.MethodDelete, fmt.Sprintf("/_component_template/%s", url.PathEscape(name)), nil, nil); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Error when delete component template %s", name)
+	}
+
+	return nil
+}
+
+// IsPriorityConflict return true if the error is returned because another index template match the same index patterns with the same priority
+func IsPriorityConflict(err error) bool {
+	var responseError *ResponseError
+	if errors.As(err, &responseError) {
+		return responseError.StatusCode == http.StatusBadRequest && strings.Contains(responseError.Body, "same priority")
+	}
+
+	return false
+}
+
+// NormalizeSettings permit to convert index settings as Opensearch return them
+// Keys are flattened with index prefix and values are converted to string
+func NormalizeSettings(settings map[string]any) map[string]any {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	flatSettings := map[string]any{}
+	flattenSettings("", settings, flatSettings)
+
+	res := map[string]any{}
+	for key, value := range flatSettings {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		res[key] = value
+	}
+
+	return res
+}
+
+func flattenSettings(prefix string, settings map[string]any, res map[string]any) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flattenSettings(key, v, res)
+		case []any:
+			values := make([]any, 0, len(v))
+			for _, item := range v {
+				values = append(values, settingToString(item))
+			}
+			res[key] = values
+		default:
+			res[key] = settingToString(v)
+		}
+	}
+}
+
+func settingToString(value any) string {
+	if v, ok := value.(float64); ok {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
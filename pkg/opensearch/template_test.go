@@ -0,0 +1,187 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+)
+
+func TestIndexTemplate(t *testing.T) {
+	var template map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch r.Method {
+		case "GET":
+			if template == nil {
+				return 404, map[string]any{"error": "resource_not_found_exception"}
+			}
+			return 200, map[string]any{
+				"index_templates": []map[string]any{
+					{
+						"name":           "logs",
+						"index_template": template,
+					},
+				},
+			}
+		case "PUT":
+			if r.Body["priority"] == float64(0) {
+				return 400, map[string]any{"error": "index template [logs] has index patterns [logs-*] matching patterns from existing templates [other] with patterns (other => [logs-*]) that have the same priority [0]"}
+			}
+			template = r.Body
+			// Opensearch return nested settings
+			template["template"] = map[string]any{
+				"settings": map[string]any{
+					"index": map[string]any{
+						"number_of_shards": "1",
+					},
+				},
+			}
+			return 200, map[string]any{"acknowledged": true}
+		case "DELETE":
+			if template == nil {
+				return 404, map[string]any{"error": "resource_not_found_exception"}
+			}
+			template = nil
+			return 200, map[string]any{"acknowledged": true}
+		}
+
+		return 400, nil
+	})
+
+	// When template not exist
+	current, err := client.GetIndexTemplate(context.Background(), "logs")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create template
+	expected := &IndexTemplate{
+		IndexPatterns: []string{"logs-*"},
+		Template: &Template{
+			Settings: NormalizeSettings(map[string]any{
+				"number_of_shards": 1,
+			}),
+		},
+		ComposedOf: []string{"logs-mappings"},
+		Priority:   pointer.Int64(100),
+	}
+	err = client.PutIndexTemplate(context.Background(), "logs", expected)
+	assert.NoError(t, err)
+	assert.Equal(t, "/_index_template/logs", (*requests)[1].Path)
+
+	// Get template
+	current, err = client.GetIndexTemplate(context.Background(), "logs")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, current)
+
+	// When priority conflict
+	expected.Priority = pointer.Int64(0)
+	err = client.PutIndexTemplate(context.Background(), "logs", expected)
+	assert.Error(t, err)
+	assert.True(t, IsPriorityConflict(err))
+
+	// Delete template
+	err = client.DeleteIndexTemplate(context.Background(), "logs")
+	assert.NoError(t, err)
+
+	// Delete template that not exist
+	err = client.DeleteIndexTemplate(context.Background(), "logs")
+	assert.NoError(t, err)
+}
+
+func TestComponentTemplate(t *testing.T) {
+	var template map[string]any
+
+	client, requests := newFakeOpensearch(t, func(r *request) (statusCode int, body any) {
+		switch r.Method {
+		case "GET":
+			if template == nil {
+				return 404, map[string]any{"error": "resource_not_found_exception"}
+			}
+			return 200, map[string]any{
+				"component_templates": []map[string]any{
+					{
+						"name":               "logs-mappings",
+						"component_template": template,
+					},
+				},
+			}
+		case "PUT":
+			template = r.Body
+			return 200, map[string]any{"acknowledged": true}
+		case "DELETE":
+			if template == nil {
+				return 404, map[string]any{"error": "resource_not_found_exception"}
+			}
+			template = nil
+			return 200, map[string]any{"acknowledged": true}
+		}
+
+		return 400, nil
+	})
+
+	// When template not exist
+	current, err := client.GetComponentTemplate(context.Background(), "logs-mappings")
+	assert.NoError(t, err)
+	assert.Nil(t, current)
+
+	// Create template
+	expected := &ComponentTemplate{
+		Template: &Template{
+			Mappings: map[string]any{
+				"properties": map[string]any{
+					"message": map[string]any{
+						"type": "text",
+					},
+				},
+			},
+			Aliases: map[string]any{
+				"logs": map[string]any{},
+			},
+		},
+		Version: pointer.Int64(1),
+	}
+	err = client.PutComponentTemplate(context.Background(), "logs-mappings", expected)
+	assert.NoError(t, err)
+	assert.Equal(t, "/_component_template/logs-mappings", (*requests)[1].Path)
+
+	// Get template
+	current, err = client.GetComponentTemplate(context.Background(), "logs-mappings")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, current)
+
+	// Delete template
+	err = client.DeleteComponentTemplate(context.Background(), "logs-mappings")
+	assert.NoError(t, err)
+
+	// Delete template that not exist
+	err = client.DeleteComponentTemplate(context.Background(), "logs-mappings")
+	assert.NoError(t, err)
+}
+
+func TestNormalizeSettings(t *testing.T) {
+	// When empty
+	assert.Nil(t, NormalizeSettings(nil))
+
+	// When settings are nested or flattened
+	assert.Equal(t, map[string]any{
+		"index.number_of_shards":                            "1",
+		"index.number_of_replicas":                          "0",
+		"index.refresh_interval":                            "5s",
+		"index.codec":                                       "best_compression",
+		"index.routing.allocation.include._tier_preference": []any{"data_hot", "data_warm"},
+		"index.max_result_window":                           "1000000",
+	}, NormalizeSettings(map[string]any{
+		"number_of_shards": float64(1),
+		"index": map[string]any{
+			"number_of_replicas": 0,
+			"refresh_interval":   "5s",
+		},
+		"index.codec": "best_compression",
+		"routing": map[string]any{
+			"allocation.include._tier_preference": []any{"data_hot", "data_warm"},
+		},
+		"max_result_window": float64(1000000),
+	}))
+}
@@ -1,11 +1,14 @@
 package pki
 
 import (
+	"crypto/x509"
 	"net"
+	"sort"
 
 	"github.com/disaster37/goca"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/thoas/go-funk"
 )
 
 const (
@@ -101,3 +104,40 @@ func NewApiTls(clusterName string, altNames, altIPs []string, ca *goca.CA, log *
 
 	return ca.IssueCertificate(clusterName, apiIdentity)
 }
+
+// NeedReissueApiTls permit to check if the subject alt names of the API certificate are not the expected ones
+// It return true if the certificate need to be reissued
+func NeedReissueApiTls(crt *x509.Certificate, clusterName string, altNames, altIPs []string, log *logrus.Entry) (status bool, err error) {
+	if crt == nil {
+		return false, errors.New("Cert must be provided")
+	}
+
+	expectedDNSNames := funk.UniqString(append([]string{clusterName}, altNames...))
+	currentDNSNames := funk.UniqString(crt.DNSNames)
+	sort.Strings(expectedDNSNames)
+	sort.Strings(currentDNSNames)
+
+	expectedIPs := make([]string, 0, len(altIPs))
+	for _, ipStr := range altIPs {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return false, errors.Errorf("IP %s is not valid", ipStr)
+		}
+		expectedIPs = append(expectedIPs, ip.String())
+	}
+	expectedIPs = funk.UniqString(expectedIPs)
+	currentIPs := make([]string, 0, len(crt.IPAddresses))
+	for _, ip := range crt.IPAddresses {
+		currentIPs = append(currentIPs, ip.String())
+	}
+	currentIPs = funk.UniqString(currentIPs)
+	sort.Strings(expectedIPs)
+	sort.Strings(currentIPs)
+
+	if !funk.Equal(expectedDNSNames, currentDNSNames) || !funk.Equal(expectedIPs, currentIPs) {
+		log.Debugf("Certificate %s must be reissued, alt names changed from %v %v to %v %v", crt.Subject.CommonName, currentDNSNames, currentIPs, expectedDNSNames, expectedIPs)
+		return true, nil
+	}
+
+	return false, nil
+}
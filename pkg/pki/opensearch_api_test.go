@@ -21,4 +21,17 @@ func TestApiPKI(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, crt.GetCertificate())
 	assert.NotEmpty(t, crt.PrivateKey)
+
+	// Check alt names
+	status, err := NeedReissueApiTls(crt.GoCert(), "test", []string{"opensearch.test.local"}, []string{"10.0.0.1"}, testLogEntry)
+	assert.NoError(t, err)
+	assert.False(t, status)
+
+	status, err = NeedReissueApiTls(crt.GoCert(), "test", []string{"opensearch.test.local", "test-os.default.svc"}, []string{"10.0.0.1"}, testLogEntry)
+	assert.NoError(t, err)
+	assert.True(t, status)
+
+	status, err = NeedReissueApiTls(crt.GoCert(), "test", []string{"opensearch.test.local"}, []string{"10.0.0.2"}, testLogEntry)
+	assert.NoError(t, err)
+	assert.True(t, status)
 }